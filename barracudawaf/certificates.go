@@ -0,0 +1,569 @@
+package barracudawaf
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// barracudaWAFCertificateUpload : certificate, private key and intermediate certificates
+// uploaded to the WAF, validated before they are sent.
+type barracudaWAFCertificateUpload struct {
+	leaf          *x509.Certificate // certificate issued for the service, nil if not checked
+	certificate   []byte            // PEM certificate or PKCS #12 bundle holding the key
+	key           []byte            // PEM private key, empty for PKCS #12 bundles
+	intermediates [][]byte          // PEM intermediate certificates, in chain order
+	pendingKey    bool              // the certificate is signed for a key pending on the WAF
+}
+
+// hashBarracudaWAFSecret : state function storing the SHA-256 hash of key material instead
+// of the key material itself.
+func hashBarracudaWAFSecret(v interface{}) string {
+	value, _ := v.(string)
+	if value == "" {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// decodeBarracudaWAFContent : returns the content of a certificate or key given as PEM text,
+// base64 encoded or as the raw content of a PKCS #12 bundle.
+func decodeBarracudaWAFContent(content []byte) []byte {
+	if bytes.Contains(content, []byte("-----BEGIN")) {
+		return content
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(string(content)), ""))
+	if err != nil {
+		return content
+	}
+
+	return decoded
+}
+
+// readBarracudaWAFContent : returns the content of the attribute, or of the file named by the
+// attribute with the _file suffix when it is set.
+func readBarracudaWAFContent(get func(string) interface{}, attribute string) ([]byte, error) {
+	if path := get(attribute + "_file").(string); path != "" {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s_file: %v", attribute, err)
+		}
+
+		return decodeBarracudaWAFContent(content), nil
+	}
+
+	return decodeBarracudaWAFContent([]byte(get(attribute).(string))), nil
+}
+
+// hashBarracudaWAFFile : returns the SHA-256 hash of the content of the file, or an empty
+// hash when no path is set.
+func hashBarracudaWAFFile(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("unable to read %s: %v", path, err)
+	}
+
+	return hashBarracudaWAFSecret(string(content)), nil
+}
+
+// readBarracudaWAFCertificateFile : returns the first certificate of a PEM file, or nil for a
+// PKCS #12 bundle which cannot be read without its password.
+func readBarracudaWAFCertificateFile(path string) (*x509.Certificate, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read signed_certificate_file: %v", err)
+	}
+
+	blocks, _, err := splitBarracudaWAFPEM(decodeBarracudaWAFContent(content))
+	if err != nil || len(blocks) == 0 {
+		return nil, nil
+	}
+
+	return x509.ParseCertificate(blocks[0].Bytes)
+}
+
+// expandBarracudaWAFCertificateUpload : reads the certificate, the private key and the
+// intermediate certificates of the resource and validates them, see
+// parseBarracudaWAFCertificateUpload. The getter is the Get function of the resource data
+// or of the resource diff, the client is only used to fetch the certificate request.
+func expandBarracudaWAFCertificateUpload(
+	ctx context.Context,
+	client *waf.Client,
+	get func(string) interface{},
+) (*barracudaWAFCertificateUpload, error) {
+	var pendingKey crypto.PublicKey
+	if name := get("certificate_request_name").(string); name != "" {
+		request, err := client.GetCertificate(ctx, waf.CertificateRequests, name)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch the certificate request (%s): %v", name, err)
+		}
+
+		if request.CertificateRequest == "" {
+			return nil, fmt.Errorf(
+				"the key of the certificate request (%s) is already assigned to a signed certificate, "+
+					"replace the certificate request to sign a new certificate",
+				name,
+			)
+		}
+
+		if pendingKey, err = parseBarracudaWAFCertificateRequestKey(request.CertificateRequest); err != nil {
+			return nil, fmt.Errorf("invalid certificate request (%s): %v", name, err)
+		}
+	}
+
+	certificate, err := readBarracudaWAFContent(get, "signed_certificate")
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := readBarracudaWAFContent(get, "certificate_key")
+	if err != nil {
+		return nil, err
+	}
+
+	var intermediates [][]byte
+	for _, intermediate := range expandStringList(get("intermediary_certificates").([]interface{})) {
+		intermediates = append(intermediates, decodeBarracudaWAFContent([]byte(intermediate)))
+	}
+
+	return parseBarracudaWAFCertificateUpload(certificate, key, intermediates, get("certificate_password").(string), pendingKey)
+}
+
+// parseBarracudaWAFCertificateRequestKey : returns the public key of a PEM certificate request.
+func parseBarracudaWAFCertificateRequestKey(content string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(decodeBarracudaWAFContent([]byte(content)))
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return nil, errors.New("no PEM certificate request found")
+	}
+
+	request, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return request.PublicKey, nil
+}
+
+// parseBarracudaWAFCertificateUpload : parses a PEM certificate, optionally followed by its
+// chain and key, or a PKCS #12 bundle. It checks that the private key matches the certificate
+// and that each certificate of the chain is issued by the next one. A PEM certificate signed
+// for a key pending on the WAF is checked against the public key of its request instead.
+func parseBarracudaWAFCertificateUpload(
+	certificate []byte,
+	key []byte,
+	intermediates [][]byte,
+	password string,
+	pendingKey crypto.PublicKey,
+) (*barracudaWAFCertificateUpload, error) {
+	if len(certificate) == 0 {
+		return nil, errors.New("one of signed_certificate or signed_certificate_file is required")
+	}
+
+	upload := &barracudaWAFCertificateUpload{certificate: certificate, pendingKey: pendingKey != nil}
+
+	if pendingKey != nil && !bytes.Contains(certificate, []byte("-----BEGIN")) {
+		return nil, errors.New("invalid signed_certificate: expected a PEM certificate signed for the certificate request")
+	}
+
+	var certificates []*x509.Certificate
+	var keyBlock *pem.Block
+
+	if bytes.Contains(certificate, []byte("-----BEGIN")) {
+		certificateBlocks, certificateKeyBlock, err := splitBarracudaWAFPEM(certificate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signed_certificate: %v", err)
+		}
+
+		if len(certificateBlocks) == 0 {
+			return nil, errors.New("invalid signed_certificate: no PEM certificate found")
+		}
+
+		if certificates, err = parseBarracudaWAFCertificates(certificateBlocks); err != nil {
+			return nil, fmt.Errorf("invalid signed_certificate: %v", err)
+		}
+
+		keyBlock = certificateKeyBlock
+		if len(key) > 0 {
+			if _, keyBlock, err = splitBarracudaWAFPEM(key); err != nil || keyBlock == nil {
+				return nil, errors.New("invalid certificate_key: no PEM private key found")
+			}
+		}
+
+		switch {
+		case pendingKey != nil && keyBlock != nil:
+			return nil, errors.New("the private key of a certificate signed for a certificate request stays on the WAF, it cannot be uploaded")
+		case pendingKey == nil && keyBlock == nil:
+			return nil, errors.New("one of certificate_key or certificate_key_file is required with a PEM certificate")
+		case keyBlock != nil:
+			upload.key = pem.EncodeToMemory(keyBlock)
+		}
+
+		// the chain of the PEM certificate is uploaded as intermediate certificates
+		upload.certificate = pem.EncodeToMemory(certificateBlocks[0])
+		for _, block := range certificateBlocks[1:] {
+			upload.intermediates = append(upload.intermediates, pem.EncodeToMemory(block))
+		}
+	} else {
+		privateKey, leaf, chain, err := pkcs12.DecodeChain(certificate, password)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signed_certificate, expected a PEM certificate or a PKCS #12 bundle: %v", err)
+		}
+
+		keyDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signed_certificate: unsupported private key in the PKCS #12 bundle: %v", err)
+		}
+		keyBlock = &pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}
+
+		certificates = append([]*x509.Certificate{leaf}, chain...)
+
+		// the chain of the bundle is uploaded with it, only the intermediate certificates
+		// configured with the resource are checked to follow the certificate
+		certificates = []*x509.Certificate{barracudaWAFBundleLeaf(certificates, keyBlock)}
+	}
+
+	upload.leaf = certificates[0]
+
+	if pendingKey != nil {
+		if !barracudaWAFPublicKeyEqual(upload.leaf.PublicKey, pendingKey) {
+			return nil, fmt.Errorf("the certificate (%s) is not signed for the key of the certificate request", upload.leaf.Subject)
+		}
+	} else if err := checkBarracudaWAFKeyMatches(upload.leaf, keyBlock); err != nil {
+		return nil, err
+	}
+
+	for _, intermediate := range intermediates {
+		blocks, _, err := splitBarracudaWAFPEM(intermediate)
+		if err != nil || len(blocks) == 0 {
+			return nil, errors.New("invalid intermediary_certificates: expected PEM certificates")
+		}
+
+		chain, err := parseBarracudaWAFCertificates(blocks)
+		if err != nil {
+			return nil, fmt.Errorf("invalid intermediary_certificates: %v", err)
+		}
+
+		for _, block := range blocks {
+			upload.intermediates = append(upload.intermediates, pem.EncodeToMemory(block))
+		}
+		certificates = append(certificates, chain...)
+	}
+
+	if len(upload.intermediates) > 0 {
+		if err := checkBarracudaWAFChainOrder(certificates); err != nil {
+			return nil, err
+		}
+	}
+
+	return upload, nil
+}
+
+// hydrate : sets the base64 encoded content of the upload on the certificate.
+func (u *barracudaWAFCertificateUpload) hydrate(certificate *waf.Certificate) *waf.Certificate {
+	if certificate.CertificateType == "" {
+		certificate.CertificateType = "PKCS12 Token"
+		if len(u.key) > 0 || u.pendingKey {
+			certificate.CertificateType = "PEM Certificate"
+		}
+	}
+
+	if u.pendingKey {
+		certificate.AssignAssociatedKey = "Yes"
+	}
+
+	certificate.SignedCertificate = base64.StdEncoding.EncodeToString(u.certificate)
+	certificate.CertificateKey = ""
+	if len(u.key) > 0 {
+		certificate.CertificateKey = base64.StdEncoding.EncodeToString(u.key)
+	}
+
+	certificate.IntermediaryCertificates = nil
+	for _, intermediate := range u.intermediates {
+		certificate.IntermediaryCertificates = append(
+			certificate.IntermediaryCertificates,
+			base64.StdEncoding.EncodeToString(intermediate),
+		)
+	}
+
+	return certificate
+}
+
+// splitBarracudaWAFPEM : returns the certificates and the private key of PEM content.
+func splitBarracudaWAFPEM(content []byte) ([]*pem.Block, *pem.Block, error) {
+	var certificates []*pem.Block
+	var key *pem.Block
+
+	for {
+		var block *pem.Block
+		block, content = pem.Decode(content)
+		if block == nil {
+			break
+		}
+
+		switch {
+		case block.Type == "CERTIFICATE":
+			certificates = append(certificates, block)
+		case strings.HasSuffix(block.Type, "PRIVATE KEY"):
+			if key != nil {
+				return nil, nil, errors.New("more than one private key found")
+			}
+			key = block
+		}
+	}
+
+	return certificates, key, nil
+}
+
+// parseBarracudaWAFCertificates : parses PEM certificates.
+func parseBarracudaWAFCertificates(blocks []*pem.Block) ([]*x509.Certificate, error) {
+	certificates := make([]*x509.Certificate, 0, len(blocks))
+	for _, block := range blocks {
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certificates = append(certificates, certificate)
+	}
+
+	return certificates, nil
+}
+
+// parseBarracudaWAFPrivateKey : parses a PKCS #8, PKCS #1 or SEC 1 private key. Encrypted
+// keys are decrypted by the WAF and return a nil key.
+func parseBarracudaWAFPrivateKey(block *pem.Block) (crypto.Signer, error) {
+	if block.Type == "ENCRYPTED PRIVATE KEY" || strings.Contains(block.Headers["Proc-Type"], "ENCRYPTED") {
+		return nil, nil
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		if signer, ok := key.(crypto.Signer); ok {
+			return signer, nil
+		}
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	return nil, errors.New("invalid certificate_key: unsupported private key")
+}
+
+// checkBarracudaWAFKeyMatches : checks that the private key is the key of the certificate.
+func checkBarracudaWAFKeyMatches(certificate *x509.Certificate, keyBlock *pem.Block) error {
+	key, err := parseBarracudaWAFPrivateKey(keyBlock)
+	if err != nil {
+		return err
+	}
+
+	if key == nil {
+		log.Printf("[WARN] The private key of certificate (%s) is encrypted, unable to check that it matches", certificate.Subject)
+		return nil
+	}
+
+	if !barracudaWAFPublicKeyEqual(certificate.PublicKey, key.Public()) {
+		return fmt.Errorf("the private key does not match the certificate (%s)", certificate.Subject)
+	}
+
+	return nil
+}
+
+// barracudaWAFPublicKeyEqual : reports whether the public keys are the same.
+func barracudaWAFPublicKeyEqual(publicKey crypto.PublicKey, other crypto.PublicKey) bool {
+	key, ok := publicKey.(interface{ Equal(crypto.PublicKey) bool })
+	return ok && key.Equal(other)
+}
+
+// checkBarracudaWAFChainOrder : checks that each certificate of the chain is issued by the
+// certificate following it.
+func checkBarracudaWAFChainOrder(chain []*x509.Certificate) error {
+	for i := 0; i+1 < len(chain); i++ {
+		if err := chain[i].CheckSignatureFrom(chain[i+1]); err != nil {
+			return fmt.Errorf(
+				"the certificate chain is out of order, certificate (%s) is not issued by the next certificate (%s): %v",
+				chain[i].Subject,
+				chain[i+1].Subject,
+				err,
+			)
+		}
+	}
+
+	return nil
+}
+
+// barracudaWAFBundleLeaf : returns the certificate of the private key of a PKCS #12 bundle,
+// the certificates of a bundle are not ordered.
+func barracudaWAFBundleLeaf(certificates []*x509.Certificate, keyBlock *pem.Block) *x509.Certificate {
+	key, err := parseBarracudaWAFPrivateKey(keyBlock)
+	if err != nil || key == nil {
+		return certificates[0]
+	}
+
+	for _, certificate := range certificates {
+		if barracudaWAFPublicKeyEqual(certificate.PublicKey, key.Public()) {
+			return certificate
+		}
+	}
+
+	return certificates[0]
+}
+
+// barracudaWAFFingerprint : returns the SHA-256 fingerprint of a certificate, as lower case
+// hexadecimal without separators.
+func barracudaWAFFingerprint(certificate *x509.Certificate) string {
+	sum := sha256.Sum256(certificate.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeBarracudaWAFFingerprint : removes the separators and the case of a fingerprint
+// reported by the WAF, e.g. "9C:0F:...".
+func normalizeBarracudaWAFFingerprint(fingerprint string) string {
+	return strings.ToLower(strings.NewReplacer(":", "", " ", "").Replace(fingerprint))
+}
+
+// parseBarracudaWAFPEMBundle : parses the PEM certificates of a bundle, PEM text or base64
+// encoded. A bundle holds certificates only, private keys are rejected.
+func parseBarracudaWAFPEMBundle(content string) ([]*pem.Block, []*x509.Certificate, error) {
+	blocks, key, err := splitBarracudaWAFPEM(decodeBarracudaWAFContent([]byte(content)))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if key != nil {
+		return nil, nil, errors.New("the bundle must not hold a private key")
+	}
+
+	if len(blocks) == 0 {
+		return nil, nil, errors.New("no PEM certificate found")
+	}
+
+	certificates, err := parseBarracudaWAFCertificates(blocks)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return blocks, certificates, nil
+}
+
+// encodeBarracudaWAFPEMBundle : returns the base64 encoded PEM certificates of a bundle, as
+// uploaded to the WAF.
+func encodeBarracudaWAFPEMBundle(blocks []*pem.Block) []string {
+	encoded := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		encoded = append(encoded, base64.StdEncoding.EncodeToString(pem.EncodeToMemory(block)))
+	}
+
+	return encoded
+}
+
+// suppressBarracudaWAFEquivalentBundle : suppresses the diff of PEM bundles holding the same
+// certificates in the same order, whatever their encoding.
+func suppressBarracudaWAFEquivalentBundle(k, old, new string, d *schema.ResourceData) bool {
+	_, oldCertificates, err := parseBarracudaWAFPEMBundle(old)
+	if err != nil {
+		return false
+	}
+
+	_, newCertificates, err := parseBarracudaWAFPEMBundle(new)
+	if err != nil || len(oldCertificates) != len(newCertificates) {
+		return false
+	}
+
+	for i := range oldCertificates {
+		if barracudaWAFFingerprint(oldCertificates[i]) != barracudaWAFFingerprint(newCertificates[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// barracudaWAFCertificateDetailsSchema : computed details of the certificates of a bundle.
+func barracudaWAFCertificateDetailsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"subject":            {Type: schema.TypeString, Computed: true},
+				"issuer":             {Type: schema.TypeString, Computed: true},
+				"expiry":             {Type: schema.TypeString, Computed: true},
+				"sha256_fingerprint": {Type: schema.TypeString, Computed: true},
+			},
+		},
+		Description: "Subject, issuer, expiry and SHA-256 fingerprint of the certificates, in order",
+	}
+}
+
+// flattenBarracudaWAFCertificateDetails : returns the details of the certificates of a bundle
+// and the earliest of their expiries.
+func flattenBarracudaWAFCertificateDetails(certificates []*x509.Certificate) ([]interface{}, string) {
+	var details []interface{}
+	var expiry time.Time
+
+	for _, certificate := range certificates {
+		details = append(details, map[string]interface{}{
+			"subject":            certificate.Subject.String(),
+			"issuer":             certificate.Issuer.String(),
+			"expiry":             certificate.NotAfter.UTC().Format(time.RFC3339),
+			"sha256_fingerprint": barracudaWAFFingerprint(certificate),
+		})
+
+		if expiry.IsZero() || certificate.NotAfter.Before(expiry) {
+			expiry = certificate.NotAfter
+		}
+	}
+
+	return details, expiry.UTC().Format(time.RFC3339)
+}
+
+// setNewBarracudaWAFCertificateDetails : plans the details and the expiry of the certificates
+// of the bundle attribute when it changes.
+func setNewBarracudaWAFCertificateDetails(d *schema.ResourceDiff, attribute string) error {
+	if !d.HasChange(attribute) {
+		return nil
+	}
+
+	if !d.NewValueKnown(attribute) {
+		if err := d.SetNewComputed("certificate_details"); err != nil {
+			return err
+		}
+		return d.SetNewComputed("expiry")
+	}
+
+	_, certificates, err := parseBarracudaWAFPEMBundle(d.Get(attribute).(string))
+	if err != nil {
+		return fmt.Errorf("invalid %s: %v", attribute, err)
+	}
+
+	details, expiry := flattenBarracudaWAFCertificateDetails(certificates)
+	if err := d.SetNew("certificate_details", details); err != nil {
+		return err
+	}
+	return d.SetNew("expiry", expiry)
+}
@@ -0,0 +1,261 @@
+package barracudawaf
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// testBarracudaWAFCertificate : PEM certificate and private key generated for the tests.
+type testBarracudaWAFCertificate struct {
+	certificate *x509.Certificate
+	key         *ecdsa.PrivateKey
+	pem         []byte
+	keyPEM      []byte
+}
+
+// newTestBarracudaWAFCertificate : generates a certificate issued by the parent certificate, or a
+// self signed certificate when the parent is nil.
+func newTestBarracudaWAFCertificate(t *testing.T, commonName string, parent *testBarracudaWAFCertificate) *testBarracudaWAFCertificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  parent == nil || strings.HasSuffix(commonName, "CA"),
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+
+	issuer, signer := template, key
+	if parent != nil {
+		issuer, signer = parent.certificate, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, &key.PublicKey, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testBarracudaWAFCertificate{
+		certificate: certificate,
+		key:         key,
+		pem:         pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:      pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+// certificateRequest : returns the PEM certificate request of the key of the certificate.
+func (c *testBarracudaWAFCertificate) certificateRequest(t *testing.T) string {
+	t.Helper()
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: c.certificate.Subject}, c.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}))
+}
+
+func TestParseBarracudaWAFCertificateUpload(t *testing.T) {
+	root := newTestBarracudaWAFCertificate(t, "Demo Root CA", nil)
+	intermediate := newTestBarracudaWAFCertificate(t, "Demo Intermediate CA", root)
+	leaf := newTestBarracudaWAFCertificate(t, "www.example.com", intermediate)
+	other := newTestBarracudaWAFCertificate(t, "www.example.org", intermediate)
+
+	join := func(contents ...[]byte) []byte {
+		var joined []byte
+		for _, content := range contents {
+			joined = append(joined, content...)
+		}
+		return joined
+	}
+
+	cases := map[string]struct {
+		certificate   []byte
+		key           []byte
+		intermediates [][]byte
+		expected      string // error, if any
+		chain         int
+	}{
+		"separate key": {
+			certificate: leaf.pem,
+			key:         leaf.keyPEM,
+		},
+		"key in the certificate": {
+			certificate: join(leaf.pem, leaf.keyPEM),
+		},
+		"chain in the certificate": {
+			certificate: join(leaf.pem, intermediate.pem, root.pem),
+			key:         leaf.keyPEM,
+			chain:       2,
+		},
+		"intermediate certificates": {
+			certificate:   leaf.pem,
+			key:           leaf.keyPEM,
+			intermediates: [][]byte{intermediate.pem, root.pem},
+			chain:         2,
+		},
+		"missing key": {
+			certificate: leaf.pem,
+			expected:    "certificate_key",
+		},
+		"key of another certificate": {
+			certificate: leaf.pem,
+			key:         other.keyPEM,
+			expected:    "does not match",
+		},
+		"chain out of order": {
+			certificate:   leaf.pem,
+			key:           leaf.keyPEM,
+			intermediates: [][]byte{root.pem, intermediate.pem},
+			expected:      "out of order",
+		},
+		"not a certificate": {
+			certificate: []byte("not a certificate"),
+			expected:    "PKCS #12",
+		},
+	}
+
+	for name, c := range cases {
+		upload, err := parseBarracudaWAFCertificateUpload(c.certificate, c.key, c.intermediates, "", nil)
+
+		if c.expected != "" {
+			if err == nil || !strings.Contains(err.Error(), c.expected) {
+				t.Errorf("%s: expected an error about %s, got %v", name, c.expected, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+
+		if barracudaWAFFingerprint(upload.leaf) != barracudaWAFFingerprint(leaf.certificate) {
+			t.Errorf("%s: expected the leaf to be %s, got %s", name, leaf.certificate.Subject, upload.leaf.Subject)
+		}
+
+		if string(upload.certificate) != string(leaf.pem) || string(upload.key) != string(leaf.keyPEM) {
+			t.Errorf("%s: expected the certificate and its key to be uploaded separately", name)
+		}
+
+		if len(upload.intermediates) != c.chain {
+			t.Errorf("%s: expected %d intermediate certificates, got %d", name, c.chain, len(upload.intermediates))
+		}
+	}
+}
+
+func TestParseBarracudaWAFCertificateUpload_pendingKey(t *testing.T) {
+	root := newTestBarracudaWAFCertificate(t, "Demo Root CA", nil)
+	leaf := newTestBarracudaWAFCertificate(t, "www.example.com", root)
+	other := newTestBarracudaWAFCertificate(t, "www.example.org", root)
+
+	pendingKey, err := parseBarracudaWAFCertificateRequestKey(leaf.certificateRequest(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	upload, err := parseBarracudaWAFCertificateUpload(leaf.pem, nil, [][]byte{root.pem}, "", pendingKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	certificate := upload.hydrate(&waf.Certificate{Name: "DemoSignedCert"})
+	if certificate.AssignAssociatedKey != "Yes" || certificate.CertificateKey != "" || certificate.CertificateType != "PEM Certificate" {
+		t.Errorf("expected the pending key to be assigned to the certificate, got %+v", certificate)
+	}
+
+	if _, err := parseBarracudaWAFCertificateUpload(other.pem, nil, nil, "", pendingKey); err == nil || !strings.Contains(err.Error(), "not signed for the key") {
+		t.Errorf("expected the certificate of another key to be rejected, got %v", err)
+	}
+
+	if _, err := parseBarracudaWAFCertificateUpload(leaf.pem, leaf.keyPEM, nil, "", pendingKey); err == nil || !strings.Contains(err.Error(), "stays on the WAF") {
+		t.Errorf("expected the private key not to be uploaded, got %v", err)
+	}
+}
+
+// testBarracudaWAFPBES2Bundle : PKCS #12 bundle encrypted with PBES2 and AES-256, protected by
+// the password secret@123
+const testBarracudaWAFPBES2Bundle = "MIIEHAIBAzCCA9IGCSqGSIb3DQEHAaCCA8MEggO/MIIDuzCCAnIGCSqGSIb3DQEHBqCCAmMwggJfAgEAMIICWAYJKoZIhvcNAQcBMFcGCSqGSIb3DQEFDTBKMCkGCSqGSIb3DQEFDDAcBAh/IKXNVKFCbwICCAAwDAYIKoZIhvcNAgkFADAdBglghkgBZQMEASoEEGEkB+MxP7ERuI2tviB7GfyAggHwwyjV+wj+5OvFQwVU6kXmZbQXvl3pjUBfx0vYMaeAm1aL9GlLACzYTo0a+9GBSuBZOa/2z2E91Vn/xT/3GKZlUScNd+TemSXd+XkmzcJjvmsNtMjBIKVlDizqMesdyz4h/D/VmnLlbkkhDA2c2DpdR25SFT4SHJr0We/i3m5GDbL6093CiatT6ReOII7A9S4Vzqwkkk99pNq+CtJ1gNrRZBTM2+DaJKkyhjMP1Nuzu1ItnY8t//JwHtcB+2nPE3l6XvjKLjlL6hb3z4coScvrysC2VUJkOtrHjtbEVx+9kjQqctXLXZxWa2LubJoQKVXYBw309y6MtJ/m2cGFvnqbGZWyM6YBkH4v3eIGduNq6b42yugRoYYzxJsqjRkJ6ScdMqsY0t+nWGFiIeJs8v3xWcsHHVTbgrcL5yto1iSsBHADegVPhNeZJs/iGZqj4KMpFSbY6xAcDCEFshtCgyAe1MMTUDGwCsosVzS56OovqxZTCVN+Hp2T+NaZu43QvOwD1nBjrMAVklydfPGt6DCNAgrm0elZ/ZpxYV1Xb42tEczPIK/1USfvT1tVi2/QJIXBSaw7RhF8l5H/7JA75kaz0+J75u5yk60KNcIVIOQMuHVgmeQsDNFUyyCXXOH5LK/ISS6gZ65/H9rSE6JmebcrwjCCAUEGCSqGSIb3DQEHAaCCATIEggEuMIIBKjCCASYGCyqGSIb3DQEMCgECoIHvMIHsMFcGCSqGSIb3DQEFDTBKMCkGCSqGSIb3DQEFDDAcBAgkxXkMATFk+AICCAAwDAYIKoZIhvcNAgkFADAdBglghkgBZQMEASoEEGbi4y6KwKLLyi47AMmTciQEgZDF/uUajZDnorPqMRoXjJWGEbiOfSoMnWRRrOvoStv9wZ6EZAeta9ZCsnqhJ7QbvyneMDtLwcjtoSXvZqe2vTeZ8F2Gs9T/ddrWTB1IQhaWOBlcqiaLDpmAQry0O87ztblnfQJWx9nq+j7ecCAc6plHtTBH7rfKTxfDsqsXgCtqaAJB6Ajg7eYzu/3HTJvdcSwxJTAjBgkqhkiG9w0BCRUxFgQUt30Zr1muXxh4d5ucF+8quLTTtBAwQTAxMA0GCWCGSAFlAwQCAQUABCAW8rrpsJDPP5ktxdH17Qe78x0KbVtU1GMj4YYXh8y9mwQIRJZMNFQikI0CAggA"
+
+func TestParseBarracudaWAFCertificateUpload_pkcs12Bundle(t *testing.T) {
+	bundles := map[string]string{
+		"SHA-256 MAC": testBarracudaWAFPKCS12Bundle,
+		"PBES2":       testBarracudaWAFPBES2Bundle,
+	}
+
+	for name, content := range bundles {
+		bundle := decodeBarracudaWAFContent([]byte(content))
+
+		upload, err := parseBarracudaWAFCertificateUpload(bundle, nil, nil, "secret@123", nil)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+
+		if upload.leaf == nil || string(upload.certificate) != string(bundle) {
+			t.Errorf("%s: expected the bundle to be checked and uploaded as is", name)
+		}
+
+		certificate := upload.hydrate(hydrateBarracudaWAFSignedCertificateResource(
+			schema.TestResourceDataRaw(t, resourceCudaWAFSignedCertificate().Schema, map[string]interface{}{"name": "DemoSignedCert"}),
+		))
+		if certificate.CertificateType != "PKCS12 Token" || certificate.CertificateKey != "" {
+			t.Errorf("%s: expected a PKCS #12 token without key, got %s", name, certificate.CertificateType)
+		}
+
+		if _, err := parseBarracudaWAFCertificateUpload(bundle, nil, nil, "wrong", nil); err == nil || !strings.Contains(err.Error(), "invalid signed_certificate") {
+			t.Errorf("%s: expected the wrong password to be rejected, got %v", name, err)
+		}
+	}
+}
+
+func TestHashBarracudaWAFSecret(t *testing.T) {
+	if hash := hashBarracudaWAFSecret(""); hash != "" {
+		t.Errorf("expected an empty secret to stay empty, got %s", hash)
+	}
+
+	hash := hashBarracudaWAFSecret("secret@123")
+	if len(hash) != 64 || strings.Contains(hash, "secret") {
+		t.Errorf("expected the SHA-256 hash of the secret, got %s", hash)
+	}
+}
+
+func TestDecodeBarracudaWAFContent(t *testing.T) {
+	leaf := newTestBarracudaWAFCertificate(t, "www.example.com", nil)
+
+	encoded := base64.StdEncoding.EncodeToString(leaf.pem)
+	for _, content := range []string{string(leaf.pem), encoded, encoded[:40] + "\n" + encoded[40:]} {
+		if decoded := decodeBarracudaWAFContent([]byte(content)); string(decoded) != string(leaf.pem) {
+			t.Errorf("expected %q to be decoded to the PEM certificate", content)
+		}
+	}
+}
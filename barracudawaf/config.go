@@ -1,8 +1,16 @@
 package barracudawaf
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"io/ioutil"
 	"log"
+	"strings"
+	"time"
+
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
 )
 
 // Config : container for Barracuda WAF session
@@ -11,32 +19,144 @@ type Config struct {
 	Username  string
 	Password  string
 	AdminPort string
+	APIToken  string // token used instead of logging in with Username and Password
+	TokenFile string // path to a file holding the token used instead of logging in
+
+	Insecure   bool   // skips the verification of the admin API certificate
+	CACertPEM  string // PEM encoded CA certificates trusted for the admin API
+	CACertFile string // path to a PEM encoded CA bundle trusted for the admin API
+	ClientCert string // PEM encoded client certificate for mutual TLS
+	ClientKey  string // PEM encoded private key of the client certificate
+
+	MaxRetries   int           // retries of requests failing with a transient error
+	RetryMinWait time.Duration // wait before the first retry
+	RetryMaxWait time.Duration // upper bound of the wait between retries
+
+	MaxConcurrentRequests int     // requests sent to the WAF at the same time, 0 for no limit
+	RequestsPerSecond     float64 // rate the requests are sent at, 0 for no limit
 }
 
 // Client : Barracuda WAF Client for REST API calls for resource crud
-func (c *Config) Client() (*BarracudaWAF, error) {
+func (c *Config) Client(ctx context.Context) (*waf.Client, error) {
+
+	hasCredentials := c.Username != "" && c.Password != ""
+	hasToken := c.APIToken != "" || c.TokenFile != ""
 
-	if c.IPAddress != "" && c.Username != "" && c.Password != "" && c.AdminPort != "" {
+	if c.IPAddress != "" && c.AdminPort != "" && (hasCredentials || hasToken) {
 		log.Println("[INFO] Initializing Barracuda WAF connection")
-		var client *BarracudaWAF
+		tlsConfig, err := c.tlsConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		if c.MaxRetries < 0 || c.RetryMinWait < 0 || c.RetryMinWait > c.RetryMaxWait {
+			return nil, fmt.Errorf(
+				"max_retries must not be negative and retry_min_wait must be between 0 and retry_max_wait",
+			)
+		}
+
+		if c.MaxConcurrentRequests < 0 || c.RequestsPerSecond < 0 {
+			return nil, fmt.Errorf("max_concurrent_requests and requests_per_second must not be negative")
+		}
+
+		client := waf.NewClient(c.IPAddress, c.AdminPort, c.Username, c.Password)
+		client.Transport.TLSClientConfig = tlsConfig
+		client.MaxRetries = c.MaxRetries
+		client.RetryMinWait = c.RetryMinWait
+		client.RetryMaxWait = c.RetryMaxWait
+		client.MaxConcurrentRequests = c.MaxConcurrentRequests
+		client.RequestsPerSecond = c.RequestsPerSecond
 
-		client = NewSession(c.IPAddress, c.AdminPort, c.Username, c.Password)
-		client, err := c.validateConnection(client)
+		if c.MaxConcurrentRequests > 0 {
+			client.Transport.MaxIdleConnsPerHost = c.MaxConcurrentRequests
+		}
+
+		if hasToken {
+			token, err := c.token()
+			if err != nil {
+				return nil, err
+			}
+
+			log.Println("[INFO] Using the configured token for the Barracuda WAF session")
+			return client.SetToken(token), nil
+		}
+
+		err = c.validateConnection(ctx, client)
 		if err == nil {
 			return client, nil
 		}
 		return nil, err
 	}
-	return nil, fmt.Errorf("Barracuda WAF provider requires IPAddress, Username, Password and AdminPort")
+	return nil, fmt.Errorf(
+		"Barracuda WAF provider requires IPAddress, AdminPort and either Username and Password, APIToken or TokenFile",
+	)
+}
+
+// token : returns the configured API token, or the one stored in the token file
+func (c *Config) token() (string, error) {
+	if c.APIToken != "" {
+		return c.APIToken, nil
+	}
+
+	content, err := ioutil.ReadFile(c.TokenFile)
+	if err != nil {
+		return "", fmt.Errorf("unable to read token_file (%s): %v", c.TokenFile, err)
+	}
+
+	token := strings.TrimSpace(string(content))
+	if token == "" {
+		return "", fmt.Errorf("token_file (%s) is empty", c.TokenFile)
+	}
+
+	return token, nil
+}
+
+// tlsConfig : builds the TLS configuration used to connect to the admin API
+func (c *Config) tlsConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: c.Insecure,
+	}
+
+	if c.Insecure {
+		log.Println("[WARN] Verification of the Barracuda WAF certificate is disabled")
+	}
+
+	caCertPEM := []byte(c.CACertPEM)
+
+	if c.CACertFile != "" {
+		caCertFile, err := ioutil.ReadFile(c.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read ca_cert_file (%s): %v", c.CACertFile, err)
+		}
+		caCertPEM = append(append(caCertPEM, '\n'), caCertFile...)
+	}
+
+	if len(caCertPEM) > 0 {
+		rootCAs := x509.NewCertPool()
+		if !rootCAs.AppendCertsFromPEM(caCertPEM) {
+			return nil, fmt.Errorf("no valid PEM encoded CA certificate found in ca_cert_pem or ca_cert_file")
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	if c.ClientCert != "" || c.ClientKey != "" {
+		clientCert, err := tls.X509KeyPair([]byte(c.ClientCert), []byte(c.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("unable to load client_cert and client_key: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{clientCert}
+	}
+
+	return tlsConfig, nil
 }
 
-func (c *Config) validateConnection(client *BarracudaWAF) (*BarracudaWAF, error) {
+func (c *Config) validateConnection(ctx context.Context, client *waf.Client) error {
 
-	client, err := client.GetAuthToken()
+	err := client.Login(ctx)
 	if err != nil {
 		log.Printf("[ERROR] Connection to Barracuda WAF could not have been validated: %v ", err)
-		return client, err
+		return err
 	}
 
-	return client, nil
+	return nil
 }
@@ -0,0 +1,102 @@
+package barracudawaf
+
+import (
+	"context"
+	"log"
+
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var (
+	// dataSourceCertificateEndpoints : certificate stores, in lookup order
+	dataSourceCertificateEndpoints = []struct {
+		certificateType string
+		store           waf.CertificateStore
+	}{
+		{"signed", waf.SignedCertificates},
+		{"self_signed", waf.SelfSignedCertificates},
+		{"trusted_ca", waf.TrustedCACertificates},
+		{"trusted_server", waf.TrustedServerCertificates},
+	}
+)
+
+func dataSourceCudaWAFCertificate() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceCudaWAFCertificateRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": {Type: schema.TypeString, Required: true, Description: "Certificate Name"},
+			"type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice(dataSourceCertificateTypes(), false),
+				Description: "Certificate store to look the certificate up in, one of `signed`, `self_signed`, " +
+					"`trusted_ca` or `trusted_server`. All stores are searched when not set.",
+			},
+			"common_name":              {Type: schema.TypeString, Computed: true, Description: "Common Name"},
+			"expiry":                   {Type: schema.TypeString, Computed: true, Description: "Expiry"},
+			"serial":                   {Type: schema.TypeString, Computed: true, Description: "Serial"},
+			"issuer":                   {Type: schema.TypeString, Computed: true, Description: "Issuer"},
+			"subject":                  {Type: schema.TypeString, Computed: true, Description: "Subject"},
+			"sha256_fingerprint":       {Type: schema.TypeString, Computed: true, Description: "SHA-256 Fingerprint"},
+			"key_type":                 {Type: schema.TypeString, Computed: true, Description: "Key Type"},
+			"allow_private_key_export": {Type: schema.TypeString, Computed: true, Description: "Allow Private Key Export"},
+			"auto_renew_cert":          {Type: schema.TypeString, Computed: true, Description: "Auto Renew Certificate"},
+			"schedule_renewal_day":     {Type: schema.TypeString, Computed: true, Description: "Renew Certificate days"},
+		},
+
+		Description: "`barracudawaf_certificate` reads a `Certificate` stored on the Barracuda Web Application Firewall.",
+	}
+}
+
+// dataSourceCertificateTypes : types of the certificates, one per certificate store.
+func dataSourceCertificateTypes() []string {
+	types := make([]string, 0, len(dataSourceCertificateEndpoints))
+	for _, store := range dataSourceCertificateEndpoints {
+		types = append(types, store.certificateType)
+	}
+
+	return types
+}
+
+func dataSourceCudaWAFCertificateRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
+
+	name := d.Get("name").(string)
+	certificateType := d.Get("type").(string)
+	log.Println("[INFO] Fetching Barracuda WAF resource " + name)
+
+	for _, store := range dataSourceCertificateEndpoints {
+		if certificateType != "" && certificateType != store.certificateType {
+			continue
+		}
+
+		certificate, err := client.GetCertificate(ctx, store.store, name)
+
+		if waf.IsNotFound(err) {
+			continue
+		}
+
+		if err != nil {
+			log.Printf("[ERROR] Unable to Retrieve Barracuda WAF resource (%s) (%v) ", name, err)
+			return barracudaWAFDiagnostics(err, dataSourceCudaWAFCertificate().Schema, "Unable to Retrieve Barracuda WAF resource (%s)", name)
+		}
+
+		err = setBarracudaWAFResourceData(d, dataSourceCudaWAFCertificate().Schema, certificate, "name", "type")
+
+		if err != nil {
+			log.Printf("[ERROR] Unable to set Barracuda WAF resource (%s) (%v) ", name, err)
+			return barracudaWAFDiagnostics(err, dataSourceCudaWAFCertificate().Schema, "Unable to set Barracuda WAF resource (%s)", name)
+		}
+
+		d.Set("type", store.certificateType)
+		d.SetId(store.certificateType + "/" + name)
+		return nil
+	}
+
+	return diag.Errorf("Barracuda WAF resource (%s) not found on the system", name)
+}
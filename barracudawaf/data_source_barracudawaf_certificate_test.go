@@ -0,0 +1,112 @@
+package barracudawaf
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+var CERTIFICATE_DATA_SOURCE_READ = SELF_SIGNED_CERT_RESOURCE_CREATE + `
+data "barracudawaf_certificate" "demo_self_signed_cert_1" {
+    name = barracudawaf_self_signed_certificate.demo_self_signed_cert_1.name
+}
+`
+
+func TestAccBarracudaWAFCertificateDataSource_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAcctPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: CERTIFICATE_DATA_SOURCE_READ,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.barracudawaf_certificate.demo_self_signed_cert_1", "name", "DemoSelfSignedCert1"),
+					resource.TestCheckResourceAttr("data.barracudawaf_certificate.demo_self_signed_cert_1", "type", "self_signed"),
+					resource.TestCheckResourceAttr("data.barracudawaf_certificate.demo_self_signed_cert_1", "common_name", "barracuda.com"),
+					resource.TestCheckResourceAttrSet("data.barracudawaf_certificate.demo_self_signed_cert_1", "expiry"),
+				),
+			},
+		},
+	})
+}
+
+func TestBarracudaWAFCertificateDataSource_stores(t *testing.T) {
+	crud := newTestResourceCRUD(t, dataSourceCudaWAFCertificate())
+
+	crud.server.SetObject("signed-certificate/DemoCert", map[string]interface{}{"name": "DemoCert", "common-name": "signed.example.com"})
+	crud.server.SetObject("self-signed-certificate/DemoCert", map[string]interface{}{"name": "DemoCert", "common-name": "self.example.com"})
+	crud.server.SetObject("trusted-ca-certificate/DemoCA", map[string]interface{}{"name": "DemoCA", "common-name": "Demo CA"})
+	crud.server.SetObject("trusted-server-certificate/DemoServerCert", map[string]interface{}{"name": "DemoServerCert", "common-name": "backend.example.com"})
+
+	cases := map[string]struct {
+		config   map[string]interface{}
+		id       string
+		expected map[string]string
+	}{
+		"signed first": {
+			config:   map[string]interface{}{"name": "DemoCert"},
+			id:       "signed/DemoCert",
+			expected: map[string]string{"type": "signed", "common_name": "signed.example.com"},
+		},
+		"type": {
+			config:   map[string]interface{}{"name": "DemoCert", "type": "self_signed"},
+			id:       "self_signed/DemoCert",
+			expected: map[string]string{"type": "self_signed", "common_name": "self.example.com"},
+		},
+		"trusted CA": {
+			config:   map[string]interface{}{"name": "DemoCA"},
+			id:       "trusted_ca/DemoCA",
+			expected: map[string]string{"type": "trusted_ca", "common_name": "Demo CA"},
+		},
+		"trusted server": {
+			config:   map[string]interface{}{"name": "DemoServerCert"},
+			id:       "trusted_server/DemoServerCert",
+			expected: map[string]string{"type": "trusted_server", "common_name": "backend.example.com"},
+		},
+	}
+
+	for name, c := range cases {
+		d := crud.read("", c.config)
+
+		if d.Id() != c.id {
+			t.Errorf("%s: expected the ID to be %s, got %s", name, c.id, d.Id())
+		}
+		testCheckAttributes(t, d, c.expected)
+	}
+}
+
+func TestBarracudaWAFCertificateDataSource_notFound(t *testing.T) {
+	crud := newTestResourceCRUD(t, dataSourceCudaWAFCertificate())
+
+	crud.server.SetObject("trusted-ca-certificate/DemoCA", map[string]interface{}{"name": "DemoCA"})
+
+	cases := map[string]struct {
+		config   map[string]interface{}
+		expected string
+	}{
+		"any store":   {config: map[string]interface{}{"name": "DemoMissingCert"}, expected: "not found on the system"},
+		"other store": {config: map[string]interface{}{"name": "DemoCA", "type": "signed"}, expected: "not found on the system"},
+	}
+
+	for name, c := range cases {
+		d := schema.TestResourceDataRaw(t, crud.resource.Schema, c.config)
+
+		diags := crud.resource.ReadContext(context.Background(), d, crud.client)
+		if !diags.HasError() || !strings.Contains(diags[0].Summary, c.expected) {
+			t.Errorf("%s: expected the read to fail with %q, got %v", name, c.expected, diags)
+		}
+	}
+}
+
+func TestBarracudaWAFCertificateDataSource_invalidType(t *testing.T) {
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{"name": "DemoCert", "type": "client"})
+
+	diags := dataSourceCudaWAFCertificate().Validate(config)
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "expected type to be one of [signed self_signed trusted_ca trusted_server]") {
+		t.Errorf("expected the type to be rejected at plan time, got %v", diags)
+	}
+}
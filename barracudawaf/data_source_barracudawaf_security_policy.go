@@ -0,0 +1,47 @@
+package barracudawaf
+
+import (
+	"context"
+	"log"
+
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceCudaWAFSecurityPolicy() *schema.Resource {
+	dataSourceSchema := dataSourceBarracudaWAFSchema(resourceCudaWAFSecurityPolicies().Schema)
+	dataSourceSchema["name"] = &schema.Schema{Type: schema.TypeString, Required: true, Description: "Policy Name"}
+
+	return &schema.Resource{
+		ReadContext: dataSourceCudaWAFSecurityPolicyRead,
+
+		Schema: dataSourceSchema,
+
+		Description: "`barracudawaf_security_policy` reads a `Security Policy` configured on the Barracuda Web Application Firewall.",
+	}
+}
+
+func dataSourceCudaWAFSecurityPolicyRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
+
+	name := d.Get("name").(string)
+	log.Println("[INFO] Fetching Barracuda WAF resource " + name)
+
+	securityPolicy, err := client.GetSecurityPolicy(ctx, name)
+
+	if err != nil {
+		log.Printf("[ERROR] Unable to Retrieve Barracuda WAF resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, dataSourceCudaWAFSecurityPolicy().Schema, "Unable to Retrieve Barracuda WAF resource (%s)", name)
+	}
+
+	err = setBarracudaWAFResourceData(d, dataSourceCudaWAFSecurityPolicy().Schema, securityPolicy)
+
+	if err != nil {
+		log.Printf("[ERROR] Unable to set Barracuda WAF resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, dataSourceCudaWAFSecurityPolicy().Schema, "Unable to set Barracuda WAF resource (%s)", name)
+	}
+
+	d.SetId(name)
+	return nil
+}
@@ -0,0 +1,51 @@
+package barracudawaf
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var SECPOLICY_DATA_SOURCE_READ = SECPOLICY_RESOURCE_CREATE + `
+data "barracudawaf_security_policy" "demo_security_policy_1" {
+    name = barracudawaf_security_policies.demo_security_policy_1.name
+}
+`
+
+func TestAccBarracudaWAFSecurityPolicyDataSource_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAcctPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: SECPOLICY_DATA_SOURCE_READ,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.barracudawaf_security_policy.demo_security_policy_1", "name", "DemoPolicy1"),
+					resource.TestCheckResourceAttr("data.barracudawaf_security_policy.demo_security_policy_1", "id", "DemoPolicy1"),
+				),
+			},
+		},
+	})
+}
+
+func TestBarracudaWAFSecurityPolicyDataSource_read(t *testing.T) {
+	crud := newTestResourceCRUD(t, dataSourceCudaWAFSecurityPolicy())
+
+	crud.server.SetObject("security-policies/DemoPolicy1", map[string]interface{}{"name": "DemoPolicy1", "based-on": "Default"})
+
+	d := crud.read("", map[string]interface{}{"name": "DemoPolicy1"})
+
+	if d.Id() != "DemoPolicy1" {
+		t.Errorf("expected the ID to be DemoPolicy1, got %s", d.Id())
+	}
+	testCheckAttributes(t, d, map[string]string{"based_on": "Default"})
+
+	d = schema.TestResourceDataRaw(t, crud.resource.Schema, map[string]interface{}{"name": "DemoPolicy2"})
+	diags := crud.resource.ReadContext(context.Background(), d, crud.client)
+	if !diags.HasError() || !strings.Contains(diags[0].Detail, "not found on the Barracuda WAF") {
+		t.Errorf("expected the read to fail as the security policy is not found, got %v", diags)
+	}
+}
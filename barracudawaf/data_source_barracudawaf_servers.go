@@ -0,0 +1,88 @@
+package barracudawaf
+
+import (
+	"context"
+	"log"
+
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceCudaWAFServers() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceCudaWAFServersRead,
+
+		Schema: map[string]*schema.Schema{
+			"service": {Type: schema.TypeString, Required: true, Description: "Name of the service the servers belong to"},
+			"status":  {Type: schema.TypeString, Optional: true, Description: "Only list the servers with this Status"},
+			"names": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Names of the matching servers",
+			},
+			"servers": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Resource{Schema: dataSourceCudaWAFServersElemSchema()},
+				Description: "Matching servers",
+			},
+		},
+
+		Description: "`barracudawaf_servers` lists the `Servers` of a service on the Barracuda Web Application Firewall.",
+	}
+}
+
+// dataSourceCudaWAFServersElemSchema : attributes of the servers in the listing.
+func dataSourceCudaWAFServersElemSchema() map[string]*schema.Schema {
+	elemSchema := dataSourceBarracudaWAFSchema(resourceCudaWAFServers().Schema)
+	delete(elemSchema, "service_name")
+	delete(elemSchema, "parent")
+
+	for attribute, attributeSchema := range elemSchema {
+		if _, ok := attributeSchema.Elem.(*schema.Resource); ok {
+			delete(elemSchema, attribute)
+		}
+	}
+
+	return elemSchema
+}
+
+func dataSourceCudaWAFServersRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
+
+	service := d.Get("service").(string)
+	status := d.Get("status").(string)
+
+	log.Println("[INFO] Fetching Barracuda WAF servers of " + service)
+
+	resources, err := client.ListServers(ctx, service)
+
+	if err != nil {
+		log.Printf("[ERROR] Unable to Retrieve Barracuda WAF servers (%s) (%v) ", service, err)
+		return barracudaWAFDiagnostics(err, dataSourceCudaWAFServers().Schema, "Unable to Retrieve Barracuda WAF servers (%s)", service)
+	}
+
+	elemSchema := dataSourceCudaWAFServersElemSchema()
+	servers := make([]map[string]interface{}, 0)
+
+	for _, resource := range resources {
+		server := flattenBarracudaWAFResourceData(elemSchema, barracudaWAFParams(resource))
+
+		if _, ok := server["name"].(string); ok && (status == "" || server["status"] == status) {
+			servers = append(servers, server)
+		}
+	}
+
+	names := make([]string, 0, len(servers))
+	for _, server := range servers {
+		names = append(names, server["name"].(string))
+	}
+
+	d.Set("names", names)
+	d.Set("servers", servers)
+
+	d.SetId(service + "/servers/" + status)
+	return nil
+}
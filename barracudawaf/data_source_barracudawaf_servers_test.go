@@ -0,0 +1,80 @@
+package barracudawaf
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var SERVERS_DATA_SOURCE_READ = SERVER_RESOURCE_CREATE + `
+data "barracudawaf_servers" "demo_app_1" {
+    service = "DemoApp1"
+    status  = "In Service"
+
+    depends_on = [ barracudawaf_servers.demo_server_1 ]
+}
+`
+
+func TestAccBarracudaWAFServersDataSource_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAcctPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: SERVERS_DATA_SOURCE_READ,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.barracudawaf_servers.demo_app_1", "names.#", "1"),
+					resource.TestCheckResourceAttr("data.barracudawaf_servers.demo_app_1", "names.0", "DemoServer1"),
+					resource.TestCheckResourceAttr("data.barracudawaf_servers.demo_app_1", "servers.0.port", "80"),
+					resource.TestCheckResourceAttr("data.barracudawaf_servers.demo_app_1", "servers.0.identifier", "IP Address"),
+				),
+			},
+		},
+	})
+}
+
+func TestBarracudaWAFServersDataSource_filters(t *testing.T) {
+	crud := newTestResourceCRUD(t, dataSourceCudaWAFServers())
+
+	crud.server.SetObject("services/DemoApp1", nil)
+	crud.server.SetObject("services/DemoApp1/servers/DemoServer1", map[string]interface{}{"name": "DemoServer1", "status": "In Service", "port": "80"})
+	crud.server.SetObject("services/DemoApp1/servers/DemoServer2", map[string]interface{}{"name": "DemoServer2", "status": "Out of Service All", "port": "8080"})
+	crud.server.SetObject("services/DemoApp2", nil)
+	crud.server.SetObject("services/DemoApp2/servers/DemoServer3", map[string]interface{}{"name": "DemoServer3", "status": "In Service", "port": "80"})
+
+	cases := map[string]struct {
+		filters  map[string]interface{}
+		expected map[string]string
+	}{
+		"service": {
+			filters:  map[string]interface{}{"service": "DemoApp1"},
+			expected: map[string]string{"names.#": "2", "names.0": "DemoServer1", "names.1": "DemoServer2"},
+		},
+		"status": {
+			filters:  map[string]interface{}{"service": "DemoApp1", "status": "Out of Service All"},
+			expected: map[string]string{"names.#": "1", "names.0": "DemoServer2", "servers.0.port": "8080"},
+		},
+		"no match": {
+			filters:  map[string]interface{}{"service": "DemoApp2", "status": "Out of Service All"},
+			expected: map[string]string{"names.#": "0", "servers.#": "0"},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			testCheckAttributes(t, crud.read("", c.filters), c.expected)
+		})
+	}
+
+	if d := crud.read("", map[string]interface{}{"service": "DemoApp1", "status": "In Service"}); d.Id() != "DemoApp1/servers/In Service" {
+		t.Errorf("expected the ID to hold the service and status, got %s", d.Id())
+	}
+
+	d := schema.TestResourceDataRaw(t, crud.resource.Schema, map[string]interface{}{"service": "DemoApp3"})
+	diags := crud.resource.ReadContext(context.Background(), d, crud.client)
+	if !diags.HasError() {
+		t.Errorf("expected listing the servers of an unknown service to fail")
+	}
+}
@@ -0,0 +1,120 @@
+package barracudawaf
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceCudaWAFService() *schema.Resource {
+	dataSourceSchema := dataSourceBarracudaWAFSchema(resourceCudaWAFServices().Schema)
+	dataSourceSchema["name"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Computed:    true,
+		Description: "Web Application Name, the service matching the vsite, group and type filters when unset",
+	}
+
+	for _, filter := range dataSourceServicesFilters {
+		dataSourceSchema[filter].Optional = true
+	}
+
+	return &schema.Resource{
+		ReadContext: dataSourceCudaWAFServiceRead,
+
+		Schema: dataSourceSchema,
+
+		Description: "`barracudawaf_service` reads a `Service` configured on the Barracuda Web Application Firewall.",
+	}
+}
+
+func dataSourceCudaWAFServiceRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
+
+	name, err := dataSourceCudaWAFServiceName(ctx, client, d)
+
+	if err != nil {
+		log.Printf("[ERROR] Unable to Retrieve Barracuda WAF service (%v) ", err)
+		return barracudaWAFDiagnostics(err, dataSourceCudaWAFService().Schema, "Unable to Retrieve Barracuda WAF service")
+	}
+
+	log.Println("[INFO] Fetching Barracuda WAF resource " + name)
+
+	service, err := client.GetService(ctx, name)
+
+	if err != nil {
+		log.Printf("[ERROR] Unable to Retrieve Barracuda WAF resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, dataSourceCudaWAFService().Schema, "Unable to Retrieve Barracuda WAF resource (%s)", name)
+	}
+
+	dataSourceSchema := dataSourceCudaWAFService().Schema
+	err = setBarracudaWAFResourceData(d, dataSourceSchema, service)
+
+	if err != nil {
+		log.Printf("[ERROR] Unable to set Barracuda WAF resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, dataSourceCudaWAFService().Schema, "Unable to set Barracuda WAF resource (%s)", name)
+	}
+
+	err = readBarracudaWAFServicesSubResource(ctx, client, d, dataSourceSchema, name)
+
+	if err != nil {
+		log.Printf("[ERROR] Unable to Retrieve Barracuda WAF sub resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, dataSourceCudaWAFService().Schema, "Unable to Retrieve Barracuda WAF sub resource (%s)", name)
+	}
+
+	d.SetId(name)
+	return nil
+}
+
+// dataSourceCudaWAFServiceName : name of the service to read, the only service matching the
+// vsite, group and type filters when the name is not set.
+func dataSourceCudaWAFServiceName(ctx context.Context, client *waf.Client, d *schema.ResourceData) (string, error) {
+	if name := d.Get("name").(string); name != "" {
+		return name, nil
+	}
+
+	services, err := client.ListServices(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var filters, names []string
+	for _, filter := range dataSourceServicesFilters {
+		if value := d.Get(filter).(string); value != "" {
+			filters = append(filters, fmt.Sprintf("%s = %q", filter, value))
+		}
+	}
+
+	for _, service := range services {
+		params := barracudaWAFParams(service)
+
+		matches := true
+		for _, filter := range dataSourceServicesFilters {
+			if value := d.Get(filter).(string); value != "" && params[filter] != value {
+				matches = false
+			}
+		}
+
+		if name, ok := params["name"].(string); matches && ok {
+			names = append(names, name)
+		}
+	}
+
+	switch len(names) {
+	case 0:
+		return "", fmt.Errorf("no service matches the filters [%s]", strings.Join(filters, ", "))
+	case 1:
+		return names[0], nil
+	default:
+		return "", fmt.Errorf(
+			"multiple services (%s) match the filters [%s], set the name or narrow the filters",
+			strings.Join(names, ", "),
+			strings.Join(filters, ", "),
+		)
+	}
+}
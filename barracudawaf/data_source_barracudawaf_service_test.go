@@ -0,0 +1,102 @@
+package barracudawaf
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var SERVICE_DATA_SOURCE_READ = SERVICE_RESOURCE_CREATE + `
+data "barracudawaf_service" "demo_app_1" {
+    name = barracudawaf_services.demo_app_1.name
+}
+`
+
+func TestAccBarracudaWAFServiceDataSource_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAcctPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: SERVICE_DATA_SOURCE_READ,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.barracudawaf_service.demo_app_1", "name", "DemoApp1"),
+					resource.TestCheckResourceAttr("data.barracudawaf_service.demo_app_1", "port", "80"),
+					resource.TestCheckResourceAttr("data.barracudawaf_service.demo_app_1", "type", "HTTP"),
+					resource.TestCheckResourceAttr("data.barracudawaf_service.demo_app_1", "basic_security.0.mode", "Active"),
+				),
+			},
+		},
+	})
+}
+
+func TestBarracudaWAFServiceDataSource_read(t *testing.T) {
+	crud := newTestResourceCRUD(t, dataSourceCudaWAFService())
+
+	crud.server.SetObject("services/DemoApp1", map[string]interface{}{"name": "DemoApp1", "vsite": "default", "type": "HTTP", "port": "80"})
+	crud.server.SetObject("services/DemoApp2", map[string]interface{}{"name": "DemoApp2", "vsite": "default", "type": "HTTPS", "port": "443"})
+	crud.server.SetSubResource("services/DemoApp2", "basic-security", map[string]interface{}{"mode": "Active"})
+
+	d := crud.read("", map[string]interface{}{"name": "DemoApp2"})
+
+	if d.Id() != "DemoApp2" {
+		t.Errorf("expected the ID to be DemoApp2, got %s", d.Id())
+	}
+	testCheckAttributes(t, d, map[string]string{
+		"type":                  "HTTPS",
+		"port":                  "443",
+		"basic_security.0.mode": "Active",
+	})
+
+	d = schema.TestResourceDataRaw(t, crud.resource.Schema, map[string]interface{}{"name": "DemoApp3"})
+	diags := crud.resource.ReadContext(context.Background(), d, crud.client)
+	if !diags.HasError() || !strings.Contains(diags[0].Detail, "not found on the Barracuda WAF") {
+		t.Errorf("expected the read to fail as the service is not found, got %v", diags)
+	}
+}
+
+func TestBarracudaWAFServiceDataSource_filters(t *testing.T) {
+	crud := newTestResourceCRUD(t, dataSourceCudaWAFService())
+
+	crud.server.SetObject("services/DemoApp1", map[string]interface{}{"name": "DemoApp1", "vsite": "default", "group": "default", "type": "HTTP", "port": "80"})
+	crud.server.SetObject("services/DemoApp2", map[string]interface{}{"name": "DemoApp2", "vsite": "default", "group": "Payments", "type": "HTTPS", "port": "443"})
+
+	lookups := map[string]struct {
+		filters  map[string]interface{}
+		expected map[string]string
+	}{
+		"name":  {filters: map[string]interface{}{"name": "DemoApp2"}, expected: map[string]string{"name": "DemoApp2", "port": "443"}},
+		"type":  {filters: map[string]interface{}{"vsite": "default", "type": "HTTP"}, expected: map[string]string{"name": "DemoApp1", "port": "80"}},
+		"group": {filters: map[string]interface{}{"group": "Payments"}, expected: map[string]string{"name": "DemoApp2", "type": "HTTPS"}},
+	}
+
+	for name, c := range lookups {
+		d := crud.read("", c.filters)
+
+		if d.Id() != c.expected["name"] {
+			t.Errorf("%s: expected the ID to be %s, got %s", name, c.expected["name"], d.Id())
+		}
+		testCheckAttributes(t, d, c.expected)
+	}
+
+	cases := map[string]struct {
+		filters  map[string]interface{}
+		expected string
+	}{
+		"no match":         {filters: map[string]interface{}{"type": "FTP"}, expected: `no service matches the filters [type = "FTP"]`},
+		"multiple matches": {filters: map[string]interface{}{"vsite": "default"}, expected: "multiple services (DemoApp1, DemoApp2) match the filters"},
+		"not found":        {filters: map[string]interface{}{"name": "DemoApp3"}, expected: "DemoApp3"},
+	}
+
+	for name, c := range cases {
+		d := schema.TestResourceDataRaw(t, crud.resource.Schema, c.filters)
+
+		diags := crud.resource.ReadContext(context.Background(), d, crud.client)
+		if !diags.HasError() || !strings.Contains(diags[0].Summary+diags[0].Detail, c.expected) {
+			t.Errorf("%s: expected the read to fail with %q, got %v", name, c.expected, diags)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package barracudawaf
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var (
+	dataSourceServicesFilters = []string{"vsite", "group", "type"}
+)
+
+func dataSourceCudaWAFServices() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceCudaWAFServicesRead,
+
+		Schema: map[string]*schema.Schema{
+			"vsite": {Type: schema.TypeString, Optional: true, Description: "Only list the services of this Vsite"},
+			"group": {Type: schema.TypeString, Optional: true, Description: "Only list the services of this Service Group"},
+			"type":  {Type: schema.TypeString, Optional: true, Description: "Only list the services of this Type"},
+			"names": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Names of the matching services",
+			},
+			"services": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Resource{Schema: dataSourceCudaWAFServicesElemSchema()},
+				Description: "Matching services",
+			},
+		},
+
+		Description: "`barracudawaf_services` lists the `Services` configured on the Barracuda Web Application Firewall.",
+	}
+}
+
+// dataSourceCudaWAFServicesElemSchema : attributes of the services in the listing, sub
+// resources are only available through the barracudawaf_service data source.
+func dataSourceCudaWAFServicesElemSchema() map[string]*schema.Schema {
+	elemSchema := dataSourceBarracudaWAFSchema(resourceCudaWAFServices().Schema)
+
+	for attribute, attributeSchema := range elemSchema {
+		if _, ok := attributeSchema.Elem.(*schema.Resource); ok {
+			delete(elemSchema, attribute)
+		}
+	}
+
+	return elemSchema
+}
+
+func dataSourceCudaWAFServicesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
+
+	log.Println("[INFO] Fetching Barracuda WAF services")
+
+	resources, err := client.ListServices(ctx)
+
+	if err != nil {
+		log.Printf("[ERROR] Unable to Retrieve Barracuda WAF services (%v) ", err)
+		return barracudaWAFDiagnostics(err, dataSourceCudaWAFServices().Schema, "Unable to Retrieve Barracuda WAF services")
+	}
+
+	elemSchema := dataSourceCudaWAFServicesElemSchema()
+	services := make([]map[string]interface{}, 0)
+
+	for _, resource := range resources {
+		service := flattenBarracudaWAFResourceData(elemSchema, barracudaWAFParams(resource))
+
+		matches := true
+		for _, filter := range dataSourceServicesFilters {
+			if value := d.Get(filter).(string); value != "" && service[filter] != value {
+				matches = false
+			}
+		}
+
+		if _, ok := service["name"].(string); matches && ok {
+			services = append(services, service)
+		}
+	}
+
+	names := make([]string, 0, len(services))
+	for _, service := range services {
+		names = append(names, service["name"].(string))
+	}
+
+	d.Set("names", names)
+	d.Set("services", services)
+
+	id := []string{"services"}
+	for _, filter := range dataSourceServicesFilters {
+		id = append(id, d.Get(filter).(string))
+	}
+
+	d.SetId(strings.Join(id, "/"))
+	return nil
+}
@@ -0,0 +1,68 @@
+package barracudawaf
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+var SERVICES_DATA_SOURCE_READ = SERVICE_RESOURCE_CREATE + `
+data "barracudawaf_services" "http" {
+    vsite = "default"
+    type  = "HTTP"
+
+    depends_on = [ barracudawaf_services.demo_app_1 ]
+}
+`
+
+func TestAccBarracudaWAFServicesDataSource_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAcctPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: SERVICES_DATA_SOURCE_READ,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemAttr("data.barracudawaf_services.http", "names.*", "DemoApp1"),
+					resource.TestCheckTypeSetElemNestedAttrs("data.barracudawaf_services.http", "services.*", map[string]string{
+						"name":  "DemoApp1",
+						"vsite": "default",
+						"type":  "HTTP",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestBarracudaWAFServicesDataSource_filters(t *testing.T) {
+	crud := newTestResourceCRUD(t, dataSourceCudaWAFServices())
+
+	crud.server.SetObject("services/DemoApp1", map[string]interface{}{"name": "DemoApp1", "vsite": "default", "group": "default", "type": "HTTP"})
+	crud.server.SetObject("services/DemoApp2", map[string]interface{}{"name": "DemoApp2", "vsite": "default", "group": "Payments", "type": "HTTPS"})
+	crud.server.SetObject("services/DemoApp3", map[string]interface{}{"name": "DemoApp3", "vsite": "Partners", "group": "default", "type": "HTTP"})
+
+	cases := map[string]struct {
+		filters  map[string]interface{}
+		expected map[string]string
+	}{
+		"none":  {filters: map[string]interface{}{}, expected: map[string]string{"names.#": "3"}},
+		"vsite": {filters: map[string]interface{}{"vsite": "Partners"}, expected: map[string]string{"names.#": "1", "names.0": "DemoApp3"}},
+		"group": {filters: map[string]interface{}{"group": "Payments"}, expected: map[string]string{"names.#": "1", "names.0": "DemoApp2"}},
+		"type": {
+			filters:  map[string]interface{}{"vsite": "default", "type": "HTTP"},
+			expected: map[string]string{"names.#": "1", "names.0": "DemoApp1", "services.0.group": "default"},
+		},
+		"no match": {filters: map[string]interface{}{"type": "FTP"}, expected: map[string]string{"names.#": "0", "services.#": "0"}},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			testCheckAttributes(t, crud.read("", c.filters), c.expected)
+		})
+	}
+
+	if d := crud.read("", map[string]interface{}{"vsite": "default", "type": "HTTP"}); d.Id() != "services/default//HTTP" {
+		t.Errorf("expected the ID to hold the filters, got %s", d.Id())
+	}
+}
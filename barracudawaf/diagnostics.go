@@ -0,0 +1,107 @@
+package barracudawaf
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// subResourceError : error of a request sent for a sub resource block of the resource, used
+// to point the validation errors of the WAF at the attributes of the block.
+type subResourceError struct {
+	subResource string
+	err         error
+}
+
+// Error : returns the error of the request.
+func (e *subResourceError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap : returns the error of the request.
+func (e *subResourceError) Unwrap() error {
+	return e.err
+}
+
+// barracudaWAFDiagnostics : converts an error of the WAF client to diagnostics summarized by
+// the formatted message. Each validation error of the WAF is reported on the attribute the
+// rejected parameter is set from, e.g. "ssl_security.0.hsts_max_age: must be a number".
+func barracudaWAFDiagnostics(
+	err error,
+	resourceSchema map[string]*schema.Schema,
+	format string,
+	a ...interface{},
+) diag.Diagnostics {
+	summary := fmt.Sprintf(format, a...)
+
+	var apiErr *waf.APIError
+	if !errors.As(err, &apiErr) {
+		return diag.Diagnostics{{Severity: diag.Error, Summary: summary, Detail: err.Error()}}
+	}
+
+	detail := fmt.Sprintf(
+		"%s %s returned %d %s: %s",
+		apiErr.Method,
+		apiErr.URL,
+		apiErr.StatusCode,
+		http.StatusText(apiErr.StatusCode),
+		apiErr.Message,
+	)
+
+	if len(apiErr.Fields) == 0 {
+		return diag.Diagnostics{{Severity: diag.Error, Summary: summary, Detail: detail}}
+	}
+
+	var subErr *subResourceError
+	subResource := ""
+	if errors.As(err, &subErr) {
+		subResource = subErr.subResource
+	}
+
+	diags := make(diag.Diagnostics, 0, len(apiErr.Fields))
+	for _, field := range apiErr.Fields {
+		attribute, path := barracudaWAFAttributePath(resourceSchema, subResource, field.Param)
+
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       attribute + ": " + field.Message,
+			Detail:        summary + ", " + detail,
+			AttributePath: path,
+		})
+	}
+
+	return diags
+}
+
+// barracudaWAFAttributePath : returns the name and the path of the attribute a WAF parameter
+// is set from. Parameters without an attribute in the schema are returned with an empty path.
+func barracudaWAFAttributePath(
+	resourceSchema map[string]*schema.Schema,
+	subResource string,
+	param string,
+) (string, cty.Path) {
+	attribute := strings.Replace(param, "-", "_", -1)
+
+	if subResource == "" {
+		if _, ok := resourceSchema[attribute]; ok {
+			return attribute, cty.GetAttrPath(attribute)
+		}
+		return param, nil
+	}
+
+	if subResourceSchema, ok := resourceSchema[subResource]; ok {
+		if elem, ok := subResourceSchema.Elem.(*schema.Resource); ok {
+			if _, ok := elem.Schema[attribute]; ok {
+				return subResource + ".0." + attribute, cty.GetAttrPath(subResource).IndexInt(0).GetAttr(attribute)
+			}
+		}
+	}
+
+	return subResource + ": " + param, nil
+}
@@ -0,0 +1,85 @@
+package barracudawaf
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
+	"github.com/hashicorp/go-cty/cty"
+)
+
+func TestBarracudaWAFDiagnostics(t *testing.T) {
+	resourceSchema := resourceCudaWAFServices().Schema
+
+	apiErr := &waf.APIError{
+		StatusCode: 400,
+		Method:     "PUT",
+		URL:        "/services/DemoApp1/ssl-security",
+		Message:    "Validation failed",
+		Fields: []waf.FieldError{
+			{Param: "hsts-max-age", Message: "must be a number"},
+			{Param: "unknown-param", Message: "is not supported"},
+		},
+	}
+
+	diags := barracudaWAFDiagnostics(
+		&subResourceError{subResource: "ssl_security", err: apiErr},
+		resourceSchema,
+		"Unable to update Barracuda WAF resource (%s)",
+		"DemoApp1",
+	)
+
+	if len(diags) != 2 {
+		t.Fatalf("expected a diagnostic per field, got %v", diags)
+	}
+
+	if diags[0].Summary != "ssl_security.0.hsts_max_age: must be a number" {
+		t.Errorf("unexpected summary %q", diags[0].Summary)
+	}
+
+	if expected := cty.GetAttrPath("ssl_security").IndexInt(0).GetAttr("hsts_max_age"); !diags[0].AttributePath.Equals(expected) {
+		t.Errorf("unexpected attribute path %#v", diags[0].AttributePath)
+	}
+
+	expectedDetail := "Unable to update Barracuda WAF resource (DemoApp1), PUT /services/DemoApp1/ssl-security returned 400 Bad Request: Validation failed"
+	if diags[0].Detail != expectedDetail {
+		t.Errorf("unexpected detail %q", diags[0].Detail)
+	}
+
+	if diags[1].Summary != "ssl_security: unknown-param: is not supported" || diags[1].AttributePath != nil {
+		t.Errorf("expected the unknown parameter to be reported without a path, got %#v", diags[1])
+	}
+}
+
+func TestBarracudaWAFDiagnostics_attributes(t *testing.T) {
+	apiErr := &waf.APIError{
+		StatusCode: 400,
+		Method:     "POST",
+		URL:        "/services",
+		Message:    "Validation failed",
+		Fields:     []waf.FieldError{{Param: "ip-address", Message: "is not a valid IP address"}},
+	}
+
+	diags := barracudaWAFDiagnostics(apiErr, resourceCudaWAFServices().Schema, "Unable to create Barracuda WAF resource (%s)", "DemoApp1")
+
+	if len(diags) != 1 || diags[0].Summary != "ip_address: is not a valid IP address" ||
+		!diags[0].AttributePath.Equals(cty.GetAttrPath("ip_address")) {
+		t.Errorf("unexpected diagnostics %#v", diags)
+	}
+
+	diags = barracudaWAFDiagnostics(
+		&waf.APIError{StatusCode: 500, Method: "GET", URL: "/services", Message: "Internal Server Error"},
+		nil,
+		"Unable to Retrieve Barracuda WAF services",
+	)
+
+	if len(diags) != 1 || diags[0].Detail != "GET /services returned 500 Internal Server Error: Internal Server Error" {
+		t.Errorf("unexpected diagnostics %#v", diags)
+	}
+
+	diags = barracudaWAFDiagnostics(errors.New("connection refused"), nil, "Unable to delete the Barracuda WAF resource (%s)", "DemoApp1")
+
+	if len(diags) != 1 || diags[0].Summary != "Unable to delete the Barracuda WAF resource (DemoApp1)" || diags[0].Detail != "connection refused" {
+		t.Errorf("unexpected diagnostics %#v", diags)
+	}
+}
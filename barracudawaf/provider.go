@@ -1,11 +1,32 @@
 package barracudawaf
 
 import (
+	"context"
 	"fmt"
+	"time"
 
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
+const (
+	defaultAdminPort = "8443"           // defaultAdminPort : HTTPS admin port of the WAF
+	defaultTimeout   = 10 * time.Minute // defaultTimeout : default timeout of the resource operations
+)
+
+var (
+	// providerEnvVars : environment variables the provider arguments can be set with
+	providerEnvVars = map[string]string{
+		"address":    "BARRACUDA_WAF_IP",
+		"port":       "BARRACUDA_WAF_PORT",
+		"username":   "BARRACUDA_WAF_USERNAME",
+		"password":   "BARRACUDA_WAF_PASSWORD",
+		"api_token":  "BARRACUDA_WAF_API_TOKEN",
+		"token_file": "BARRACUDA_WAF_TOKEN_FILE",
+	}
+)
+
 // Provider : Schema definition for barracudawaf provider
 func Provider() *schema.Provider {
 
@@ -15,61 +36,194 @@ func Provider() *schema.Provider {
 		Schema: map[string]*schema.Schema{
 			"address": {
 				Type:        schema.TypeString,
-				Required:    true,
-				Description: "IP Address of the WAF to be configured",
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(providerEnvVars["address"], nil),
+				Description: "IP Address of the WAF to be configured, can also be set with the `BARRACUDA_WAF_IP` environment variable",
 			},
 			"port": {
 				Type:        schema.TypeString,
-				Required:    true,
-				Description: "Admin port on the WAF to be configured",
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(providerEnvVars["port"], defaultAdminPort),
+				Description: "Admin port on the WAF to be configured, can also be set with the `BARRACUDA_WAF_PORT` environment variable",
 			},
 			"username": {
 				Type:        schema.TypeString,
-				Required:    true,
-				Description: "Username of the WAF to be configured",
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(providerEnvVars["username"], nil),
+				Description: "Username of the WAF to be configured, can also be set with the `BARRACUDA_WAF_USERNAME` environment variable",
 			},
 			"password": {
 				Type:        schema.TypeString,
-				Required:    true,
-				Description: "Password of the WAF to be configured",
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc(providerEnvVars["password"], nil),
+				Description: "Password of the WAF to be configured, can also be set with the `BARRACUDA_WAF_PASSWORD` environment variable",
+			},
+			"api_token": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				DefaultFunc:   schema.EnvDefaultFunc(providerEnvVars["api_token"], nil),
+				ConflictsWith: []string{"token_file"},
+				Description: "Token used to authenticate instead of `username` and `password`, " +
+					"can also be set with the `BARRACUDA_WAF_API_TOKEN` environment variable",
+			},
+			"token_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc(providerEnvVars["token_file"], nil),
+				ConflictsWith: []string{"api_token"},
+				Description: "Path to a file holding the token used to authenticate instead of `username` and `password`, " +
+					"can also be set with the `BARRACUDA_WAF_TOKEN_FILE` environment variable",
+			},
+			"insecure": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Skip the verification of the admin API certificate of the WAF",
+			},
+			"ca_cert_pem": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "PEM encoded CA certificates used to verify the admin API certificate of the WAF",
+			},
+			"ca_cert_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Path to a PEM encoded CA bundle used to verify the admin API certificate of the WAF",
+			},
+			"client_cert": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"client_key"},
+				Description:  "PEM encoded client certificate presented to the WAF for mutual TLS",
+			},
+			"client_key": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				RequiredWith: []string{"client_cert"},
+				Description:  "PEM encoded private key of the client certificate",
+			},
+			"max_retries": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     waf.DefaultMaxRetries,
+				Description: "Number of retries of requests failing with a transient error",
+			},
+			"retry_min_wait": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     int(waf.DefaultRetryMinWait / time.Second),
+				Description: "Seconds to wait before the first retry, doubled on every further retry",
+			},
+			"retry_max_wait": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     int(waf.DefaultRetryMaxWait / time.Second),
+				Description: "Maximum seconds to wait between retries",
+			},
+			"max_concurrent_requests": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     waf.DefaultMaxConcurrentRequests,
+				Description: "Maximum number of requests sent to the WAF at the same time, 0 for no limit",
+			},
+			"requests_per_second": {
+				Type:        schema.TypeFloat,
+				Optional:    true,
+				Default:     0,
+				Description: "Maximum number of requests sent to the WAF per second, 0 for no limit",
 			},
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
-			"barracudawaf_trusted_ca_certificate":     resourceCudaWAFTrustedCaCertificate(),
-			"barracudawaf_content_rules":              resourceCudaWAFContentRules(),
-			"barracudawaf_trusted_server_certificate": resourceCudaWAFTrustedServerCertificate(),
-			"barracudawaf_services":                   resourceCudaWAFServices(),
-			"barracudawaf_content_rule_servers":       resourceCudaWAFContentRuleServers(),
-			"barracudawaf_security_policies":          resourceCudaWAFSecurityPolicies(),
-			"barracudawaf_signed_certificate":         resourceCudaWAFSignedCertificate(),
-			"barracudawaf_self_signed_certificate":    resourceCudaWAFSelfSignedCertificate(),
-			"barracudawaf_servers":                    resourceCudaWAFServers(),
-			"barracudawaf_letsencrypt_certificate":    resourceCudaWAFLetsEncryptCertificate(),
+			"barracudawaf_trusted_ca_certificate":      resourceCudaWAFTrustedCaCertificate(),
+			"barracudawaf_content_rules":               resourceCudaWAFContentRules(),
+			"barracudawaf_trusted_server_certificate":  resourceCudaWAFTrustedServerCertificate(),
+			"barracudawaf_services":                    resourceCudaWAFServices(),
+			"barracudawaf_content_rule_servers":        resourceCudaWAFContentRuleServers(),
+			"barracudawaf_security_policies":           resourceCudaWAFSecurityPolicies(),
+			"barracudawaf_signed_certificate":          resourceCudaWAFSignedCertificate(),
+			"barracudawaf_self_signed_certificate":     resourceCudaWAFSelfSignedCertificate(),
+			"barracudawaf_certificate_signing_request": resourceCudaWAFCertificateSigningRequest(),
+			"barracudawaf_certificate_chain":           resourceCudaWAFCertificateChain(),
+			"barracudawaf_client_ca_bundle":            resourceCudaWAFClientCABundle(),
+			"barracudawaf_servers":                     resourceCudaWAFServers(),
+			"barracudawaf_letsencrypt_certificate":     resourceCudaWAFLetsEncryptCertificate(),
+		},
+
+		DataSourcesMap: map[string]*schema.Resource{
+			"barracudawaf_service":         dataSourceCudaWAFService(),
+			"barracudawaf_services":        dataSourceCudaWAFServices(),
+			"barracudawaf_servers":         dataSourceCudaWAFServers(),
+			"barracudawaf_certificate":     dataSourceCudaWAFCertificate(),
+			"barracudawaf_security_policy": dataSourceCudaWAFSecurityPolicy(),
 		},
 	}
 
-	provider.ConfigureFunc = func(d *schema.ResourceData) (interface{}, error) {
+	provider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 		terraformVersion := provider.TerraformVersion
 		if terraformVersion == "" {
 			// Terraform 0.12 introduced this field to the protocol
 			// We can therefore assume that if it's missing it's 0.10 or 0.11
 			terraformVersion = "0.11+compatible"
 		}
-		return providerConfigure(d, terraformVersion)
+
+		client, err := providerConfigure(ctx, d, terraformVersion)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+		return client, nil
 	}
 
 	return provider
 }
 
-func providerConfigure(d *schema.ResourceData, terraformVersion string) (interface{}, error) {
+func providerConfigure(ctx context.Context, d *schema.ResourceData, terraformVersion string) (interface{}, error) {
+	required := []string{"address", "port"}
+
+	// credentials are only needed to log in when no token is configured
+	if d.Get("api_token").(string) == "" && d.Get("token_file").(string) == "" {
+		required = append(required, "username", "password")
+	}
+
+	for _, argument := range required {
+		if d.Get(argument).(string) == "" {
+			return nil, fmt.Errorf(
+				"%s is required, set it in the provider configuration or with the %s environment variable",
+				argument,
+				providerEnvVars[argument],
+			)
+		}
+	}
+
+	if d.Get("api_token").(string) != "" && d.Get("token_file").(string) != "" {
+		return nil, fmt.Errorf("only one of api_token and token_file can be set")
+	}
+
 	config := Config{
 		IPAddress: d.Get("address").(string),
 		AdminPort: d.Get("port").(string),
 		Username:  d.Get("username").(string),
 		Password:  d.Get("password").(string),
+		APIToken:  d.Get("api_token").(string),
+		TokenFile: d.Get("token_file").(string),
+
+		Insecure:   d.Get("insecure").(bool),
+		CACertPEM:  d.Get("ca_cert_pem").(string),
+		CACertFile: d.Get("ca_cert_file").(string),
+		ClientCert: d.Get("client_cert").(string),
+		ClientKey:  d.Get("client_key").(string),
+
+		MaxRetries:   d.Get("max_retries").(int),
+		RetryMinWait: time.Duration(d.Get("retry_min_wait").(int)) * time.Second,
+		RetryMaxWait: time.Duration(d.Get("retry_max_wait").(int)) * time.Second,
+
+		MaxConcurrentRequests: d.Get("max_concurrent_requests").(int),
+		RequestsPerSecond:     d.Get("requests_per_second").(float64),
 	}
-	cfg, err := config.Client()
+	cfg, err := config.Client(ctx)
 	if err != nil {
 		return cfg, err
 	}
@@ -1,9 +1,18 @@
 package barracudawaf
 
 import (
+	"context"
+	"encoding/pem"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf/waftest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -23,6 +32,143 @@ func TestAccProvider(t *testing.T) {
 	}
 }
 
+func TestProvider_resourceTimeouts(t *testing.T) {
+	for name, resource := range Provider().ResourcesMap {
+		if resource.CreateContext == nil || resource.ReadContext == nil || resource.DeleteContext == nil {
+			t.Errorf("%s: expected the context aware CRUD functions to be used", name)
+		}
+
+		timeouts := resource.Timeouts
+		if timeouts == nil || timeouts.Create == nil || timeouts.Read == nil || timeouts.Delete == nil {
+			t.Errorf("%s: expected a default timeout for every operation", name)
+		}
+
+		if (resource.UpdateContext != nil) != (timeouts != nil && timeouts.Update != nil) {
+			t.Errorf("%s: expected a default update timeout only when the resource can be updated", name)
+		}
+	}
+}
+
+func TestProvider_forceNew(t *testing.T) {
+	cases := map[string][]string{
+		"barracudawaf_services":                {"address_version", "type", "group", "vsite", "certificate", "secure_site_domain"},
+		"barracudawaf_servers":                 {"address_version"},
+		"barracudawaf_content_rule_servers":    {"address_version"},
+		"barracudawaf_security_policies":       {"based_on"},
+		"barracudawaf_self_signed_certificate": {"name", "common_name", "key_type", "key_size", "san_certificate"},
+		"barracudawaf_signed_certificate":      {"name", "signed_certificate", "certificate_key", "certificate_request_name", "common_name"},
+		"barracudawaf_letsencrypt_certificate": {"name", "common_name", "multi_cert_trusted_service", "san_cert"},
+		"barracudawaf_certificate_chain":       {"certificate_name"},
+		"barracudawaf_client_ca_bundle":        {"name", "certificates"},
+	}
+
+	resources := Provider().ResourcesMap
+	for name, attributes := range cases {
+		for _, attribute := range attributes {
+			if !resources[name].Schema[attribute].ForceNew {
+				t.Errorf("%s: expected %s to force a new resource as the WAF cannot update it", name, attribute)
+			}
+		}
+	}
+
+	for _, name := range []string{
+		"barracudawaf_certificate_signing_request",
+		"barracudawaf_trusted_ca_certificate",
+		"barracudawaf_trusted_server_certificate",
+	} {
+		if resources[name].UpdateContext != nil {
+			t.Errorf("%s: expected every attribute to force a new resource", name)
+		}
+	}
+}
+
+func TestProvider_stateUpgraders(t *testing.T) {
+	cases := map[string]struct {
+		state    map[string]interface{}
+		expected map[string]interface{}
+	}{
+		"barracudawaf_servers": {
+			state:    map[string]interface{}{"parent": []interface{}{"DemoApp1"}},
+			expected: map[string]interface{}{"service_name": "DemoApp1"},
+		},
+		"barracudawaf_content_rules": {
+			state:    map[string]interface{}{"parent": []interface{}{"DemoApp1"}},
+			expected: map[string]interface{}{"service_name": "DemoApp1"},
+		},
+		"barracudawaf_content_rule_servers": {
+			state:    map[string]interface{}{"parent": []interface{}{"DemoApp1", "DemoRule1"}},
+			expected: map[string]interface{}{"service_name": "DemoApp1", "content_rule_name": "DemoRule1"},
+		},
+		"barracudawaf_signed_certificate": {
+			state: map[string]interface{}{"certificate_password": "secret@123", "certificate_key": ""},
+			expected: map[string]interface{}{
+				"certificate_password": hashBarracudaWAFSecret("secret@123"),
+				"certificate_key":      "",
+			},
+		},
+		"barracudawaf_services": {
+			state:    map[string]interface{}{"vsite": "default", "basic_security": []interface{}{map[string]interface{}{"mode": "Passive"}}},
+			expected: map[string]interface{}{"vsite": "default"},
+		},
+		"barracudawaf_security_policies": {
+			state:    map[string]interface{}{"based_on": "Create New"},
+			expected: map[string]interface{}{"based_on": "Create New"},
+		},
+		"barracudawaf_trusted_ca_certificate": {
+			state:    map[string]interface{}{"certificate": "Q0E="},
+			expected: map[string]interface{}{"certificate": "Q0E="},
+		},
+		"barracudawaf_trusted_server_certificate": {
+			state:    map[string]interface{}{"certificate": "U2VydmVy"},
+			expected: map[string]interface{}{"certificate": "U2VydmVy"},
+		},
+		"barracudawaf_self_signed_certificate": {
+			state:    map[string]interface{}{"common_name": "www.example.com", "key_type": "rsa"},
+			expected: map[string]interface{}{"common_name": "www.example.com", "key_type": "rsa"},
+		},
+		"barracudawaf_letsencrypt_certificate": {
+			state:    map[string]interface{}{"common_name": "www.example.com", "multi_cert_trusted_service": "DemoApp1"},
+			expected: map[string]interface{}{"common_name": "www.example.com", "multi_cert_trusted_service": "DemoApp1"},
+		},
+	}
+
+	for name, resource := range Provider().ResourcesMap {
+		c, ok := cases[name]
+		if !ok {
+			if resource.SchemaVersion != 0 {
+				t.Errorf("%s: expected the upgrade of the version 0 state to be tested", name)
+			}
+			continue
+		}
+
+		if resource.SchemaVersion != 1 || len(resource.StateUpgraders) != 1 {
+			t.Fatalf("%s: expected version 1 with the upgrade of the version 0 state", name)
+		}
+
+		rawState := map[string]interface{}{"id": "DemoObject1", "name": "DemoObject1"}
+		for attribute, value := range c.state {
+			rawState[attribute] = value
+		}
+
+		upgraded, err := resource.StateUpgraders[0].Upgrade(context.Background(), rawState, nil)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+
+		for attribute, value := range c.expected {
+			if upgraded[attribute] != value {
+				t.Errorf("%s: expected %s to be %v, got %v", name, attribute, value, upgraded[attribute])
+			}
+		}
+
+		for attribute := range upgraded {
+			if _, ok := resource.Schema[attribute]; !ok && attribute != "id" {
+				t.Errorf("%s: unexpected attribute %s in the upgraded state", name, attribute)
+			}
+		}
+	}
+}
+
 func testAcctPreCheck(t *testing.T) {
 	if os.Getenv("BARRACUDA_WAF_IP") != "" && (os.Getenv("BARRACUDA_WAF_USERNAME") != "" && os.Getenv("BARRACUDA_WAF_PASSWORD") != "") {
 		return
@@ -31,3 +177,204 @@ func testAcctPreCheck(t *testing.T) {
 		return
 	}
 }
+
+func TestProvider_envDefaults(t *testing.T) {
+	for argument, envVar := range providerEnvVars {
+		defer os.Setenv(envVar, os.Getenv(envVar))
+		os.Setenv(envVar, "from-"+argument)
+	}
+
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{})
+
+	for argument := range providerEnvVars {
+		if value := d.Get(argument).(string); value != "from-"+argument {
+			t.Errorf("expected %s to default to the environment variable, got %q", argument, value)
+		}
+	}
+}
+
+func TestProvider_missingCredentials(t *testing.T) {
+	for _, envVar := range providerEnvVars {
+		defer os.Setenv(envVar, os.Getenv(envVar))
+		os.Unsetenv(envVar)
+	}
+
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"address": "127.0.0.1",
+	})
+
+	_, err := providerConfigure(context.Background(), d, "0.14.0")
+	if err == nil || !strings.Contains(err.Error(), "BARRACUDA_WAF_USERNAME") {
+		t.Fatalf("expected an error pointing at BARRACUDA_WAF_USERNAME, got %v", err)
+	}
+}
+
+func TestConfig_tokenFile(t *testing.T) {
+	tokenFile, err := ioutil.TempFile("", "barracudawaf-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tokenFile.Name())
+
+	server := waftest.NewServer(t)
+
+	tokenFile.WriteString(server.IssueToken() + "\n")
+	tokenFile.Close()
+
+	url := strings.Split(server.URL, ":")
+
+	config := Config{
+		IPAddress:    "http:" + url[1],
+		AdminPort:    url[2],
+		TokenFile:    tokenFile.Name(),
+		RetryMaxWait: waf.DefaultRetryMaxWait,
+	}
+
+	client, err := config.Client(context.Background())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if _, err := client.ListServices(context.Background()); err != nil {
+		t.Fatalf("expected the token of the file to be used, got %v", err)
+	}
+
+	if server.Logins() != 0 {
+		t.Errorf("expected no login, got %d logins", server.Logins())
+	}
+}
+
+func TestConfig_retries(t *testing.T) {
+	server := waftest.NewServer(t)
+	url := strings.Split(server.URL, ":")
+
+	cases := map[string]struct {
+		raw      map[string]interface{}
+		expected string
+	}{
+		"defaults":           {raw: map[string]interface{}{}},
+		"settings":           {raw: map[string]interface{}{"max_retries": 5, "retry_min_wait": 2, "retry_max_wait": 60}},
+		"no retries":         {raw: map[string]interface{}{"max_retries": 0}},
+		"negative retries":   {raw: map[string]interface{}{"max_retries": -1}, expected: "max_retries must not be negative"},
+		"negative min wait":  {raw: map[string]interface{}{"retry_min_wait": -1}, expected: "retry_min_wait must be between 0 and retry_max_wait"},
+		"min above max wait": {raw: map[string]interface{}{"retry_min_wait": 10, "retry_max_wait": 5}, expected: "retry_min_wait must be between 0 and retry_max_wait"},
+	}
+
+	for name, c := range cases {
+		raw := map[string]interface{}{
+			"address":  "http:" + url[1],
+			"port":     url[2],
+			"username": waftest.Username,
+			"password": waftest.Password,
+		}
+		for argument, value := range c.raw {
+			raw[argument] = value
+		}
+
+		d := schema.TestResourceDataRaw(t, Provider().Schema, raw)
+		meta, err := providerConfigure(context.Background(), d, "0.14.0")
+
+		if c.expected != "" {
+			if err == nil || !strings.Contains(err.Error(), c.expected) {
+				t.Errorf("%s: expected an error containing %q, got %v", name, c.expected, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+
+		client := meta.(*waf.Client)
+		expected := []interface{}{
+			time.Duration(d.Get("retry_min_wait").(int)) * time.Second,
+			time.Duration(d.Get("retry_max_wait").(int)) * time.Second,
+			d.Get("max_retries").(int),
+		}
+
+		if actual := []interface{}{client.RetryMinWait, client.RetryMaxWait, client.MaxRetries}; !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%s: expected the retries to be set to %v, got %v", name, expected, actual)
+		}
+	}
+}
+
+func TestConfig_tls(t *testing.T) {
+	server := waftest.NewTLSServer(t)
+	serverCA := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	directory, err := ioutil.TempDir("", "barracudawaf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+
+	caCertFile := filepath.Join(directory, "ca.pem")
+	ioutil.WriteFile(caCertFile, []byte(serverCA), 0600)
+
+	other := newTestBarracudaWAFCertificate(t, "other", nil)
+
+	cases := map[string]struct {
+		config   Config
+		expected string
+	}{
+		"default verification": {
+			expected: "Set ca_cert_pem or ca_cert_file",
+		},
+		"ca_cert_pem":  {config: Config{CACertPEM: serverCA}},
+		"ca_cert_file": {config: Config{CACertFile: caCertFile}},
+		"ca_cert_pem without trailing newline and ca_cert_file": {
+			config: Config{CACertPEM: strings.TrimSpace(string(other.pem)), CACertFile: caCertFile},
+		},
+		"invalid ca_cert_pem": {
+			config:   Config{CACertPEM: "not a certificate"},
+			expected: "no valid PEM encoded CA certificate found",
+		},
+		"missing ca_cert_file": {
+			config:   Config{CACertFile: filepath.Join(directory, "missing.pem")},
+			expected: "unable to read ca_cert_file",
+		},
+		"insecure": {config: Config{Insecure: true}},
+	}
+
+	url := strings.Split(server.URL, ":")
+
+	for name, c := range cases {
+		config := c.config
+		config.IPAddress = "https:" + url[1]
+		config.AdminPort = url[2]
+		config.Username = waftest.Username
+		config.Password = waftest.Password
+
+		_, err := config.Client(context.Background())
+
+		if c.expected == "" && err != nil {
+			t.Errorf("%s: expected the admin API to be trusted, got %v", name, err)
+		}
+
+		if c.expected != "" && (err == nil || !strings.Contains(err.Error(), c.expected)) {
+			t.Errorf("%s: expected an error containing %q, got %v", name, c.expected, err)
+		}
+	}
+}
+
+func TestConfig_clientCertificate(t *testing.T) {
+	client := newTestBarracudaWAFCertificate(t, "terraform", nil)
+	other := newTestBarracudaWAFCertificate(t, "other", nil)
+
+	config := Config{ClientCert: string(client.pem), ClientKey: string(client.keyPEM)}
+
+	tlsConfig, err := config.tlsConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(tlsConfig.Certificates) != 1 || tlsConfig.InsecureSkipVerify {
+		t.Errorf("expected the client certificate to be loaded with the verification enabled, got %+v", tlsConfig)
+	}
+
+	config.ClientKey = string(other.keyPEM)
+
+	if _, err := config.tlsConfig(); err == nil || !strings.Contains(err.Error(), "unable to load client_cert and client_key") {
+		t.Errorf("expected a mismatched client_cert and client_key to fail, got %v", err)
+	}
+}
@@ -0,0 +1,229 @@
+package barracudawaf
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceCudaWAFCertificateChain() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceCudaWAFCertificateChainCreate,
+		ReadContext:   resourceCudaWAFCertificateChainRead,
+		UpdateContext: resourceCudaWAFCertificateChainUpdate,
+		DeleteContext: resourceCudaWAFCertificateChainDelete,
+		CustomizeDiff: resourceCudaWAFCertificateChainCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Read:   schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"certificate_name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the signed certificate the chain is attached to",
+				ForceNew:    true,
+			},
+			"certificates": {
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressBarracudaWAFEquivalentBundle,
+				Description:      "PEM intermediate certificates, ordered from the issuer of the certificate up to the root",
+			},
+			"certificate_details": barracudaWAFCertificateDetailsSchema(),
+			"expiry": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Earliest expiry of the certificates of the chain",
+			},
+		},
+
+		Description: "`barracudawaf_certificate_chain` manages the `Intermediary Certificates` of a `Signed Certificate` on the Barracuda Web Application Firewall.",
+	}
+}
+
+func resourceCudaWAFCertificateChainCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	name := d.Get("certificate_name").(string)
+
+	log.Println("[INFO] Creating Barracuda WAF resource " + name)
+
+	if diags := updateBarracudaWAFCertificateChain(ctx, d, m, name); diags != nil {
+		return diags
+	}
+
+	d.SetId(name)
+	return resourceCudaWAFCertificateChainRead(ctx, d, m)
+}
+
+func resourceCudaWAFCertificateChainRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
+
+	name := d.Id()
+	log.Println("[INFO] Fetching Barracuda WAF resource " + name)
+
+	certificate, err := client.GetCertificate(ctx, waf.SignedCertificates, name)
+
+	if waf.IsNotFound(err) || (err == nil && len(certificate.IntermediaryCertificates) == 0) {
+		log.Printf("[WARN] Barracuda WAF resource (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	if err != nil {
+		log.Printf("[ERROR] Unable to Retrieve Barracuda WAF resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFCertificateChain().Schema, "Unable to Retrieve Barracuda WAF resource (%s)", name)
+	}
+
+	// the chain is read back from the WAF when it returns the certificates themselves
+	var chain []string
+	for _, intermediate := range certificate.IntermediaryCertificates {
+		chain = append(chain, string(decodeBarracudaWAFContent([]byte(intermediate))))
+	}
+
+	certificates := d.Get("certificates").(string)
+	if _, _, err := parseBarracudaWAFPEMBundle(strings.Join(chain, "")); err == nil {
+		certificates = strings.Join(chain, "")
+		d.Set("certificates", certificates)
+	}
+
+	if _, parsed, err := parseBarracudaWAFPEMBundle(certificates); err == nil {
+		details, expiry := flattenBarracudaWAFCertificateDetails(parsed)
+		d.Set("certificate_details", details)
+		d.Set("expiry", expiry)
+	}
+
+	d.Set("certificate_name", name)
+	return nil
+}
+
+func resourceCudaWAFCertificateChainUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	name := d.Id()
+
+	log.Println("[INFO] Updating Barracuda WAF resource " + name)
+
+	if diags := updateBarracudaWAFCertificateChain(ctx, d, m, name); diags != nil {
+		return diags
+	}
+
+	return resourceCudaWAFCertificateChainRead(ctx, d, m)
+}
+
+func resourceCudaWAFCertificateChainCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.NewValueKnown("certificates") {
+		if _, certificates, err := parseBarracudaWAFPEMBundle(d.Get("certificates").(string)); err == nil {
+			if err := checkBarracudaWAFChainOrder(certificates); err != nil {
+				return err
+			}
+
+			// the certificate is checked once it is uploaded when it is created by the same apply
+			name := d.Get("certificate_name").(string)
+			if client, ok := m.(*waf.Client); ok && d.NewValueKnown("certificate_name") && d.HasChanges("certificate_name", "certificates") {
+				if err := checkBarracudaWAFChainLeaf(ctx, client, name, certificates); err != nil && !waf.IsNotFound(err) {
+					return err
+				}
+			}
+		}
+	}
+
+	return setNewBarracudaWAFCertificateDetails(d, "certificates")
+}
+
+func resourceCudaWAFCertificateChainDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
+
+	name := d.Id()
+
+	log.Println("[INFO] Deleting Barracuda WAF resource " + name)
+
+	err := client.UpdateCertificateChain(ctx, waf.SignedCertificates, name, nil)
+
+	if err != nil && !waf.IsNotFound(err) {
+		return barracudaWAFDiagnostics(err, resourceCudaWAFCertificateChain().Schema, "Unable to delete the Barracuda WAF resource (%s)", name)
+	}
+
+	return nil
+}
+
+// updateBarracudaWAFCertificateChain : validates the chain and replaces the intermediate
+// certificates of the signed certificate with it.
+func updateBarracudaWAFCertificateChain(ctx context.Context, d *schema.ResourceData, m interface{}, name string) diag.Diagnostics {
+	client := m.(*waf.Client)
+
+	blocks, certificates, err := parseBarracudaWAFPEMBundle(d.Get("certificates").(string))
+	if err == nil {
+		err = checkBarracudaWAFChainOrder(certificates)
+	}
+	if err == nil {
+		err = checkBarracudaWAFChainLeaf(ctx, client, name, certificates)
+	}
+
+	if err != nil {
+		log.Printf("[ERROR] Invalid certificates for Barracuda WAF resource (%s) (%v) ", name, err)
+		return diag.Errorf("Invalid certificates for Barracuda WAF resource (%s): %v", name, err)
+	}
+
+	err = client.UpdateCertificateChain(ctx, waf.SignedCertificates, name, encodeBarracudaWAFPEMBundle(blocks))
+
+	if err != nil {
+		log.Printf("[ERROR] Unable to update the Barracuda WAF resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFCertificateChain().Schema, "Unable to update the Barracuda WAF resource (%s)", name)
+	}
+
+	return nil
+}
+
+// checkBarracudaWAFChainLeaf : checks that the named signed certificate is issued by the first
+// certificate of the chain, when the WAF returns the certificate itself.
+func checkBarracudaWAFChainLeaf(ctx context.Context, client *waf.Client, name string, chain []*x509.Certificate) error {
+	certificate, err := client.GetCertificate(ctx, waf.SignedCertificates, name)
+	if err != nil {
+		return err
+	}
+
+	leaf := barracudaWAFStoredCertificate(certificate)
+	if leaf == nil {
+		log.Printf("[WARN] Barracuda WAF resource (%s) does not return its certificate, unable to check that it is issued by the chain", name)
+		return nil
+	}
+
+	if err := leaf.CheckSignatureFrom(chain[0]); err != nil {
+		return fmt.Errorf(
+			"the certificate (%s) is not issued by the first certificate of the chain (%s): %v",
+			leaf.Subject,
+			chain[0].Subject,
+			err,
+		)
+	}
+
+	return nil
+}
+
+// barracudaWAFStoredCertificate : returns the certificate returned by the WAF for a stored
+// certificate, nil when it only returns its parameters.
+func barracudaWAFStoredCertificate(certificate *waf.Certificate) *x509.Certificate {
+	for _, content := range []string{certificate.Certificate, certificate.SignedCertificate} {
+		blocks, _, err := splitBarracudaWAFPEM(decodeBarracudaWAFContent([]byte(content)))
+		if err != nil || len(blocks) == 0 {
+			continue
+		}
+
+		if leaf, err := x509.ParseCertificate(blocks[0].Bytes); err == nil {
+			return leaf
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,184 @@
+package barracudawaf
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+var CERTIFICATE_CHAIN_RESOURCE_CREATE = BARRACUDA_WAF_PROVIDER + `
+resource "barracudawaf_certificate_chain" "demo_chain" {
+    certificate_name = "DemoSignedCert"
+    certificates     = file("certificates/chain.pem")
+}
+`
+
+func TestAccBarracudaWAFCertificateChain_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAcctPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: CERTIFICATE_CHAIN_RESOURCE_CREATE,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckCertificateChainExists("DemoSignedCert"),
+					resource.TestCheckResourceAttr("barracudawaf_certificate_chain.demo_chain", "certificate_name", "DemoSignedCert"),
+					resource.TestCheckResourceAttrSet("barracudawaf_certificate_chain.demo_chain", "expiry"),
+				),
+			},
+		},
+	})
+}
+
+func testCheckCertificateChainExists(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*waf.Client)
+
+		certificate, err := client.GetCertificate(context.Background(), waf.SignedCertificates, name)
+		if err != nil || len(certificate.IntermediaryCertificates) == 0 {
+			return fmt.Errorf("certificate chain of (%s) not found on the system (%v)", name, err)
+		}
+
+		return nil
+	}
+}
+
+func TestBarracudaWAFCertificateChain_crud(t *testing.T) {
+	crud := newTestResourceCRUD(t, resourceCudaWAFCertificateChain())
+
+	root := newTestBarracudaWAFCertificate(t, "Root CA", nil)
+	intermediate := newTestBarracudaWAFCertificate(t, "Intermediate CA", root)
+
+	crud.server.SetObject("signed-certificate/DemoSignedCert", map[string]interface{}{"name": "DemoSignedCert"})
+
+	raw := map[string]interface{}{
+		"certificate_name": "DemoSignedCert",
+		"certificates":     string(intermediate.pem) + string(root.pem),
+	}
+
+	d := crud.create(raw)
+
+	intermediates := crud.server.Object("signed-certificate/DemoSignedCert")["intermediary-certificates"].([]interface{})
+	if len(intermediates) != 2 || string(decodeBarracudaWAFContent([]byte(intermediates[0].(string)))) != string(intermediate.pem) {
+		t.Fatalf("expected the base64 encoded chain to be uploaded in order, got %v", intermediates)
+	}
+
+	testCheckAttributes(t, d, map[string]string{
+		"certificate_name":                         "DemoSignedCert",
+		"certificate_details.#":                    "2",
+		"certificate_details.0.subject":            "CN=Intermediate CA",
+		"certificate_details.0.issuer":             "CN=Root CA",
+		"certificate_details.1.sha256_fingerprint": barracudaWAFFingerprint(root.certificate),
+	})
+
+	raw["certificates"] = string(intermediate.pem)
+	d = crud.update(d.Id(), raw)
+
+	if intermediates := crud.server.Object("signed-certificate/DemoSignedCert")["intermediary-certificates"].([]interface{}); len(intermediates) != 1 {
+		t.Errorf("expected the chain to be replaced, got %v", intermediates)
+	}
+
+	crud.delete(d.Id(), raw)
+	if intermediates := crud.server.Object("signed-certificate/DemoSignedCert")["intermediary-certificates"].([]interface{}); len(intermediates) != 0 {
+		t.Fatalf("expected the chain to be removed from the certificate, got %v", intermediates)
+	}
+
+	if d = crud.read(d.Id(), raw); d.Id() != "" {
+		t.Errorf("expected the removed chain to be removed from the state")
+	}
+}
+
+func TestBarracudaWAFCertificateChain_invalid(t *testing.T) {
+	resource := resourceCudaWAFCertificateChain()
+
+	root := newTestBarracudaWAFCertificate(t, "Root CA", nil)
+	intermediate := newTestBarracudaWAFCertificate(t, "Intermediate CA", root)
+
+	cases := map[string]struct {
+		certificates string
+		expected     string
+	}{
+		"out of order": {certificates: string(root.pem) + string(intermediate.pem), expected: "out of order"},
+		"private key":  {certificates: string(intermediate.pem) + string(intermediate.keyPEM), expected: "private key"},
+		"no PEM":       {certificates: "not a certificate", expected: "no PEM certificate"},
+	}
+
+	for name, c := range cases {
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"certificate_name": "DemoSignedCert",
+			"certificates":     c.certificates,
+		})
+
+		_, err := resource.Diff(context.Background(), nil, config, nil)
+		if err == nil || !strings.Contains(err.Error(), c.expected) {
+			t.Errorf("%s: expected the plan to fail with %q, got %v", name, c.expected, err)
+		}
+	}
+}
+
+func TestBarracudaWAFCertificateChain_signedCertificate(t *testing.T) {
+	crud := newTestResourceCRUD(t, resourceCudaWAFSignedCertificate())
+
+	root := newTestBarracudaWAFCertificate(t, "Root CA", nil)
+	intermediate := newTestBarracudaWAFCertificate(t, "Intermediate CA", root)
+	leaf := newTestBarracudaWAFCertificate(t, "www.example.com", intermediate)
+
+	raw := map[string]interface{}{
+		"name":                      "DemoSignedCert",
+		"signed_certificate":        string(leaf.pem),
+		"certificate_key":           string(leaf.keyPEM),
+		"intermediary_certificates": []interface{}{string(intermediate.pem)},
+	}
+
+	signed := crud.create(raw)
+
+	chain := crud.with(resourceCudaWAFCertificateChain())
+	chain.create(map[string]interface{}{
+		"certificate_name": "DemoSignedCert",
+		"certificates":     string(intermediate.pem) + string(root.pem),
+	})
+
+	// the chain replaced by the chain resource is not planned back by the signed certificate
+	signed = crud.read(signed.Id(), raw)
+	for name, config := range map[string]map[string]interface{}{
+		"uploaded chain": raw,
+		"changed chain": {
+			"name":                      "DemoSignedCert",
+			"signed_certificate":        string(leaf.pem),
+			"certificate_key":           string(leaf.keyPEM),
+			"intermediary_certificates": []interface{}{string(root.pem)},
+		},
+	} {
+		diff, err := crud.resource.Diff(context.Background(), signed.State(), terraform.NewResourceConfigRaw(config), crud.client)
+		if err != nil {
+			t.Fatalf("%s: diff: %v", name, err)
+		}
+
+		if diff != nil && (diff.RequiresNew() || len(diff.Attributes) > 0) {
+			t.Errorf("%s: expected the intermediary certificates to be left to the chain resource, got %v", name, diff)
+		}
+	}
+
+	// the first certificate of the chain must issue the certificate
+	other := newTestBarracudaWAFCertificate(t, "Other CA", root)
+	config := map[string]interface{}{
+		"certificate_name": "DemoSignedCert",
+		"certificates":     string(other.pem) + string(root.pem),
+	}
+
+	_, err := chain.resource.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), chain.client)
+	if err == nil || !strings.Contains(err.Error(), "not issued by the first certificate of the chain") {
+		t.Errorf("expected the plan to fail as the chain does not issue the certificate, got %v", err)
+	}
+
+	d := schema.TestResourceDataRaw(t, chain.resource.Schema, config)
+	if diags := chain.resource.CreateContext(context.Background(), d, chain.client); !diags.HasError() {
+		t.Errorf("expected the chain not issuing the certificate to be rejected")
+	}
+}
@@ -0,0 +1,209 @@
+package barracudawaf
+
+import (
+	"context"
+	"log"
+
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceCudaWAFCertificateSigningRequest() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceCudaWAFCertificateSigningRequestCreate,
+		ReadContext:   resourceCudaWAFCertificateSigningRequestRead,
+		DeleteContext: resourceCudaWAFCertificateSigningRequestDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Read:   schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Certificate Request Name",
+				ForceNew:    true,
+			},
+			"common_name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Common Name",
+				ForceNew:    true,
+			},
+			"country_code": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Country",
+				ValidateFunc: validateBarracudaWAFCountryCode,
+				ForceNew:     true,
+			},
+			"state": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "State or Province",
+				ForceNew:    true,
+			},
+			"city": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Locality Name",
+				ForceNew:    true,
+			},
+			"organization_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Organization Name",
+				ForceNew:    true,
+			},
+			"organizational_unit": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Organizational Unit Name",
+				ForceNew:    true,
+			},
+			"key_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Key Type",
+				ValidateFunc: validateBarracudaWAFKeyType,
+				ForceNew:     true,
+			},
+			"key_size": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Key Size",
+				ValidateFunc: validateBarracudaWAFKeySize,
+				ForceNew:     true,
+			},
+			"elliptic_curve_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Elliptic Curve Name",
+				ForceNew:    true,
+			},
+			"san_certificate": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "SAN Certificate",
+				ForceNew:    true,
+			},
+			"allow_private_key_export": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "If set to <b>Yes</b>, the Private Key can be downloaded along with the signed certificate.",
+				ValidateFunc: validateBarracudaWAFYesNo,
+				ForceNew:     true,
+			},
+			"certificate_request_pem": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "PEM certificate signing request of the key generated on the WAF",
+			},
+		},
+
+		Description: "`barracudawaf_certificate_signing_request` manages a `Certificate Signing Request` and its private key generated on the Barracuda Web Application Firewall.",
+	}
+}
+
+func resourceCudaWAFCertificateSigningRequestCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
+
+	name := d.Get("name").(string)
+
+	log.Println("[INFO] Creating Barracuda WAF resource " + name)
+
+	err := client.CreateCertificate(ctx, waf.CertificateRequests, hydrateBarracudaWAFCertificateSigningRequestResource(d))
+
+	if err != nil {
+		log.Printf("[ERROR] Unable to create Barracuda WAF resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFCertificateSigningRequest().Schema, "Unable to create Barracuda WAF resource (%s)", name)
+	}
+
+	d.SetId(name)
+	return resourceCudaWAFCertificateSigningRequestRead(ctx, d, m)
+}
+
+func resourceCudaWAFCertificateSigningRequestRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
+
+	name := d.Id()
+	log.Println("[INFO] Fetching Barracuda WAF resource " + name)
+
+	request, err := client.GetCertificate(ctx, waf.CertificateRequests, name)
+
+	if waf.IsNotFound(err) {
+		log.Printf("[WARN] Barracuda WAF resource (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	if err != nil {
+		log.Printf("[ERROR] Unable to Retrieve Barracuda WAF resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFCertificateSigningRequest().Schema, "Unable to Retrieve Barracuda WAF resource (%s)", name)
+	}
+
+	err = setBarracudaWAFResourceData(d, resourceCudaWAFCertificateSigningRequest().Schema, request)
+
+	if err != nil {
+		log.Printf("[ERROR] Unable to set Barracuda WAF resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFCertificateSigningRequest().Schema, "Unable to set Barracuda WAF resource (%s)", name)
+	}
+
+	// the WAF clears the request once a signed certificate is uploaded onto its key, the
+	// request of the state is kept as the key is still in use
+	if request.CertificateRequest != "" {
+		d.Set("certificate_request_pem", string(decodeBarracudaWAFContent([]byte(request.CertificateRequest))))
+	} else {
+		log.Printf("[INFO] The key of Barracuda WAF resource (%s) is assigned to a signed certificate", name)
+	}
+	d.Set("name", name)
+	return nil
+}
+
+func resourceCudaWAFCertificateSigningRequestDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
+
+	name := d.Id()
+
+	log.Println("[INFO] Deleting Barracuda WAF resource " + name)
+
+	err := client.DeleteCertificate(ctx, waf.CertificateRequests, name)
+
+	if err != nil {
+		return barracudaWAFDiagnostics(err, resourceCudaWAFCertificateSigningRequest().Schema, "Unable to delete the Barracuda WAF resource (%s)", name)
+	}
+
+	return nil
+}
+
+func hydrateBarracudaWAFCertificateSigningRequestResource(d *schema.ResourceData) *waf.Certificate {
+	return &waf.Certificate{
+		Name:                  d.Get("name").(string),
+		CommonName:            d.Get("common_name").(string),
+		CountryCode:           d.Get("country_code").(string),
+		State:                 d.Get("state").(string),
+		City:                  d.Get("city").(string),
+		OrganizationName:      d.Get("organization_name").(string),
+		OrganizationalUnit:    d.Get("organizational_unit").(string),
+		KeyType:               d.Get("key_type").(string),
+		KeySize:               d.Get("key_size").(string),
+		EllipticCurveName:     d.Get("elliptic_curve_name").(string),
+		SANCertificate:        expandStringList(d.Get("san_certificate").([]interface{})),
+		AllowPrivateKeyExport: d.Get("allow_private_key_export").(string),
+	}
+}
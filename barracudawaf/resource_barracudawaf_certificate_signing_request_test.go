@@ -0,0 +1,225 @@
+package barracudawaf
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+var CERTIFICATE_SIGNING_REQUEST_RESOURCE_CREATE = BARRACUDA_WAF_PROVIDER + `
+resource "barracudawaf_certificate_signing_request" "demo_csr" {
+    name                = "DemoCSR"
+    common_name         = "www.example.com"
+    country_code        = "US"
+    organization_name   = "Example"
+    key_type            = "rsa"
+    key_size            = "2048"
+    san_certificate     = [ "DNS:example.com" ]
+}
+`
+
+func TestAccBarracudaWAFCertificateSigningRequest_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAcctPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: CERTIFICATE_SIGNING_REQUEST_RESOURCE_CREATE,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckCertificateSigningRequestExists("DemoCSR"),
+					resource.TestCheckResourceAttr("barracudawaf_certificate_signing_request.demo_csr", "name", "DemoCSR"),
+					resource.TestCheckResourceAttrSet("barracudawaf_certificate_signing_request.demo_csr", "certificate_request_pem"),
+				),
+			},
+		},
+	})
+}
+
+func testCheckCertificateSigningRequestExists(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*waf.Client)
+
+		if _, err := client.GetCertificate(context.Background(), waf.CertificateRequests, name); err != nil {
+			return fmt.Errorf("certificate signing request (%s) not found on the system (%v)", name, err)
+		}
+
+		return nil
+	}
+}
+
+func TestBarracudaWAFCertificateSigningRequest_crud(t *testing.T) {
+	crud := newTestResourceCRUD(t, resourceCudaWAFCertificateSigningRequest())
+
+	raw := map[string]interface{}{
+		"name":            "DemoCSR",
+		"common_name":     "www.example.com",
+		"country_code":    "US",
+		"key_type":        "rsa",
+		"key_size":        "2048",
+		"san_certificate": []interface{}{"DNS:example.com"},
+	}
+
+	d := crud.create(raw)
+
+	testCheckParams(t, crud.server.Object("certificate-request/DemoCSR"), map[string]interface{}{
+		"common-name":     "www.example.com",
+		"country-code":    "US",
+		"key-type":        "rsa",
+		"san-certificate": []interface{}{"DNS:example.com"},
+	})
+
+	if _, err := parseBarracudaWAFCertificateRequestKey(d.Get("certificate_request_pem").(string)); err != nil {
+		t.Errorf("expected the certificate request generated by the WAF to be read, got %v", err)
+	}
+
+	leaf := newTestBarracudaWAFCertificate(t, "www.example.com", nil)
+	request := leaf.certificateRequest(t)
+
+	object := crud.server.Object("certificate-request/DemoCSR")
+	object["certificate-request"] = request
+	crud.server.SetObject("certificate-request/DemoCSR", object)
+
+	d = crud.read(d.Id(), raw)
+	testCheckAttributes(t, d, map[string]string{
+		"common_name":             "www.example.com",
+		"certificate_request_pem": request,
+	})
+
+	crud.delete(d.Id(), raw)
+	if crud.server.Object("certificate-request/DemoCSR") != nil {
+		t.Fatalf("expected the certificate signing request to be deleted")
+	}
+
+	if d = crud.read(d.Id(), raw); d.Id() != "" {
+		t.Errorf("expected the deleted certificate signing request to be removed from the state")
+	}
+}
+
+func TestBarracudaWAFCertificateSigningRequest_signed(t *testing.T) {
+	crud := newTestResourceCRUD(t, resourceCudaWAFCertificateSigningRequest())
+
+	d := crud.create(map[string]interface{}{
+		"name":         "DemoCSR",
+		"common_name":  "www.example.com",
+		"country_code": "US",
+	})
+
+	// the fake WAF keeps the key of the request private, use a request of a known key to sign it
+	leaf := newTestBarracudaWAFCertificate(t, "www.example.com", nil)
+	request := leaf.certificateRequest(t)
+
+	object := crud.server.Object("certificate-request/DemoCSR")
+	object["certificate-request"] = request
+	crud.server.SetObject("certificate-request/DemoCSR", object)
+
+	if diags := crud.resource.ReadContext(context.Background(), d, crud.client); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+
+	certificates := crud.with(resourceCudaWAFSignedCertificate())
+	certificates.create(map[string]interface{}{
+		"name":                     "DemoSignedCert",
+		"signed_certificate":       string(leaf.pem),
+		"certificate_request_name": "DemoCSR",
+	})
+
+	if consumed := crud.server.Object("certificate-request/DemoCSR")["certificate-request"]; consumed != "" {
+		t.Fatalf("expected the WAF to clear the certificate request once its key is assigned, got %v", consumed)
+	}
+
+	if diags := crud.resource.ReadContext(context.Background(), d, crud.client); diags.HasError() {
+		t.Fatalf("expected the certificate request assigned to a certificate to be read, got %v", diags)
+	}
+
+	testCheckAttributes(t, d, map[string]string{
+		"name":                    "DemoCSR",
+		"certificate_request_pem": request,
+	})
+
+	other := schema.TestResourceDataRaw(t, certificates.resource.Schema, map[string]interface{}{
+		"name":                     "DemoSignedCert2",
+		"signed_certificate":       string(leaf.pem),
+		"certificate_request_name": "DemoCSR",
+	})
+
+	diags := certificates.resource.CreateContext(context.Background(), other, crud.client)
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "is already assigned to a signed certificate") {
+		t.Fatalf("expected the upload to fail as the key of the request is assigned already, got %v", diags)
+	}
+}
+
+func TestBarracudaWAFCertificateSigningRequest_replaceSignedCertificate(t *testing.T) {
+	crud := newTestResourceCRUD(t, resourceCudaWAFCertificateSigningRequest())
+	certificates := crud.with(resourceCudaWAFSignedCertificate())
+
+	requestConfig := map[string]interface{}{
+		"name":         "DemoCSR",
+		"common_name":  "www.example.com",
+		"country_code": "US",
+	}
+
+	// the fake WAF keeps the key of the request private, use a request of a known key to sign it
+	createRequest := func(leaf *testBarracudaWAFCertificate) {
+		crud.create(requestConfig)
+
+		object := crud.server.Object("certificate-request/DemoCSR")
+		object["certificate-request"] = leaf.certificateRequest(t)
+		crud.server.SetObject("certificate-request/DemoCSR", object)
+	}
+
+	leaf := newTestBarracudaWAFCertificate(t, "www.example.com", nil)
+	createRequest(leaf)
+
+	raw := map[string]interface{}{
+		"name":                     "DemoSignedCert",
+		"signed_certificate":       string(leaf.pem),
+		"certificate_request_name": "DemoCSR",
+	}
+
+	state := certificates.create(raw).State()
+
+	// the certificate is planned once the key of the request is assigned to it
+	diff, err := certificates.resource.Diff(context.Background(), state, terraform.NewResourceConfigRaw(raw), crud.client)
+	if err != nil || (diff != nil && len(diff.Attributes) > 0) {
+		t.Fatalf("expected no change of the certificate, got %v (%v)", diff, err)
+	}
+
+	// a new certificate needs a new key, so a new certificate signing request
+	renewed := newTestBarracudaWAFCertificate(t, "www.example.com", nil)
+	raw["signed_certificate"] = string(renewed.pem)
+
+	_, err = certificates.resource.Diff(context.Background(), state, terraform.NewResourceConfigRaw(raw), crud.client)
+	if err == nil || !strings.Contains(err.Error(), "replace the certificate request") {
+		t.Fatalf("expected the plan to ask for a new certificate signing request, got %v", err)
+	}
+
+	crud.delete("DemoCSR", requestConfig)
+	createRequest(renewed)
+
+	diff, err = certificates.resource.Diff(context.Background(), state, terraform.NewResourceConfigRaw(raw), crud.client)
+	if err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	if !diff.RequiresNew() {
+		t.Fatalf("expected the certificate to be replaced, got %v", diff)
+	}
+
+	if _, diags := certificates.resource.Apply(context.Background(), state, diff, crud.client); diags.HasError() {
+		t.Fatalf("apply: %v", diags)
+	}
+
+	uploaded, _ := crud.server.Object("signed-certificate/DemoSignedCert")["signed-certificate"].(string)
+	if string(decodeBarracudaWAFContent([]byte(uploaded))) != string(renewed.pem) {
+		t.Errorf("expected the certificate signed for the new request to be uploaded, got %q", uploaded)
+	}
+
+	if consumed := crud.server.Object("certificate-request/DemoCSR")["certificate-request"]; consumed != "" {
+		t.Errorf("expected the key of the new request to be assigned to the certificate, got %v", consumed)
+	}
+}
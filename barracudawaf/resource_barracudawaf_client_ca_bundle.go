@@ -0,0 +1,443 @@
+package barracudawaf
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceCudaWAFClientCABundle() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceCudaWAFClientCABundleCreate,
+		ReadContext:   resourceCudaWAFClientCABundleRead,
+		UpdateContext: resourceCudaWAFClientCABundleUpdate,
+		DeleteContext: resourceCudaWAFClientCABundleDelete,
+		CustomizeDiff: resourceCudaWAFClientCABundleCustomizeDiff,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: importBarracudaWAFClientCABundle,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Read:   schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Bundle Name, prefix of the names of the uploaded trusted CA certificates",
+				ForceNew:    true,
+			},
+			"certificates": {
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressBarracudaWAFEquivalentClientCABundle,
+				Description:      "PEM CA certificates trusted to issue client certificates",
+				ForceNew:         true,
+			},
+			"service_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Service whose client authentication trusts the certificates",
+			},
+			"certificate_names": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Names of the uploaded trusted CA certificates",
+			},
+			"certificate_details": barracudaWAFCertificateDetailsSchema(),
+			"expiry": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Earliest expiry of the certificates of the bundle",
+			},
+		},
+
+		Description: "`barracudawaf_client_ca_bundle` manages a bundle of `Trusted CA Certificates` used for client certificate authentication on the Barracuda Web Application Firewall.",
+	}
+}
+
+func resourceCudaWAFClientCABundleCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
+
+	name := d.Get("name").(string)
+
+	log.Println("[INFO] Creating Barracuda WAF resource " + name)
+
+	blocks, certificates, err := parseBarracudaWAFClientCABundle(d.Get("certificates").(string))
+
+	if err != nil {
+		log.Printf("[ERROR] Invalid certificates for Barracuda WAF resource (%s) (%v) ", name, err)
+		return diag.Errorf("Invalid certificates for Barracuda WAF resource (%s): %v", name, err)
+	}
+
+	var names []string
+	for i, certificate := range certificates {
+		certificateName := barracudaWAFClientCACertificateName(name, certificate)
+
+		err := client.CreateCertificate(ctx, waf.TrustedCACertificates, &waf.Certificate{
+			Name:        certificateName,
+			Certificate: base64.StdEncoding.EncodeToString(pem.EncodeToMemory(blocks[i])),
+		})
+
+		if err != nil {
+			log.Printf("[ERROR] Unable to create Barracuda WAF resource (%s) (%v) ", certificateName, err)
+			deleteBarracudaWAFClientCACertificates(ctx, client, names)
+			return barracudaWAFDiagnostics(err, resourceCudaWAFClientCABundle().Schema, "Unable to create Barracuda WAF resource (%s)", certificateName)
+		}
+
+		names = append(names, certificateName)
+	}
+
+	if service := d.Get("service_name").(string); service != "" {
+		err := updateBarracudaWAFClientAuthentication(ctx, client, service, names, nil)
+
+		if err != nil {
+			log.Printf("[ERROR] Unable to update the Barracuda WAF resource (%s) (%v) ", service, err)
+			deleteBarracudaWAFClientCACertificates(ctx, client, names)
+			return barracudaWAFDiagnostics(err, resourceCudaWAFClientCABundle().Schema, "Unable to update the Barracuda WAF resource (%s)", service)
+		}
+	}
+
+	d.SetId(name)
+	return resourceCudaWAFClientCABundleRead(ctx, d, m)
+}
+
+func resourceCudaWAFClientCABundleRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
+
+	name := d.Id()
+	log.Println("[INFO] Fetching Barracuda WAF resource " + name)
+
+	// the certificates of the state, in case the WAF does not return the certificates themselves
+	stateBlocks := make(map[string][]byte)
+	if blocks, certificates, err := parseBarracudaWAFPEMBundle(d.Get("certificates").(string)); err == nil {
+		for i, certificate := range certificates {
+			stateBlocks[barracudaWAFClientCACertificateName(name, certificate)] = pem.EncodeToMemory(blocks[i])
+		}
+	}
+
+	var names []string
+	var bundle []byte
+	for _, certificateName := range barracudaWAFClientCACertificateNames(d) {
+		certificate, err := client.GetCertificate(ctx, waf.TrustedCACertificates, certificateName)
+
+		if waf.IsNotFound(err) {
+			log.Printf("[WARN] Barracuda WAF resource (%s) not found, removing from the bundle", certificateName)
+			continue
+		}
+
+		if err != nil {
+			log.Printf("[ERROR] Unable to Retrieve Barracuda WAF resource (%s) (%v) ", certificateName, err)
+			return barracudaWAFDiagnostics(err, resourceCudaWAFClientCABundle().Schema, "Unable to Retrieve Barracuda WAF resource (%s)", certificateName)
+		}
+
+		content := decodeBarracudaWAFContent([]byte(certificate.Certificate))
+		if _, _, err := parseBarracudaWAFPEMBundle(string(content)); err != nil {
+			content = stateBlocks[certificateName]
+		}
+
+		names = append(names, certificateName)
+		bundle = append(bundle, content...)
+	}
+
+	if len(names) == 0 {
+		log.Printf("[WARN] Barracuda WAF resource (%s) not found, removing from state", name)
+		d.SetId("")
+		return nil
+	}
+
+	if _, certificates, err := parseBarracudaWAFPEMBundle(string(bundle)); err == nil {
+		details, expiry := flattenBarracudaWAFCertificateDetails(certificates)
+		d.Set("certificates", string(bundle))
+		d.Set("certificate_details", details)
+		d.Set("expiry", expiry)
+	}
+
+	if service := d.Get("service_name").(string); service != "" {
+		trusted, err := isBarracudaWAFClientAuthenticationTrusting(ctx, client, service, names)
+
+		if err != nil && !waf.IsNotFound(err) {
+			log.Printf("[ERROR] Unable to Retrieve Barracuda WAF sub resource (%s) (%v) ", service, err)
+			return barracudaWAFDiagnostics(err, resourceCudaWAFClientCABundle().Schema, "Unable to Retrieve Barracuda WAF sub resource (%s)", service)
+		}
+
+		// the service is updated to trust the certificates again
+		if !trusted {
+			log.Printf("[WARN] Barracuda WAF resource (%s) does not trust the bundle (%s) for client authentication", service, name)
+			d.Set("service_name", "")
+		}
+	}
+
+	d.Set("certificate_names", names)
+	d.Set("name", name)
+	return nil
+}
+
+func resourceCudaWAFClientCABundleUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
+
+	name := d.Id()
+
+	log.Println("[INFO] Updating Barracuda WAF resource " + name)
+
+	names := barracudaWAFClientCACertificateNames(d)
+
+	if d.HasChange("service_name") {
+		oldService, newService := d.GetChange("service_name")
+
+		if service := oldService.(string); service != "" && service != newService.(string) {
+			err := updateBarracudaWAFClientAuthentication(ctx, client, service, nil, names)
+
+			if err != nil && !waf.IsNotFound(err) {
+				log.Printf("[ERROR] Unable to update the Barracuda WAF resource (%s) (%v) ", service, err)
+				return barracudaWAFDiagnostics(err, resourceCudaWAFClientCABundle().Schema, "Unable to update the Barracuda WAF resource (%s)", service)
+			}
+		}
+
+		if service := newService.(string); service != "" {
+			err := updateBarracudaWAFClientAuthentication(ctx, client, service, names, nil)
+
+			if err != nil {
+				log.Printf("[ERROR] Unable to update the Barracuda WAF resource (%s) (%v) ", service, err)
+				return barracudaWAFDiagnostics(err, resourceCudaWAFClientCABundle().Schema, "Unable to update the Barracuda WAF resource (%s)", service)
+			}
+		}
+	}
+
+	return resourceCudaWAFClientCABundleRead(ctx, d, m)
+}
+
+func resourceCudaWAFClientCABundleCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.NewValueKnown("certificates") {
+		if _, _, err := parseBarracudaWAFClientCABundle(d.Get("certificates").(string)); err != nil {
+			return fmt.Errorf("invalid certificates: %v", err)
+		}
+	}
+
+	return setNewBarracudaWAFCertificateDetails(d, "certificates")
+}
+
+func resourceCudaWAFClientCABundleDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
+
+	name := d.Id()
+
+	log.Println("[INFO] Deleting Barracuda WAF resource " + name)
+
+	names := barracudaWAFClientCACertificateNames(d)
+
+	if service := d.Get("service_name").(string); service != "" {
+		err := updateBarracudaWAFClientAuthentication(ctx, client, service, nil, names)
+
+		if err != nil && !waf.IsNotFound(err) {
+			return barracudaWAFDiagnostics(err, resourceCudaWAFClientCABundle().Schema, "Unable to update the Barracuda WAF resource (%s)", service)
+		}
+	}
+
+	if err := deleteBarracudaWAFClientCACertificates(ctx, client, names); err != nil {
+		return barracudaWAFDiagnostics(err, resourceCudaWAFClientCABundle().Schema, "Unable to delete the Barracuda WAF resource (%s)", name)
+	}
+
+	return nil
+}
+
+// importBarracudaWAFClientCABundle : imports the bundle from its trusted CA certificates. The
+// import ID is the name of the bundle, optionally preceded by the service trusting it, e.g.
+// "DemoApp1/DemoClientCAs".
+func importBarracudaWAFClientCABundle(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := m.(*waf.Client)
+
+	name := d.Id()
+	if strings.Contains(name, "/") {
+		parts, err := splitBarracudaWAFImportID(name, []string{"service_name"})
+		if err != nil {
+			return nil, err
+		}
+
+		d.Set("service_name", parts[0])
+		name = parts[1]
+	}
+
+	certificates, err := client.ListCertificates(ctx, waf.TrustedCACertificates)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, certificate := range certificates {
+		if isBarracudaWAFClientCACertificateName(name, certificate.Name) {
+			names = append(names, certificate.Name)
+		}
+	}
+
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no trusted CA certificate of the bundle (%s) found", name)
+	}
+
+	d.Set("certificate_names", names)
+	d.Set("name", name)
+	d.SetId(name)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// parseBarracudaWAFClientCABundle : parses the PEM bundle, every certificate must be a CA.
+func parseBarracudaWAFClientCABundle(content string) ([]*pem.Block, []*x509.Certificate, error) {
+	blocks, certificates, err := parseBarracudaWAFPEMBundle(content)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, certificate := range certificates {
+		if !certificate.BasicConstraintsValid || !certificate.IsCA {
+			return nil, nil, fmt.Errorf("certificate %q is not a CA", certificate.Subject)
+		}
+	}
+
+	return blocks, certificates, nil
+}
+
+// suppressBarracudaWAFEquivalentClientCABundle : suppresses the diff of bundles holding the same
+// CA certificates, whatever their order, e.g. the one of an imported bundle.
+func suppressBarracudaWAFEquivalentClientCABundle(k, old, new string, d *schema.ResourceData) bool {
+	_, oldCertificates, err := parseBarracudaWAFPEMBundle(old)
+	if err != nil {
+		return false
+	}
+
+	_, newCertificates, err := parseBarracudaWAFPEMBundle(new)
+	if err != nil || len(oldCertificates) != len(newCertificates) {
+		return false
+	}
+
+	fingerprints := make(map[string]bool)
+	for _, certificate := range oldCertificates {
+		fingerprints[barracudaWAFFingerprint(certificate)] = true
+	}
+
+	for _, certificate := range newCertificates {
+		if !fingerprints[barracudaWAFFingerprint(certificate)] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// barracudaWAFClientCACertificateName : name of the trusted CA certificate uploaded for a
+// certificate of the bundle, stable as long as the certificate is.
+func barracudaWAFClientCACertificateName(name string, certificate *x509.Certificate) string {
+	return fmt.Sprintf("%s-%s", name, barracudaWAFFingerprint(certificate)[:8])
+}
+
+// isBarracudaWAFClientCACertificateName : reports whether the trusted CA certificate was
+// uploaded for a certificate of the named bundle.
+func isBarracudaWAFClientCACertificateName(name string, certificateName string) bool {
+	fingerprint := strings.TrimPrefix(certificateName, name+"-")
+	if len(fingerprint) != 8 || fingerprint == certificateName {
+		return false
+	}
+
+	_, err := hex.DecodeString(fingerprint)
+	return err == nil
+}
+
+// barracudaWAFClientCACertificateNames : names of the trusted CA certificates of the bundle,
+// the ones in the state when the certificates cannot be parsed.
+func barracudaWAFClientCACertificateNames(d *schema.ResourceData) []string {
+	_, certificates, err := parseBarracudaWAFPEMBundle(d.Get("certificates").(string))
+	if err != nil {
+		return expandStringList(d.Get("certificate_names").([]interface{}))
+	}
+
+	names := make([]string, 0, len(certificates))
+	for _, certificate := range certificates {
+		names = append(names, barracudaWAFClientCACertificateName(d.Id(), certificate))
+	}
+
+	return names
+}
+
+// updateBarracudaWAFClientAuthentication : adds and removes trusted certificates of the client
+// authentication of the service, leaving the others in place. The bundles of the same service
+// are updated one at a time, so that they do not drop the certificates of each other.
+func updateBarracudaWAFClientAuthentication(ctx context.Context, client *waf.Client, service string, add, remove []string) error {
+	return client.ModifyServiceSSLClientAuthentication(ctx, service, func(clientAuthentication *waf.ServiceSSLClientAuthentication) error {
+		removed := make(map[string]bool)
+		for _, name := range append(remove, add...) {
+			removed[name] = true
+		}
+
+		trusted := append([]string{}, add...)
+		for _, name := range clientAuthentication.TrustedCertificates {
+			if !removed[name] {
+				trusted = append(trusted, name)
+			}
+		}
+
+		clientAuthentication.TrustedCertificates = trusted
+		if len(add) > 0 {
+			clientAuthentication.EnableClientAuthentication = "Yes"
+		}
+
+		// client authentication cannot be left enabled without any trusted certificate
+		if len(trusted) == 0 {
+			clientAuthentication.EnableClientAuthentication = "No"
+		}
+
+		return nil
+	})
+}
+
+// isBarracudaWAFClientAuthenticationTrusting : reports whether the client authentication of the
+// service is enabled and trusts all the certificates.
+func isBarracudaWAFClientAuthenticationTrusting(ctx context.Context, client *waf.Client, service string, names []string) (bool, error) {
+	clientAuthentication, err := client.GetServiceSSLClientAuthentication(ctx, service)
+	if err != nil {
+		return false, err
+	}
+
+	if clientAuthentication.EnableClientAuthentication == "No" {
+		return false, nil
+	}
+
+	trusted := make(map[string]bool)
+	for _, name := range clientAuthentication.TrustedCertificates {
+		trusted[name] = true
+	}
+
+	for _, name := range names {
+		if !trusted[name] {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// deleteBarracudaWAFClientCACertificates : deletes the uploaded trusted CA certificates, the
+// ones already gone are ignored.
+func deleteBarracudaWAFClientCACertificates(ctx context.Context, client *waf.Client, names []string) error {
+	for _, name := range names {
+		if err := client.DeleteCertificate(ctx, waf.TrustedCACertificates, name); err != nil && !waf.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,371 @@
+package barracudawaf
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+var CLIENT_CA_BUNDLE_RESOURCE_CREATE = BARRACUDA_WAF_PROVIDER + `
+resource "barracudawaf_client_ca_bundle" "demo_client_cas" {
+    name         = "DemoClientCAs"
+    certificates = file("certificates/client-cas.pem")
+    service_name = "DemoApp1"
+}
+`
+
+func TestAccBarracudaWAFClientCABundle_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAcctPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: CLIENT_CA_BUNDLE_RESOURCE_CREATE,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckClientCABundleExists("barracudawaf_client_ca_bundle.demo_client_cas"),
+					resource.TestCheckResourceAttr("barracudawaf_client_ca_bundle.demo_client_cas", "name", "DemoClientCAs"),
+					resource.TestCheckResourceAttrSet("barracudawaf_client_ca_bundle.demo_client_cas", "expiry"),
+				),
+			},
+		},
+	})
+}
+
+func testCheckClientCABundleExists(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*waf.Client)
+
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("client CA bundle (%s) not found in the state", name)
+		}
+
+		certificateName := rs.Primary.Attributes["certificate_names.0"]
+		if _, err := client.GetCertificate(context.Background(), waf.TrustedCACertificates, certificateName); err != nil {
+			return fmt.Errorf("trusted CA certificate (%s) not found on the system (%v)", certificateName, err)
+		}
+
+		return nil
+	}
+}
+
+func TestBarracudaWAFClientCABundle_crud(t *testing.T) {
+	crud := newTestResourceCRUD(t, resourceCudaWAFClientCABundle())
+
+	root := newTestBarracudaWAFCertificate(t, "Root CA", nil)
+	intermediate := newTestBarracudaWAFCertificate(t, "Intermediate CA", root)
+
+	crud.server.SetObject("services/DemoApp1", map[string]interface{}{"name": "DemoApp1"})
+
+	raw := map[string]interface{}{
+		"name":         "DemoClientCAs",
+		"certificates": string(root.pem) + string(intermediate.pem),
+		"service_name": "DemoApp1",
+	}
+
+	d := crud.create(raw)
+
+	names := []string{
+		"DemoClientCAs-" + barracudaWAFFingerprint(root.certificate)[:8],
+		"DemoClientCAs-" + barracudaWAFFingerprint(intermediate.certificate)[:8],
+	}
+
+	testCheckAttributes(t, d, map[string]string{
+		"certificate_names.#":           "2",
+		"certificate_names.0":           names[0],
+		"certificate_details.1.subject": "CN=Intermediate CA",
+	})
+
+	testCheckParams(t, crud.server.Object("trusted-ca-certificate/"+names[0]), map[string]interface{}{
+		"certificate": base64.StdEncoding.EncodeToString(root.pem),
+	})
+
+	clientAuthentication := crud.server.SubResource("services/DemoApp1", "ssl-client-authentication")
+	testCheckParams(t, clientAuthentication, map[string]interface{}{
+		"enable-client-authentication": "Yes",
+		"trusted-certificates":         []interface{}{names[0], names[1]},
+	})
+
+	crud.delete(d.Id(), raw)
+
+	for _, name := range names {
+		if crud.server.Object("trusted-ca-certificate/"+name) != nil {
+			t.Errorf("expected the trusted CA certificate %s to be deleted", name)
+		}
+	}
+
+	testCheckParams(t, crud.server.SubResource("services/DemoApp1", "ssl-client-authentication"), map[string]interface{}{
+		"enable-client-authentication": "No",
+		"trusted-certificates":         []interface{}{},
+	})
+
+	if d = crud.read(d.Id(), raw); d.Id() != "" {
+		t.Errorf("expected the deleted bundle to be removed from the state")
+	}
+}
+
+func TestBarracudaWAFClientCABundle_drift(t *testing.T) {
+	crud := newTestResourceCRUD(t, resourceCudaWAFClientCABundle())
+
+	root := newTestBarracudaWAFCertificate(t, "Root CA", nil)
+	intermediate := newTestBarracudaWAFCertificate(t, "Intermediate CA", root)
+
+	crud.server.SetObject("services/DemoApp1", map[string]interface{}{"name": "DemoApp1"})
+
+	raw := map[string]interface{}{
+		"name":         "DemoClientCAs",
+		"certificates": string(root.pem) + string(intermediate.pem),
+		"service_name": "DemoApp1",
+	}
+
+	d := crud.create(raw)
+	names := expandStringList(d.Get("certificate_names").([]interface{}))
+
+	// certificates the WAF does not return are read back from the state
+	crud.server.SetObject("trusted-ca-certificate/"+names[0], map[string]interface{}{"name": names[0]})
+
+	d = crud.read(d.Id(), raw)
+	testCheckAttributes(t, d, map[string]string{
+		"certificates":        string(root.pem) + string(intermediate.pem),
+		"service_name":        "DemoApp1",
+		"certificate_names.#": "2",
+	})
+
+	crud.server.SetObject("trusted-ca-certificate/"+names[0], nil)
+	if err := crud.client.DeleteCertificate(context.Background(), waf.TrustedCACertificates, names[1]); err != nil {
+		t.Fatalf("unable to delete the trusted CA certificate: %v", err)
+	}
+
+	d = crud.read(d.Id(), raw)
+	testCheckAttributes(t, d, map[string]string{
+		"certificates":                  string(root.pem),
+		"certificate_names.#":           "1",
+		"certificate_names.0":           names[0],
+		"certificate_details.#":         "1",
+		"certificate_details.0.subject": "CN=Root CA",
+	})
+
+	crud.server.SetObject("trusted-ca-certificate/"+names[0], map[string]interface{}{
+		"name":        names[0],
+		"certificate": base64.StdEncoding.EncodeToString(root.pem),
+	})
+	crud.server.SetObject("trusted-ca-certificate/"+names[1], map[string]interface{}{
+		"name":        names[1],
+		"certificate": base64.StdEncoding.EncodeToString(intermediate.pem),
+	})
+
+	d = crud.read(d.Id(), raw)
+	testCheckAttributes(t, d, map[string]string{
+		"certificates":                  string(root.pem) + string(intermediate.pem),
+		"certificate_details.1.subject": "CN=Intermediate CA",
+		"service_name":                  "DemoApp1",
+	})
+
+	cases := map[string]*waf.ServiceSSLClientAuthentication{
+		"untrusted": {EnableClientAuthentication: "Yes", TrustedCertificates: names[:1]},
+		"disabled":  {EnableClientAuthentication: "No", TrustedCertificates: names},
+	}
+
+	for name, clientAuthentication := range cases {
+		if err := crud.client.UpdateServiceSSLClientAuthentication(context.Background(), "DemoApp1", clientAuthentication); err != nil {
+			t.Fatalf("%s: unable to update the client authentication: %v", name, err)
+		}
+
+		if d := crud.read(d.Id(), raw); d.Get("service_name") != "" {
+			t.Errorf("%s: expected the service to be cleared to trust the bundle again, got %v", name, d.Get("service_name"))
+		}
+
+		diff, err := crud.resource.Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(raw), crud.client)
+		if err != nil {
+			t.Fatalf("%s: diff: %v", name, err)
+		}
+		if diff.RequiresNew() {
+			t.Errorf("%s: expected the service to trust the bundle again without replacing it", name)
+		}
+
+		d = crud.update(d.Id(), raw)
+		testCheckAttributes(t, d, map[string]string{"service_name": "DemoApp1"})
+
+		testCheckParams(t, crud.server.SubResource("services/DemoApp1", "ssl-client-authentication"), map[string]interface{}{
+			"enable-client-authentication": "Yes",
+			"trusted-certificates":         []interface{}{names[0], names[1]},
+		})
+	}
+}
+
+func TestBarracudaWAFClientCABundle_updateService(t *testing.T) {
+	crud := newTestResourceCRUD(t, resourceCudaWAFClientCABundle())
+
+	root := newTestBarracudaWAFCertificate(t, "Root CA", nil)
+
+	crud.server.SetObject("services/DemoApp1", map[string]interface{}{"name": "DemoApp1"})
+	crud.server.SetObject("services/DemoApp2", map[string]interface{}{"name": "DemoApp2"})
+
+	raw := map[string]interface{}{
+		"name":         "DemoClientCAs",
+		"certificates": string(root.pem),
+		"service_name": "DemoApp1",
+	}
+
+	d := crud.create(raw)
+	names := d.Get("certificate_names").([]interface{})
+
+	raw["service_name"] = "DemoApp2"
+	diff, err := crud.resource.Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(raw), crud.client)
+	if err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	if diff.RequiresNew() {
+		t.Errorf("expected the bundle to move to the other service without replacing it")
+	}
+
+	if _, diags := crud.resource.Apply(context.Background(), d.State(), diff, crud.client); diags.HasError() {
+		t.Fatalf("apply: %v", diags)
+	}
+
+	testCheckParams(t, crud.server.SubResource("services/DemoApp1", "ssl-client-authentication"), map[string]interface{}{
+		"enable-client-authentication": "No",
+		"trusted-certificates":         []interface{}{},
+	})
+	testCheckParams(t, crud.server.SubResource("services/DemoApp2", "ssl-client-authentication"), map[string]interface{}{
+		"enable-client-authentication": "Yes",
+		"trusted-certificates":         names,
+	})
+
+	if crud.server.Object("trusted-ca-certificate/"+names[0].(string)) == nil {
+		t.Errorf("expected the trusted CA certificate to be kept")
+	}
+}
+
+func TestBarracudaWAFClientCABundle_concurrentBundles(t *testing.T) {
+	crud := newTestResourceCRUD(t, resourceCudaWAFClientCABundle())
+
+	crud.server.SetObject("services/DemoApp1", map[string]interface{}{"name": "DemoApp1"})
+
+	var configs []map[string]interface{}
+	for i := 0; i < 4; i++ {
+		root := newTestBarracudaWAFCertificate(t, fmt.Sprintf("Root CA %d", i), nil)
+		configs = append(configs, map[string]interface{}{
+			"name":         fmt.Sprintf("DemoClientCAs%d", i),
+			"certificates": string(root.pem),
+			"service_name": "DemoApp1",
+		})
+	}
+
+	var mutex sync.Mutex
+	var names []interface{}
+
+	var wg sync.WaitGroup
+	for _, raw := range configs {
+		wg.Add(1)
+		go func(raw map[string]interface{}) {
+			defer wg.Done()
+
+			d := schema.TestResourceDataRaw(t, crud.resource.Schema, raw)
+			if diags := crud.resource.CreateContext(context.Background(), d, crud.client); diags.HasError() {
+				t.Errorf("create %s: %v", raw["name"], diags)
+				return
+			}
+
+			mutex.Lock()
+			names = append(names, d.Get("certificate_names").([]interface{})...)
+			mutex.Unlock()
+		}(raw)
+	}
+	wg.Wait()
+
+	trusted := crud.server.SubResource("services/DemoApp1", "ssl-client-authentication")["trusted-certificates"]
+	if got, ok := trusted.([]interface{}); !ok || len(got) != len(names) {
+		t.Fatalf("expected the service to trust the certificates of every bundle %v, got %v", names, trusted)
+	}
+
+	for _, name := range names {
+		if !strings.Contains(fmt.Sprint(trusted), name.(string)) {
+			t.Errorf("expected the service to trust %s, got %v", name, trusted)
+		}
+	}
+}
+
+func TestBarracudaWAFClientCABundle_import(t *testing.T) {
+	crud := newTestResourceCRUD(t, resourceCudaWAFClientCABundle())
+
+	root := newTestBarracudaWAFCertificate(t, "Root CA", nil)
+	intermediate := newTestBarracudaWAFCertificate(t, "Intermediate CA", root)
+
+	crud.server.SetObject("services/DemoApp1", map[string]interface{}{"name": "DemoApp1"})
+	crud.server.SetObject("trusted-ca-certificate/DemoClientCAsOther-1a2b3c4d", map[string]interface{}{"name": "DemoClientCAsOther-1a2b3c4d"})
+
+	raw := map[string]interface{}{
+		"name":         "DemoClientCAs",
+		"certificates": string(root.pem) + string(intermediate.pem),
+		"service_name": "DemoApp1",
+	}
+
+	created := crud.create(raw)
+
+	cases := map[string]string{
+		"DemoClientCAs":          "",
+		"DemoApp1/DemoClientCAs": "DemoApp1",
+	}
+
+	for id, service := range cases {
+		d := crud.resource.Data(nil)
+		d.SetId(id)
+
+		imported, err := crud.resource.Importer.StateContext(context.Background(), d, crud.client)
+		if err != nil {
+			t.Fatalf("%s: import: %v", id, err)
+		}
+
+		d = imported[0]
+		if diags := crud.resource.ReadContext(context.Background(), d, crud.client); diags.HasError() {
+			t.Fatalf("%s: read: %v", id, diags)
+		}
+
+		testCheckAttributes(t, d, map[string]string{
+			"name":                created.Get("name").(string),
+			"certificate_names.#": "2",
+			"service_name":        service,
+		})
+
+		// the certificates are read back in the order of their names
+		diff, err := crud.resource.Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(raw), crud.client)
+		if err != nil {
+			t.Fatalf("%s: diff: %v", id, err)
+		}
+		if diff.RequiresNew() {
+			t.Errorf("%s: expected the imported bundle to be kept, got %v", id, diff)
+		}
+	}
+
+	d := crud.resource.Data(nil)
+	d.SetId("DemoOtherCAs")
+	if _, err := crud.resource.Importer.StateContext(context.Background(), d, crud.client); err == nil {
+		t.Errorf("expected the import of an unknown bundle to fail")
+	}
+}
+
+func TestBarracudaWAFClientCABundle_notCA(t *testing.T) {
+	resource := resourceCudaWAFClientCABundle()
+
+	root := newTestBarracudaWAFCertificate(t, "Root CA", nil)
+	leaf := newTestBarracudaWAFCertificate(t, "www.example.com", root)
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":         "DemoClientCAs",
+		"certificates": string(root.pem) + string(leaf.pem),
+	})
+
+	_, err := resource.Diff(context.Background(), nil, config, nil)
+	if err == nil || !strings.Contains(err.Error(), "is not a CA") {
+		t.Fatalf("expected the plan to fail as the leaf is not a CA, got %v", err)
+	}
+}
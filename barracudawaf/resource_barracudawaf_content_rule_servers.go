@@ -1,53 +1,85 @@
 package barracudawaf
 
 import (
-	"fmt"
+	"context"
 	"log"
-	"strings"
 
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
-var (
-	subResourceContentRuleServersParams = map[string][]string{
-		"ssl_policy": {
-			"client_certificate",
-			"enable_ssl_compatibility_mode",
-			"validate_certificate",
-			"enable_https",
-			"enable_sni",
-			"enable_ssl_3",
-			"enable_tls_1",
-			"enable_tls_1_1",
-			"enable_tls_1_2",
-			"enable_tls_1_3",
+func resourceCudaWAFContentRuleServers() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceCudaWAFContentRuleServersCreate,
+		ReadContext:   resourceCudaWAFContentRuleServersRead,
+		UpdateContext: resourceCudaWAFContentRuleServersUpdate,
+		DeleteContext: resourceCudaWAFContentRuleServersDelete,
+		CustomizeDiff: resourceCudaWAFContentRuleServersCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: importBarracudaWAFNamedParentsResource("service_name", "content_rule_name"),
 		},
-		"connection_pooling": {
-			"keepalive_timeout",
-			"enable_connection_pooling",
+
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceCudaWAFServersV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: upgradeBarracudaWAFParentState("service_name", "content_rule_name"),
+			},
 		},
-	}
-)
 
-func resourceCudaWAFContentRuleServers() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceCudaWAFContentRuleServersCreate,
-		Read:   resourceCudaWAFContentRuleServersRead,
-		Update: resourceCudaWAFContentRuleServersUpdate,
-		Delete: resourceCudaWAFContentRuleServersDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Read:   schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
 
 		Schema: map[string]*schema.Schema{
-			"comments":        {Type: schema.TypeString, Optional: true, Description: "Comments"},
-			"name":            {Type: schema.TypeString, Optional: true, Description: "Web Server Name"},
-			"hostname":        {Type: schema.TypeString, Optional: true, Description: "Hostname"},
-			"identifier":      {Type: schema.TypeString, Optional: true, Description: "Identifier:"},
-			"ip_address":      {Type: schema.TypeString, Optional: true, Description: "IP Address"},
-			"address_version": {Type: schema.TypeString, Optional: true, Description: "Version"},
-			"port":            {Type: schema.TypeString, Optional: true, Description: "Port"},
-			"status":          {Type: schema.TypeString, Optional: true, Description: "Status"},
+			"comments": {Type: schema.TypeString, Optional: true, Description: "Comments"},
+			"name":     {Type: schema.TypeString, Required: true, Description: "Web Server Name"},
+			"hostname": {Type: schema.TypeString, Optional: true, Description: "Hostname"},
+			"identifier": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Identifier:",
+				ValidateFunc: validateBarracudaWAFServerIdentifier,
+			},
+			"ip_address": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "IP Address",
+				ValidateFunc: validateBarracudaWAFIPAddress,
+			},
+			"address_version": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Version",
+				ValidateFunc: validateBarracudaWAFAddressVersion,
+				ForceNew:     true,
+			},
+			"port": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Port",
+				ValidateFunc: validateBarracudaWAFPort,
+			},
+			"status": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Status",
+				ValidateFunc: validateBarracudaWAFServerStatus,
+			},
 			"ssl_policy": {
 				Type:     schema.TypeList,
 				Optional: true,
+				Computed: true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"client_certificate": {
@@ -56,63 +88,103 @@ func resourceCudaWAFContentRuleServers() *schema.Resource {
 							Description: "Client Certificate",
 						},
 						"enable_ssl_compatibility_mode": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: "Enable SSL Compatibility Mode",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "Enable SSL Compatibility Mode",
+							ValidateFunc: validateBarracudaWAFYesNo,
 						},
 						"validate_certificate": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: "Validate Server Certificate",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "Validate Server Certificate",
+							ValidateFunc: validateBarracudaWAFYesNo,
 						},
 						"enable_https": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: "Server uses SSL",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "Server uses SSL",
+							ValidateFunc: validateBarracudaWAFYesNo,
 						},
 						"enable_sni": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: "Enable SNI",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "Enable SNI",
+							ValidateFunc: validateBarracudaWAFYesNo,
 						},
 						"enable_ssl_3": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: "SSL 3.0 (Insecure)",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "SSL 3.0 (Insecure)",
+							ValidateFunc: validateBarracudaWAFYesNo,
 						},
 						"enable_tls_1": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: "TLS 1.0 (Insecure)",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "TLS 1.0 (Insecure)",
+							ValidateFunc: validateBarracudaWAFYesNo,
+						},
+						"enable_tls_1_1": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "TLS 1.1",
+							ValidateFunc: validateBarracudaWAFYesNo,
+						},
+						"enable_tls_1_2": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "TLS 1.2",
+							ValidateFunc: validateBarracudaWAFYesNo,
+						},
+						"enable_tls_1_3": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "TLS 1.3",
+							ValidateFunc: validateBarracudaWAFYesNo,
 						},
-						"enable_tls_1_1": {Type: schema.TypeString, Optional: true, Description: "TLS 1.1"},
-						"enable_tls_1_2": {Type: schema.TypeString, Optional: true, Description: "TLS 1.2"},
-						"enable_tls_1_3": {Type: schema.TypeString, Optional: true, Description: "TLS 1.3"},
 					},
 				},
 			},
 			"connection_pooling": {
 				Type:     schema.TypeList,
 				Optional: true,
+				Computed: true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"keepalive_timeout": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: "Keepalive Timeout",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "Keepalive Timeout",
+							ValidateFunc: validateBarracudaWAFIntString(0, 2147483647),
 						},
 						"enable_connection_pooling": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: "Enable Connection Pooling",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "Enable Connection Pooling",
+							ValidateFunc: validateBarracudaWAFYesNo,
 						},
 					},
 				},
 			},
-			"parent": {
-				Type:     schema.TypeList,
-				Elem:     &schema.Schema{Type: schema.TypeString},
-				Required: true,
+			"service_name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the service of the content rule",
+			},
+			"content_rule_name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the content rule the server is configured in",
 			},
 		},
 
@@ -120,191 +192,229 @@ func resourceCudaWAFContentRuleServers() *schema.Resource {
 	}
 }
 
-func resourceCudaWAFContentRuleServersCreate(d *schema.ResourceData, m interface{}) error {
-	client := m.(*BarracudaWAF)
+func resourceCudaWAFContentRuleServersCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
 
 	name := d.Get("name").(string)
+	service := d.Get("service_name").(string)
+	contentRule := d.Get("content_rule_name").(string)
 
 	log.Println("[INFO] Creating Barracuda WAF resource " + name)
 
-	resourceEndpoint := "/services/" + d.Get("parent.0").(string) + "/content-rules/" + d.Get("parent.1").(string) + "/content-rule-servers"
-	err := client.CreateBarracudaWAFResource(
-		name,
-		hydrateBarracudaWAFContentRuleServersResource(d, "post", resourceEndpoint),
-	)
+	err := checkBarracudaWAFContentRuleExists(ctx, client, service, contentRule)
 
 	if err != nil {
 		log.Printf("[ERROR] Unable to create Barracuda WAF resource (%s) (%v) ", name, err)
-		return err
+		return barracudaWAFDiagnostics(err, resourceCudaWAFContentRuleServers().Schema, "Unable to create Barracuda WAF resource (%s)", name)
 	}
 
-	err = client.hydrateBarracudaWAFContentRuleServersSubResource(d, name, resourceEndpoint)
+	err = client.CreateContentRuleServer(ctx, service, contentRule, hydrateBarracudaWAFContentRuleServersResource(d))
+
+	if err != nil {
+		log.Printf("[ERROR] Unable to create Barracuda WAF resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFContentRuleServers().Schema, "Unable to create Barracuda WAF resource (%s)", name)
+	}
+
+	err = hydrateBarracudaWAFContentRuleServersSubResource(ctx, client, d, service, contentRule, name)
 
 	if err != nil {
 		log.Printf("[ERROR] Unable to create Barracuda WAF sub resource (%s) (%v) ", name, err)
-		return err
+		return barracudaWAFDiagnostics(err, resourceCudaWAFContentRuleServers().Schema, "Unable to create Barracuda WAF sub resource (%s)", name)
 	}
 
 	d.SetId(name)
-	return resourceCudaWAFContentRuleServersRead(d, m)
+	return resourceCudaWAFContentRuleServersRead(ctx, d, m)
 }
 
-func resourceCudaWAFContentRuleServersRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(*BarracudaWAF)
+func resourceCudaWAFContentRuleServersRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
 
 	name := d.Id()
+	service := d.Get("service_name").(string)
+	contentRule := d.Get("content_rule_name").(string)
 	log.Println("[INFO] Fetching Barracuda WAF resource " + name)
 
-	resourceEndpoint := "/services/" + d.Get("parent.0").(string) + "/content-rules/" + d.Get("parent.1").(string) + "/content-rule-servers"
-	request := &APIRequest{
-		Method: "get",
-		URL:    resourceEndpoint,
-	}
+	server, err := client.GetContentRuleServer(ctx, service, contentRule, name)
 
-	var dataItems map[string]interface{}
-	resources, err := client.GetBarracudaWAFResource(name, request)
+	if waf.IsNotFound(err) {
+		log.Printf("[WARN] Barracuda WAF resource (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
 
 	if err != nil {
 		log.Printf("[ERROR] Unable to Retrieve Barracuda WAF resource (%s) (%v) ", name, err)
-		return err
+		return barracudaWAFDiagnostics(err, resourceCudaWAFContentRuleServers().Schema, "Unable to Retrieve Barracuda WAF resource (%s)", name)
 	}
 
-	if resources.Data == nil {
-		log.Printf("[WARN] Barracuda WAF resource (%s) not found, removing from state", d.Id())
-		d.SetId("")
-		return nil
-	}
+	resourceSchema := resourceCudaWAFContentRuleServers().Schema
+	err = setBarracudaWAFResourceData(d, resourceSchema, server, "service_name", "content_rule_name")
 
-	for _, dataItems = range resources.Data {
-		if dataItems["name"] == name {
-			break
-		}
+	if err != nil {
+		log.Printf("[ERROR] Unable to set Barracuda WAF resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFContentRuleServers().Schema, "Unable to set Barracuda WAF resource (%s)", name)
 	}
 
-	if dataItems["name"] != name {
-		return fmt.Errorf("Barracuda WAF resource (%s) not found on the system", name)
+	err = readBarracudaWAFContentRuleServersSubResource(ctx, client, d, resourceSchema, service, contentRule, name)
+
+	if err != nil {
+		log.Printf("[ERROR] Unable to Retrieve Barracuda WAF sub resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFContentRuleServers().Schema, "Unable to Retrieve Barracuda WAF sub resource (%s)", name)
 	}
 
 	d.Set("name", name)
 	return nil
 }
 
-func resourceCudaWAFContentRuleServersUpdate(d *schema.ResourceData, m interface{}) error {
-	client := m.(*BarracudaWAF)
+func resourceCudaWAFContentRuleServersUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
 
 	name := d.Id()
+	service := d.Get("service_name").(string)
+	contentRule := d.Get("content_rule_name").(string)
 
 	log.Println("[INFO] Updating Barracuda WAF resource " + name)
 
-	resourceEndpoint := "/services/" + d.Get("parent.0").(string) + "/content-rules/" + d.Get("parent.1").(string) + "/content-rule-servers"
-	err := client.UpdateBarracudaWAFResource(name, hydrateBarracudaWAFContentRuleServersResource(d, "put", resourceEndpoint))
+	err := client.UpdateContentRuleServer(ctx, service, contentRule, name, hydrateBarracudaWAFContentRuleServersResource(d))
 
 	if err != nil {
-		log.Printf("[ERROR] Unable to update the Barracuda WAF resource (%s) (%v)", name, err)
-		return err
+		log.Printf("[ERROR] Unable to update the Barracuda WAF resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFContentRuleServers().Schema, "Unable to update the Barracuda WAF resource (%s)", name)
 	}
 
-	err = client.hydrateBarracudaWAFContentRuleServersSubResource(d, name, resourceEndpoint)
+	name = renameBarracudaWAFResource(d)
+
+	err = hydrateBarracudaWAFContentRuleServersSubResource(ctx, client, d, service, contentRule, name)
 
 	if err != nil {
-		log.Printf("[ERROR] Unable to update the Barracuda WAF sub resource (%s) (%v)", name, err)
-		return err
+		log.Printf("[ERROR] Unable to update the Barracuda WAF sub resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFContentRuleServers().Schema, "Unable to update the Barracuda WAF sub resource (%s)", name)
 	}
 
-	return resourceCudaWAFContentRuleServersRead(d, m)
+	return resourceCudaWAFContentRuleServersRead(ctx, d, m)
 }
 
-func resourceCudaWAFContentRuleServersDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(*BarracudaWAF)
+func resourceCudaWAFContentRuleServersCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	oldService, newService := d.GetChange("service_name")
+	oldContentRule, newContentRule := d.GetChange("content_rule_name")
+
+	_, err := forceNewIfBarracudaWAFParentChanges(
+		ctx,
+		d,
+		m,
+		[]string{"service_name", "content_rule_name"},
+		waf.ContentRulePath(oldService.(string), oldContentRule.(string)),
+		waf.ContentRulePath(newService.(string), newContentRule.(string)),
+		func(ctx context.Context) error {
+			_, err := m.(*waf.Client).GetContentRule(ctx, oldService.(string), oldContentRule.(string))
+			return err
+		},
+	)
+
+	return err
+}
+
+func resourceCudaWAFContentRuleServersDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
 
 	name := d.Id()
+	service := d.Get("service_name").(string)
+	contentRule := d.Get("content_rule_name").(string)
 
 	log.Println("[INFO] Deleting Barracuda WAF resource " + name)
 
-	resourceEndpoint := "/services/" + d.Get("parent.0").(string) + "/content-rules/" + d.Get("parent.1").(string) + "/content-rule-servers"
-	request := &APIRequest{
-		Method: "delete",
-		URL:    resourceEndpoint,
-	}
-
-	err := client.DeleteBarracudaWAFResource(name, request)
+	err := client.DeleteContentRuleServer(ctx, service, contentRule, name)
 
 	if err != nil {
-		return fmt.Errorf("Unable to delete the Barracuda WAF resource (%s) (%v)", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFContentRuleServers().Schema, "Unable to delete the Barracuda WAF resource (%s)", name)
 	}
 
 	return nil
 }
 
-func hydrateBarracudaWAFContentRuleServersResource(d *schema.ResourceData, method string, endpoint string) *APIRequest {
-
-	//resourcePayload : payload for the resource
-	resourcePayload := map[string]string{
-		"comments":        d.Get("comments").(string),
-		"name":            d.Get("name").(string),
-		"hostname":        d.Get("hostname").(string),
-		"identifier":      d.Get("identifier").(string),
-		"ip-address":      d.Get("ip_address").(string),
-		"address-version": d.Get("address_version").(string),
-		"port":            d.Get("port").(string),
-		"status":          d.Get("status").(string),
+func hydrateBarracudaWAFContentRuleServersResource(d *schema.ResourceData) *waf.ContentRuleServer {
+	return &waf.ContentRuleServer{
+		AddressVersion: d.Get("address_version").(string),
+		Comments:       d.Get("comments").(string),
+		Name:           d.Get("name").(string),
+		Hostname:       d.Get("hostname").(string),
+		Identifier:     d.Get("identifier").(string),
+		IPAddress:      d.Get("ip_address").(string),
+		Port:           d.Get("port").(string),
+		Status:         d.Get("status").(string),
 	}
+}
+
+func hydrateBarracudaWAFContentRuleServersSubResource(
+	ctx context.Context,
+	client *waf.Client,
+	d *schema.ResourceData,
+	service string,
+	contentRule string,
+	name string,
+) error {
+	log.Printf("[INFO] Updating Barracuda WAF sub resources (%s)", name)
 
-	// parameters not supported for updates
-	if method == "put" {
-		updatePayloadExceptions := [...]string{"address-version"}
-		for _, param := range updatePayloadExceptions {
-			delete(resourcePayload, param)
+	sslPolicy := &waf.SSLPolicy{}
+	if expandBarracudaWAFSubResource(d, "ssl_policy", sslPolicy) {
+		if err := client.UpdateContentRuleServerSSLPolicy(ctx, service, contentRule, name, sslPolicy); err != nil {
+			return &subResourceError{subResource: "ssl_policy", err: err}
 		}
 	}
 
-	// remove empty parameters from resource payload
-	for key, val := range resourcePayload {
-		if len(val) == 0 {
-			delete(resourcePayload, key)
+	connectionPooling := &waf.ConnectionPooling{}
+	if expandBarracudaWAFSubResource(d, "connection_pooling", connectionPooling) {
+		if err := client.UpdateContentRuleServerConnectionPooling(ctx, service, contentRule, name, connectionPooling); err != nil {
+			return &subResourceError{subResource: "connection_pooling", err: err}
 		}
 	}
 
-	return &APIRequest{
-		URL:  endpoint,
-		Body: resourcePayload,
-	}
+	return nil
 }
 
-func (b *BarracudaWAF) hydrateBarracudaWAFContentRuleServersSubResource(
+func readBarracudaWAFContentRuleServersSubResource(
+	ctx context.Context,
+	client *waf.Client,
 	d *schema.ResourceData,
+	resourceSchema map[string]*schema.Schema,
+	service string,
+	contentRule string,
 	name string,
-	endpoint string,
 ) error {
+	log.Printf("[INFO] Fetching Barracuda WAF sub resources (%s)", name)
 
-	for subResource, subResourceParams := range subResourceContentRuleServersParams {
-		subResourceParamsLength := d.Get(subResource + ".#").(int)
-
-		log.Printf("[INFO] Updating Barracuda WAF sub resource (%s) (%s)", name, subResource)
+	sslPolicy, err := client.GetContentRuleServerSSLPolicy(ctx, service, contentRule, name)
+	if err != nil {
+		return err
+	}
 
-		for i := 0; i < subResourceParamsLength; i++ {
-			subResourcePayload := map[string]string{}
-			suffix := fmt.Sprintf(".%d", i)
+	if err := setBarracudaWAFSubResourceData(d, resourceSchema, "ssl_policy", sslPolicy); err != nil {
+		return err
+	}
 
-			for _, param := range subResourceParams {
-				paramSuffix := fmt.Sprintf(".%s", param)
-				paramVaule := d.Get(subResource + suffix + paramSuffix).(string)
+	connectionPooling, err := client.GetContentRuleServerConnectionPooling(ctx, service, contentRule, name)
+	if err != nil {
+		return err
+	}
 
-				if len(paramVaule) > 0 {
-					param = strings.Replace(param, "_", "-", -1)
-					subResourcePayload[param] = paramVaule
-				}
-			}
+	return setBarracudaWAFSubResourceData(d, resourceSchema, "connection_pooling", connectionPooling)
+}
 
-			err := b.UpdateBarracudaWAFSubResource(name, endpoint, &APIRequest{
-				URL:  strings.Replace(subResource, "_", "-", -1),
-				Body: subResourcePayload,
-			})
+// checkBarracudaWAFContentRuleExists : reports a missing service or content rule before the
+// servers of the content rule are configured.
+func checkBarracudaWAFContentRuleExists(ctx context.Context, client *waf.Client, service string, contentRule string) error {
+	if _, err := client.GetService(ctx, service); err != nil {
+		if waf.IsNotFound(err) {
+			return &waf.NotFoundError{Kind: "service", Name: service}
+		}
+		return err
+	}
 
-			if err != nil {
-				return err
-			}
+	if _, err := client.GetContentRule(ctx, service, contentRule); err != nil {
+		if waf.IsNotFound(err) {
+			return &waf.NotFoundError{Kind: "content rule", Name: service + "/" + contentRule}
 		}
+		return err
 	}
 
 	return nil
@@ -1,10 +1,14 @@
 package barracudawaf
 
 import (
+	"context"
 	"fmt"
+	"reflect"
 	"testing"
 
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -43,7 +47,8 @@ resource "barracudawaf_content_rule_servers" "demo_rule_group_server_1" {
     name        = "DemoRuleGroupServer1"
     identifier  = "Hostname"
     hostname    = "barracuda.com"
-    parent      = [ barracudawaf_services.demo_app_1.name, barracudawaf_content_rules.demo_rule_group_1.name ]
+    service_name      = barracudawaf_services.demo_app_1.name
+    content_rule_name = barracudawaf_content_rules.demo_rule_group_1.name
 
     depends_on = [ barracudawaf_content_rules.demo_rule_group_1 ]
 }
@@ -63,40 +68,229 @@ func TestAccBarracudaWAFContentRulesServer_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("barracudawaf_content_rule_servers.demo_rule_group_server_1", "name", "DemoRuleGroupServer1"),
 				),
 			},
+			{
+				ResourceName:      "barracudawaf_content_rule_servers.demo_rule_group_server_1",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateId:     "DemoApp1/DemoRuleGroup1/DemoRuleGroupServer1",
+			},
 		},
 	})
 }
 
 func testCheckContentRulesServerExists(name string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*BarracudaWAF)
+		client := testAccProvider.Meta().(*waf.Client)
 
-		resourceEndpoint := "/services/DemoApp1/content-rules/DemoRuleGroup1/content-rule-servers/" + name
-		request := &APIRequest{
-			Method: "get",
-			URL:    resourceEndpoint,
+		if _, err := client.GetContentRuleServer(context.Background(), "DemoApp1", "DemoRuleGroup1", name); err != nil {
+			return fmt.Errorf("content rule server (%s) not found on the system (%v)", name, err)
 		}
 
-		contentRuleServers, err := client.GetBarracudaWAFResource(name, request)
-		if err != nil {
-			return err
+		return nil
+	}
+}
+
+func TestBarracudaWAFContentRulesServer_crud(t *testing.T) {
+	crud := newTestResourceCRUD(t, resourceCudaWAFContentRuleServers())
+	crud.server.SetObject("services/DemoApp1", nil)
+	crud.server.SetObject("services/DemoApp1/content-rules/DemoRule1", nil)
+
+	path := "services/DemoApp1/content-rules/DemoRule1/content-rule-servers/DemoRgServer1"
+	raw := map[string]interface{}{
+		"name":              "DemoRgServer1",
+		"ip_address":        "10.11.16.21",
+		"identifier":        "IP Address",
+		"address_version":   "IPv4",
+		"port":              "80",
+		"status":            "In Service",
+		"service_name":      "DemoApp1",
+		"content_rule_name": "DemoRule1",
+		"ssl_policy":        []interface{}{map[string]interface{}{"enable_https": "Yes"}},
+	}
+
+	d := crud.create(raw)
+
+	testCheckParams(t, crud.server.Object(path), map[string]interface{}{
+		"ip-address":      "10.11.16.21",
+		"address-version": "IPv4",
+	})
+	testCheckParams(t, crud.server.SubResource(path, "ssl-policy"), map[string]interface{}{"enable-https": "Yes"})
+	testCheckAttributes(t, d, map[string]string{
+		"port":                      "80",
+		"ssl_policy.0.enable_https": "Yes",
+		"content_rule_name":         "DemoRule1",
+	})
+
+	raw["port"] = "8080"
+	d = crud.update(d.Id(), raw)
+
+	testCheckParams(t, crud.server.LastRequest("PUT", "/"+path).Body, map[string]interface{}{
+		"port":            "8080",
+		"address-version": nil,
+	})
+	testCheckAttributes(t, d, map[string]string{"port": "8080"})
+
+	crud.delete(d.Id(), raw)
+	if crud.server.Object(path) != nil {
+		t.Fatalf("expected the content rule server to be deleted")
+	}
+
+	if d = crud.read(d.Id(), raw); d.Id() != "" {
+		t.Errorf("expected the deleted content rule server to be removed from the state")
+	}
+}
+
+func TestHydrateBarracudaWAFContentRuleServersResource(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCudaWAFContentRuleServers().Schema, map[string]interface{}{
+		"name":              "DemoRgServer1",
+		"address_version":   "IPv6",
+		"service_name":      "DemoApp1",
+		"content_rule_name": "DemoRule1",
+	})
+
+	testCheckPayload(t, hydrateBarracudaWAFContentRuleServersResource(d), map[string]interface{}{
+		"name":            "DemoRgServer1",
+		"address-version": "IPv6",
+		"port":            nil,
+	})
+}
+
+func TestBarracudaWAFContentRulesServer_missingContentRule(t *testing.T) {
+	crud := newTestResourceCRUD(t, resourceCudaWAFContentRuleServers())
+	crud.server.SetObject("services/DemoApp1", nil)
+
+	d := schema.TestResourceDataRaw(t, crud.resource.Schema, map[string]interface{}{
+		"name":              "DemoRgServer1",
+		"service_name":      "DemoApp1",
+		"content_rule_name": "DemoRule1",
+	})
+
+	diags := crud.resource.CreateContext(context.Background(), d, crud.client)
+
+	if len(diags) != 1 || diags[0].Detail != "content rule (DemoApp1/DemoRule1) not found on the Barracuda WAF" {
+		t.Fatalf("expected the missing content rule to be reported, got %#v", diags)
+	}
+
+	if len(crud.server.Requests("POST", "/services")) != 0 {
+		t.Errorf("expected no server to be created without its content rule")
+	}
+}
+
+func TestBarracudaWAFContentRulesServer_parentChange(t *testing.T) {
+	cases := []struct {
+		name        string
+		rename      bool // DemoRule1 is renamed to DemoRule2 in the same plan
+		service     string
+		contentRule string
+		requiresNew bool
+	}{
+		{name: "renamed", rename: true, service: "DemoApp1", contentRule: "DemoRule2", requiresNew: false},
+		{name: "created", service: "DemoApp1", contentRule: "DemoRule2", requiresNew: true},
+		{name: "service created", service: "DemoApp2", contentRule: "DemoRule1", requiresNew: true},
+	}
+
+	for _, c := range cases {
+		crud := newTestResourceCRUD(t, resourceCudaWAFContentRuleServers())
+		crud.server.SetObject("services/DemoApp1", nil)
+		crud.server.SetObject("services/DemoApp1/content-rules/DemoRule1", nil)
+
+		if c.rename {
+			ruleState := &terraform.InstanceState{
+				ID: "DemoRule1",
+				Attributes: map[string]string{
+					"id":           "DemoRule1",
+					"name":         "DemoRule1",
+					"host_match":   "www.example.com",
+					"url_match":    "/index.html",
+					"service_name": "DemoApp1",
+				},
+			}
+			ruleConfig := terraform.NewResourceConfigRaw(map[string]interface{}{
+				"name":         "DemoRule2",
+				"host_match":   "www.example.com",
+				"url_match":    "/index.html",
+				"service_name": "DemoApp1",
+			})
+
+			if _, err := resourceCudaWAFContentRules().Diff(context.Background(), ruleState, ruleConfig, crud.client); err != nil {
+				t.Fatalf("%s: unexpected error: %v", c.name, err)
+			}
 		}
 
-		if contentRuleServers == nil {
-			return fmt.Errorf("content rule server %s was not created.", name)
+		state := &terraform.InstanceState{
+			ID: "DemoRgServer1",
+			Attributes: map[string]string{
+				"id":                "DemoRgServer1",
+				"name":              "DemoRgServer1",
+				"address_version":   "IPv4",
+				"service_name":      "DemoApp1",
+				"content_rule_name": "DemoRule1",
+			},
 		}
 
-		var dataItems map[string]interface{}
-		for _, dataItems = range contentRuleServers.Data {
-			if dataItems["name"] == name {
-				break
-			}
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":              "DemoRgServer1",
+			"service_name":      c.service,
+			"content_rule_name": c.contentRule,
+		})
+
+		diff, err := crud.resource.Diff(context.Background(), state, config, crud.client)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.name, err)
 		}
 
-		if dataItems["name"] != name {
-			return fmt.Errorf("content rule server (%s) not found on the system", name)
+		if diff.RequiresNew() != c.requiresNew {
+			t.Errorf("%s: expected requires new to be %t", c.name, c.requiresNew)
 		}
+	}
+}
 
-		return nil
+func TestBarracudaWAFContentRulesServer_stateUpgradeV0(t *testing.T) {
+	rawState := map[string]interface{}{
+		"id":     "DemoRgServer1",
+		"name":   "DemoRgServer1",
+		"parent": []interface{}{"DemoApp1", "DemoRule1"},
+	}
+
+	upgrade := resourceCudaWAFContentRuleServers().StateUpgraders[0].Upgrade
+
+	upgraded, err := upgrade(context.Background(), rawState, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"id":                "DemoRgServer1",
+		"name":              "DemoRgServer1",
+		"service_name":      "DemoApp1",
+		"content_rule_name": "DemoRule1",
+	}
+
+	if !reflect.DeepEqual(upgraded, expected) {
+		t.Errorf("expected %v, got %v", expected, upgraded)
+	}
+
+	if _, err := upgrade(context.Background(), map[string]interface{}{}, nil); err == nil {
+		t.Errorf("expected a state without parent to be rejected")
+	}
+}
+
+func TestBarracudaWAFContentRulesServer_import(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCudaWAFContentRuleServers().Schema, map[string]interface{}{})
+	d.SetId("DemoApp1/DemoRule1/DemoRgServer1")
+
+	imported, err := resourceCudaWAFContentRuleServers().Importer.StateContext(context.Background(), d, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	testCheckAttributes(t, imported[0], map[string]string{
+		"service_name":      "DemoApp1",
+		"content_rule_name": "DemoRule1",
+		"name":              "DemoRgServer1",
+	})
+
+	if imported[0].Id() != "DemoRgServer1" {
+		t.Errorf("expected the ID to be the name of the server, got %s", imported[0].Id())
 	}
 }
@@ -1,10 +1,13 @@
 package barracudawaf
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -34,7 +37,7 @@ resource "barracudawaf_content_rules" "demo_rule_group_1" {
     host_match          = "www.example.com"
     web_firewall_policy = "DemoPolicy1"
     mode                = "Active"
-    parent              = [ barracudawaf_services.demo_app_1.name ]
+    service_name        = barracudawaf_services.demo_app_1.name
     
     depends_on          = [ barracudawaf_security_policies.demo_security_policy_1 ]
 }
@@ -56,40 +59,159 @@ func TestAccBarracudaWAFContentRules_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("barracudawaf_content_rules.demo_rule_group_1", "web_firewall_policy", "DemoPolicy1"),
 				),
 			},
+			{
+				ResourceName:      "barracudawaf_content_rules.demo_rule_group_1",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateId:     "DemoApp1/DemoRuleGroup1",
+			},
 		},
 	})
 }
 
 func testCheckContentRulesExists(name string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*BarracudaWAF)
+		client := testAccProvider.Meta().(*waf.Client)
 
-		resourceEndpoint := "/services/DemoApp1/content-rules/" + name
-		request := &APIRequest{
-			Method: "get",
-			URL:    resourceEndpoint,
+		if _, err := client.GetContentRule(context.Background(), "DemoApp1", name); err != nil {
+			return fmt.Errorf("content rule (%s) not found on the system (%v)", name, err)
 		}
 
-		contentRules, err := client.GetBarracudaWAFResource(name, request)
-		if err != nil {
-			return err
-		}
+		return nil
+	}
+}
 
-		if contentRules == nil {
-			return fmt.Errorf("content rule %s was not created.", name)
-		}
+func TestBarracudaWAFContentRules_crud(t *testing.T) {
+	crud := newTestResourceCRUD(t, resourceCudaWAFContentRules())
+	crud.server.SetObject("services/DemoApp1", nil)
 
-		var dataItems map[string]interface{}
-		for _, dataItems = range contentRules.Data {
-			if dataItems["name"] == name {
-				break
-			}
-		}
+	raw := map[string]interface{}{
+		"name":                "DemoRule1",
+		"url_match":           "/index.html",
+		"host_match":          "www.example.com",
+		"web_firewall_policy": "default",
+		"mode":                "Passive",
+		"service_name":        "DemoApp1",
+	}
 
-		if dataItems["name"] != name {
-			return fmt.Errorf("content rule (%s) not found on the system", name)
-		}
+	d := crud.create(raw)
 
-		return nil
+	testCheckParams(t, crud.server.Object("services/DemoApp1/content-rules/DemoRule1"), map[string]interface{}{
+		"url-match":  "/index.html",
+		"host-match": "www.example.com",
+		"comments":   "",
+	})
+	testCheckAttributes(t, d, map[string]string{"mode": "Passive", "service_name": "DemoApp1"})
+
+	raw["mode"] = "Active"
+	d = crud.update(d.Id(), raw)
+
+	testCheckParams(t, crud.server.LastRequest("PUT", "/services/DemoApp1/content-rules/DemoRule1").Body, map[string]interface{}{
+		"mode": "Active",
+	})
+	testCheckAttributes(t, d, map[string]string{"mode": "Active"})
+
+	crud.delete(d.Id(), raw)
+	if crud.server.Object("services/DemoApp1/content-rules/DemoRule1") != nil {
+		t.Fatalf("expected the content rule to be deleted")
+	}
+
+	if d = crud.read(d.Id(), raw); d.Id() != "" {
+		t.Errorf("expected the deleted content rule to be removed from the state")
+	}
+}
+
+func TestBarracudaWAFContentRules_rename(t *testing.T) {
+	crud := newTestResourceCRUD(t, resourceCudaWAFContentRules())
+	ruleServers := crud.with(resourceCudaWAFContentRuleServers())
+	crud.server.SetObject("services/DemoApp1", nil)
+
+	raw := map[string]interface{}{
+		"name":         "DemoRule1",
+		"url_match":    "/index.html",
+		"host_match":   "www.example.com",
+		"service_name": "DemoApp1",
+	}
+
+	d := crud.create(raw)
+
+	ruleServer := map[string]interface{}{
+		"name":              "DemoRgServer1",
+		"ip_address":        "10.11.16.21",
+		"port":              "80",
+		"service_name":      "DemoApp1",
+		"content_rule_name": "DemoRule1",
+	}
+	ruleServers.create(ruleServer)
+
+	raw["name"] = "DemoRule2"
+	d = crud.update(d.Id(), raw)
+
+	if d.Id() != "DemoRule2" {
+		t.Errorf("expected the ID to follow the new name, got %s", d.Id())
+	}
+
+	testCheckParams(t, crud.server.LastRequest("PUT", "/services/DemoApp1/content-rules/DemoRule1").Body, map[string]interface{}{
+		"name": "DemoRule2",
+	})
+
+	if crud.server.Object("services/DemoApp1/content-rules/DemoRule1") != nil {
+		t.Errorf("expected the old name to be released")
+	}
+
+	ruleServer["content_rule_name"] = "DemoRule2"
+	if rs := ruleServers.read("DemoRgServer1", ruleServer); rs.Id() != "DemoRgServer1" {
+		t.Fatalf("expected the content rule server to resolve under the renamed content rule")
+	}
+
+	if len(crud.server.Requests("DELETE", "/")) != 0 {
+		t.Errorf("expected the content rule to be renamed without being recreated")
+	}
+}
+
+func TestHydrateBarracudaWAFContentRulesResource(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCudaWAFContentRules().Schema, map[string]interface{}{
+		"name":         "DemoRule1",
+		"url_match":    "/index.html",
+		"host_match":   "www.example.com",
+		"service_name": "DemoApp1",
+	})
+
+	testCheckPayload(t, hydrateBarracudaWAFContentRulesResource(d), map[string]interface{}{
+		"name":         "DemoRule1",
+		"url-match":    "/index.html",
+		"host-match":   "www.example.com",
+		"mode":         nil,
+		"service_name": nil,
+	})
+}
+
+func TestBarracudaWAFContentRule_deprecatedParent(t *testing.T) {
+	crud := newTestResourceCRUD(t, resourceCudaWAFContentRules())
+	crud.server.SetObject("services/DemoApp1", nil)
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":       "DemoRule1",
+		"url_match":  "/index.html",
+		"host_match": "www.example.com",
+		"parent":     []interface{}{"DemoApp1"},
+	})
+
+	diff, err := crud.resource.Diff(context.Background(), nil, config, crud.client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	state, diags := crud.resource.Apply(context.Background(), nil, diff, crud.client)
+	if diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+
+	if crud.server.Object("services/DemoApp1/content-rules/DemoRule1") == nil {
+		t.Fatalf("expected the content rule to be created in the service named by parent")
+	}
+
+	if state.Attributes["service_name"] != "DemoApp1" || state.Attributes["parent.0"] != "DemoApp1" {
+		t.Errorf("expected service_name and parent to name the service, got %v", state.Attributes)
 	}
 }
@@ -1,40 +1,80 @@
 package barracudawaf
 
 import (
-	"fmt"
+	"context"
 	"log"
-	"strings"
 
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
-var (
-	subResourceContentRulesParams = map[string][]string{}
-)
-
 func resourceCudaWAFContentRules() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceCudaWAFContentRulesCreate,
-		Read:   resourceCudaWAFContentRulesRead,
-		Update: resourceCudaWAFContentRulesUpdate,
-		Delete: resourceCudaWAFContentRulesDelete,
+		CreateContext: resourceCudaWAFContentRulesCreate,
+		ReadContext:   resourceCudaWAFContentRulesRead,
+		UpdateContext: resourceCudaWAFContentRulesUpdate,
+		DeleteContext: resourceCudaWAFContentRulesDelete,
+		CustomizeDiff: resourceCudaWAFContentRulesCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: importBarracudaWAFNamedParentsResource("service_name"),
+		},
+
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceCudaWAFContentRulesV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: upgradeBarracudaWAFDeprecatedParentState("service_name"),
+			},
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Read:   schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
 
 		Schema: map[string]*schema.Schema{
-			"access_log":              {Type: schema.TypeString, Optional: true, Description: "Access Log"},
-			"app_id":                  {Type: schema.TypeString, Optional: true, Description: "Rule App Id"},
-			"comments":                {Type: schema.TypeString, Optional: true, Description: "Comments"},
-			"host_match":              {Type: schema.TypeString, Required: true, Description: "Host Match"},
-			"name":                    {Type: schema.TypeString, Required: true, Description: "Rule Group Name"},
-			"status":                  {Type: schema.TypeString, Optional: true, Description: "Status"},
-			"extended_match":          {Type: schema.TypeString, Optional: true, Description: "Extended Match"},
-			"extended_match_sequence": {Type: schema.TypeString, Optional: true, Description: "Extended Match Sequence"},
-			"mode":                    {Type: schema.TypeString, Optional: true, Description: "Mode"},
-			"url_match":               {Type: schema.TypeString, Required: true, Description: "URL Match"},
-			"web_firewall_policy":     {Type: schema.TypeString, Optional: true, Description: "Web Firewall Policy"},
+			"access_log": {Type: schema.TypeString, Optional: true, Computed: true, Description: "Access Log"},
+			"app_id":     {Type: schema.TypeString, Optional: true, Description: "Rule App Id"},
+			"comments":   {Type: schema.TypeString, Optional: true, Description: "Comments"},
+			"host_match": {Type: schema.TypeString, Required: true, Description: "Host Match"},
+			"name":       {Type: schema.TypeString, Required: true, Description: "Rule Group Name"},
+			"status": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Status",
+				ValidateFunc: validateBarracudaWAFOnOff,
+			},
+			"extended_match":          {Type: schema.TypeString, Optional: true, Computed: true, Description: "Extended Match"},
+			"extended_match_sequence": {Type: schema.TypeString, Optional: true, Computed: true, Description: "Extended Match Sequence"},
+			"mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Mode",
+				ValidateFunc: validateBarracudaWAFMode,
+			},
+			"url_match":           {Type: schema.TypeString, Required: true, Description: "URL Match"},
+			"web_firewall_policy": {Type: schema.TypeString, Optional: true, Computed: true, Description: "Web Firewall Policy"},
+			"service_name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"parent", "service_name"},
+				Description:  "Name of the service the content rule is configured in",
+			},
 			"parent": {
-				Type:     schema.TypeList,
-				Elem:     &schema.Schema{Type: schema.TypeString},
-				Required: true,
+				Type:         schema.TypeList,
+				Optional:     true,
+				Computed:     true,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				ExactlyOneOf: []string{"parent", "service_name"},
+				Deprecated:   "use service_name instead",
+				Description:  "Name of the service the content rule is configured in, as a single element list",
 			},
 		},
 
@@ -42,192 +82,166 @@ func resourceCudaWAFContentRules() *schema.Resource {
 	}
 }
 
-func resourceCudaWAFContentRulesCreate(d *schema.ResourceData, m interface{}) error {
-	client := m.(*BarracudaWAF)
+func resourceCudaWAFContentRulesCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
 
 	name := d.Get("name").(string)
+	service := d.Get("service_name").(string)
 
 	log.Println("[INFO] Creating Barracuda WAF resource " + name)
 
-	resourceEndpoint := "/services/" + d.Get("parent.0").(string) + "/content-rules"
-	err := client.CreateBarracudaWAFResource(name, hydrateBarracudaWAFContentRulesResource(d, "post", resourceEndpoint))
+	err := client.CreateContentRule(ctx, service, hydrateBarracudaWAFContentRulesResource(d))
 
 	if err != nil {
 		log.Printf("[ERROR] Unable to create Barracuda WAF resource (%s) (%v) ", name, err)
-		return err
-	}
-
-	err = client.hydrateBarracudaWAFContentRulesSubResource(d, name, resourceEndpoint)
-
-	if err != nil {
-		log.Printf("[ERROR] Unable to create Barracuda WAF sub resource (%s) (%v) ", name, err)
-		return err
+		return barracudaWAFDiagnostics(err, resourceCudaWAFContentRules().Schema, "Unable to create Barracuda WAF resource (%s)", name)
 	}
 
 	d.SetId(name)
-	return resourceCudaWAFContentRulesRead(d, m)
+	return resourceCudaWAFContentRulesRead(ctx, d, m)
 }
 
-func resourceCudaWAFContentRulesRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(*BarracudaWAF)
+func resourceCudaWAFContentRulesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
 
 	name := d.Id()
+	service := d.Get("service_name").(string)
 	log.Println("[INFO] Fetching Barracuda WAF resource " + name)
 
-	resourceEndpoint := "/services/" + d.Get("parent.0").(string) + "/content-rules"
-	request := &APIRequest{
-		Method: "get",
-		URL:    resourceEndpoint,
-	}
-
-	var dataItems map[string]interface{}
-	resources, err := client.GetBarracudaWAFResource(name, request)
-
-	if err != nil {
-		log.Printf("[ERROR] Unable to Retrieve Barracuda WAF resource (%s) (%v) ", name, err)
-		return err
-	}
+	contentRule, err := client.GetContentRule(ctx, service, name)
 
-	if resources.Data == nil {
+	if waf.IsNotFound(err) {
 		log.Printf("[WARN] Barracuda WAF resource (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
 	}
 
-	for _, dataItems = range resources.Data {
-		if dataItems["name"] == name {
-			break
-		}
+	if err != nil {
+		log.Printf("[ERROR] Unable to Retrieve Barracuda WAF resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFContentRules().Schema, "Unable to Retrieve Barracuda WAF resource (%s)", name)
 	}
 
-	if dataItems["name"] != name {
-		return fmt.Errorf("Barracuda WAF resource (%s) not found on the system", name)
+	err = setBarracudaWAFResourceData(d, resourceCudaWAFContentRules().Schema, contentRule, "service_name")
+
+	if err != nil {
+		log.Printf("[ERROR] Unable to set Barracuda WAF resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFContentRules().Schema, "Unable to set Barracuda WAF resource (%s)", name)
 	}
 
+	setBarracudaWAFDeprecatedParent(d, service)
 	d.Set("name", name)
 	return nil
 }
 
-func resourceCudaWAFContentRulesUpdate(d *schema.ResourceData, m interface{}) error {
-	client := m.(*BarracudaWAF)
+func resourceCudaWAFContentRulesUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
 
 	name := d.Id()
+	service := d.Get("service_name").(string)
 
 	log.Println("[INFO] Updating Barracuda WAF resource " + name)
 
-	resourceEndpoint := "/services/" + d.Get("parent.0").(string) + "/content-rules"
-	err := client.UpdateBarracudaWAFResource(name, hydrateBarracudaWAFContentRulesResource(d, "put", resourceEndpoint))
+	err := client.UpdateContentRule(ctx, service, name, hydrateBarracudaWAFContentRulesResource(d))
 
 	if err != nil {
-		log.Printf("[ERROR] Unable to update the Barracuda WAF resource (%s) (%v)", name, err)
+		log.Printf("[ERROR] Unable to update the Barracuda WAF resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFContentRules().Schema, "Unable to update the Barracuda WAF resource (%s)", name)
+	}
+
+	renameBarracudaWAFResource(d)
+
+	return resourceCudaWAFContentRulesRead(ctx, d, m)
+}
+
+func resourceCudaWAFContentRulesCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if err := setBarracudaWAFServiceNameFromParent(d); err != nil {
 		return err
 	}
 
-	err = client.hydrateBarracudaWAFContentRulesSubResource(d, name, resourceEndpoint)
+	oldService, newService := d.GetChange("service_name")
+	oldName, newName := d.GetChange("name")
 
-	if err != nil {
-		log.Printf("[ERROR] Unable to update the Barracuda WAF sub resource (%s) (%v)", name, err)
+	replaced, err := forceNewIfBarracudaWAFParentChanges(
+		ctx,
+		d,
+		m,
+		[]string{"service_name"},
+		waf.ServicePath(oldService.(string)),
+		waf.ServicePath(newService.(string)),
+		func(ctx context.Context) error {
+			_, err := m.(*waf.Client).GetService(ctx, oldService.(string))
+			return err
+		},
+	)
+
+	if err != nil || replaced {
 		return err
 	}
 
-	return resourceCudaWAFContentRulesRead(d, m)
+	expectBarracudaWAFRename(
+		d,
+		m,
+		waf.ContentRulePath(oldService.(string), oldName.(string)),
+		waf.ContentRulePath(newService.(string), newName.(string)),
+	)
+
+	return nil
 }
 
-func resourceCudaWAFContentRulesDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(*BarracudaWAF)
+func resourceCudaWAFContentRulesDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
 
 	name := d.Id()
+	service := d.Get("service_name").(string)
 
 	log.Println("[INFO] Deleting Barracuda WAF resource " + name)
 
-	resourceEndpoint := "/services/" + d.Get("parent.0").(string) + "/content-rules"
-	request := &APIRequest{
-		Method: "delete",
-		URL:    resourceEndpoint,
-	}
-
-	err := client.DeleteBarracudaWAFResource(name, request)
+	err := client.DeleteContentRule(ctx, service, name)
 
 	if err != nil {
-		return fmt.Errorf("Unable to delete the Barracuda WAF resource (%s) (%v)", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFContentRules().Schema, "Unable to delete the Barracuda WAF resource (%s)", name)
 	}
 
 	return nil
 }
 
-func hydrateBarracudaWAFContentRulesResource(d *schema.ResourceData, method string, endpoint string) *APIRequest {
-
-	//resourcePayload : payload for the resource
-	resourcePayload := map[string]string{
-		"access-log":              d.Get("access_log").(string),
-		"app-id":                  d.Get("app_id").(string),
-		"comments":                d.Get("comments").(string),
-		"host-match":              d.Get("host_match").(string),
-		"name":                    d.Get("name").(string),
-		"status":                  d.Get("status").(string),
-		"extended-match":          d.Get("extended_match").(string),
-		"extended-match-sequence": d.Get("extended_match_sequence").(string),
-		"mode":                    d.Get("mode").(string),
-		"url-match":               d.Get("url_match").(string),
-		"web-firewall-policy":     d.Get("web_firewall_policy").(string),
-	}
-
-	// parameters not supported for updates
-	if method == "put" {
-		updatePayloadExceptions := [...]string{}
-		for _, param := range updatePayloadExceptions {
-			delete(resourcePayload, param)
-		}
-	}
-
-	// remove empty parameters from resource payload
-	for key, val := range resourcePayload {
-		if len(val) == 0 {
-			delete(resourcePayload, key)
-		}
-	}
-
-	return &APIRequest{
-		URL:  endpoint,
-		Body: resourcePayload,
+func hydrateBarracudaWAFContentRulesResource(d *schema.ResourceData) *waf.ContentRule {
+	return &waf.ContentRule{
+		AccessLog:             d.Get("access_log").(string),
+		AppID:                 d.Get("app_id").(string),
+		Comments:              d.Get("comments").(string),
+		HostMatch:             d.Get("host_match").(string),
+		Name:                  d.Get("name").(string),
+		Status:                d.Get("status").(string),
+		ExtendedMatch:         d.Get("extended_match").(string),
+		ExtendedMatchSequence: d.Get("extended_match_sequence").(string),
+		Mode:                  d.Get("mode").(string),
+		URLMatch:              d.Get("url_match").(string),
+		WebFirewallPolicy:     d.Get("web_firewall_policy").(string),
 	}
 }
 
-func (b *BarracudaWAF) hydrateBarracudaWAFContentRulesSubResource(
-	d *schema.ResourceData,
-	name string,
-	endpoint string,
-) error {
-
-	for subResource, subResourceParams := range subResourceContentRulesParams {
-		subResourceParamsLength := d.Get(subResource + ".#").(int)
-
-		log.Printf("[INFO] Updating Barracuda WAF sub resource (%s) (%s)", name, subResource)
-
-		for i := 0; i < subResourceParamsLength; i++ {
-			subResourcePayload := map[string]string{}
-			suffix := fmt.Sprintf(".%d", i)
-
-			for _, param := range subResourceParams {
-				paramSuffix := fmt.Sprintf(".%s", param)
-				paramVaule := d.Get(subResource + suffix + paramSuffix).(string)
-
-				if len(paramVaule) > 0 {
-					param = strings.Replace(param, "_", "-", -1)
-					subResourcePayload[param] = paramVaule
-				}
-			}
-
-			err := b.UpdateBarracudaWAFSubResource(name, endpoint, &APIRequest{
-				URL:  strings.Replace(subResource, "_", "-", -1),
-				Body: subResourcePayload,
-			})
-
-			if err != nil {
-				return err
-			}
-		}
+// resourceCudaWAFContentRulesV0 : schema of the version 0 state, the service was held by the
+// "parent" list.
+func resourceCudaWAFContentRulesV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"access_log":              {Type: schema.TypeString, Optional: true, Computed: true},
+			"app_id":                  {Type: schema.TypeString, Optional: true, Computed: true},
+			"comments":                {Type: schema.TypeString, Optional: true, Computed: true},
+			"host_match":              {Type: schema.TypeString, Required: true},
+			"name":                    {Type: schema.TypeString, Required: true},
+			"status":                  {Type: schema.TypeString, Optional: true, Computed: true},
+			"extended_match":          {Type: schema.TypeString, Optional: true, Computed: true},
+			"extended_match_sequence": {Type: schema.TypeString, Optional: true, Computed: true},
+			"mode":                    {Type: schema.TypeString, Optional: true, Computed: true},
+			"url_match":               {Type: schema.TypeString, Required: true},
+			"web_firewall_policy":     {Type: schema.TypeString, Optional: true, Computed: true},
+			"parent": {
+				Type:     schema.TypeList,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Required: true,
+			},
+		},
 	}
-
-	return nil
 }
@@ -1,207 +1,237 @@
 package barracudawaf
 
 import (
-	"fmt"
+	"context"
 	"log"
-	"reflect"
-	"strings"
 
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-)
-
-var (
-	subResourceLetsEncryptCertificateParams = map[string][]string{}
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceCudaWAFLetsEncryptCertificate() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceCudaWAFLetsEncryptCertificateCreate,
-		Read:   resourceCudaWAFLetsEncryptCertificateRead,
-		Update: resourceCudaWAFLetsEncryptCertificateUpdate,
-		Delete: resourceCudaWAFLetsEncryptCertificateDelete,
+		CreateContext: resourceCudaWAFLetsEncryptCertificateCreate,
+		ReadContext:   resourceCudaWAFLetsEncryptCertificateRead,
+		UpdateContext: resourceCudaWAFLetsEncryptCertificateUpdate,
+		DeleteContext: resourceCudaWAFLetsEncryptCertificateDelete,
+		CustomizeDiff: resourceCudaWAFLetsEncryptCertificateCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceCudaWAFLetsEncryptCertificateV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: upgradeBarracudaWAFIdentityState,
+			},
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Read:   schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
 
 		Schema: map[string]*schema.Schema{
-			"allow_private_key_export":   {Type: schema.TypeString, Optional: true, Description: "If set Yes, Private Key gets downloaded along with the certificate"},
-			"auto_renew_cert":            {Type: schema.TypeString, Optional: true, Description: "Auto Renew Certificate"},
-			"common_name":                {Type: schema.TypeString, Required: true, Description: "Common Name"},
-			"multi_cert_trusted_service": {Type: schema.TypeString, Required: true, Description: "Service Name for LetsEncrypt certificate"},
-			"schedule_renewal_day":       {Type: schema.TypeString, Optional: true, Description: "Renew Certificate days"},
+			"allow_private_key_export": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "If set Yes, Private Key gets downloaded along with the certificate",
+				ValidateFunc: validateBarracudaWAFYesNo,
+				ForceNew:     true,
+			},
+			"auto_renew_cert": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Auto Renew Certificate",
+				ValidateFunc: validateBarracudaWAFYesNo,
+			},
+			"common_name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Common Name",
+				ForceNew:    true,
+			},
+			"multi_cert_trusted_service": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Service Name for LetsEncrypt certificate",
+				ForceNew:    true,
+			},
+			"schedule_renewal_day": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Renew Certificate days",
+				ValidateFunc: validateBarracudaWAFIntString(1, 90),
+			},
 			"san_cert": {
 				Type:     schema.TypeList,
 				Optional: true,
+				Computed: true,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
 				Description: "Subject Alternative Names",
+				ForceNew:    true,
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Policy Name",
+				ForceNew:    true,
+			},
+			"early_renewal_hours": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Hours before the expiry of the certificate from which it is replaced",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"issuer": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Issuer of the issued certificate",
+			},
+			"expiry": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Expiry of the issued certificate",
+			},
+			"serial": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Serial Number of the issued certificate",
+			},
+			"san_certificate": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Subject Alternative Names of the issued certificate",
 			},
-			"name": {Type: schema.TypeString, Required: true, Description: "Policy Name"},
 		},
 
 		Description: "`barracudawaf_letsencrypt_certificate` manages `Letsencrypt Certificate` on the Barracuda Web Application Firewall.",
 	}
 }
 
-func resourceCudaWAFLetsEncryptCertificateCreate(d *schema.ResourceData, m interface{}) error {
-	client := m.(*BarracudaWAF)
+func resourceCudaWAFLetsEncryptCertificateCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
 
 	name := d.Get("name").(string)
 
 	log.Println("[INFO] Creating Barracuda WAF resource " + name)
 
-	resourceEndpoint := "/certificates/letsencrypt"
-	err := client.CreateBarracudaWAFResource(name, hydrateBarracudaWAFLetsEncryptCertificateResource(d, "post", resourceEndpoint))
+	err := client.CreateLetsEncryptCertificate(ctx, hydrateBarracudaWAFLetsEncryptCertificateResource(d))
 
 	if err != nil {
 		log.Printf("[ERROR] Unable to create Barracuda WAF resource (%s) (%v) ", name, err)
-		return err
-	}
-
-	err = client.hydrateBarracudaWAFLetsEncryptCertificateSubResource(d, name, resourceEndpoint)
-
-	if err != nil {
-		log.Printf("[ERROR] Unable to create Barracuda WAF sub resource (%s) (%v) ", name, err)
-		return err
+		return barracudaWAFDiagnostics(err, resourceCudaWAFLetsEncryptCertificate().Schema, "Unable to create Barracuda WAF resource (%s)", name)
 	}
 
 	d.SetId(name)
-	return resourceCudaWAFLetsEncryptCertificateRead(d, m)
+	return resourceCudaWAFLetsEncryptCertificateRead(ctx, d, m)
 }
 
-func resourceCudaWAFLetsEncryptCertificateRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(*BarracudaWAF)
+func resourceCudaWAFLetsEncryptCertificateRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
 
 	name := d.Id()
 	log.Println("[INFO] Fetching Barracuda WAF resource " + name)
 
-	resourceEndpoint := "/signed-certificate"
-	request := &APIRequest{
-		Method: "get",
-		URL:    resourceEndpoint,
-	}
-
-	var dataItems map[string]interface{}
-	resources, err := client.GetBarracudaWAFResource(name, request)
+	certificate, err := client.GetLetsEncryptCertificate(ctx, name)
 
-	if err != nil {
-		log.Printf("[ERROR] Unable to Retrieve Barracuda WAF resource (%s) (%v) ", name, err)
-		return err
-	}
-
-	if resources.Data == nil {
+	if waf.IsNotFound(err) {
 		log.Printf("[WARN] Barracuda WAF resource (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
 	}
 
-	for _, dataItems = range resources.Data {
-		if dataItems["name"] == name {
-			break
-		}
+	if err != nil {
+		log.Printf("[ERROR] Unable to Retrieve Barracuda WAF resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFLetsEncryptCertificate().Schema, "Unable to Retrieve Barracuda WAF resource (%s)", name)
 	}
 
-	if dataItems["name"] != name {
-		return fmt.Errorf("Barracuda WAF resource (%s) not found on the system", name)
+	err = setBarracudaWAFResourceData(d, resourceCudaWAFLetsEncryptCertificate().Schema, certificate)
+
+	if err != nil {
+		log.Printf("[ERROR] Unable to set Barracuda WAF resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFLetsEncryptCertificate().Schema, "Unable to set Barracuda WAF resource (%s)", name)
 	}
 
 	d.Set("name", name)
 	return nil
 }
 
-func resourceCudaWAFLetsEncryptCertificateUpdate(d *schema.ResourceData, m interface{}) error {
-	return resourceCudaWAFLetsEncryptCertificateRead(d, m)
-}
-
-func resourceCudaWAFLetsEncryptCertificateDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(*BarracudaWAF)
+func resourceCudaWAFLetsEncryptCertificateUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
 
 	name := d.Id()
 
-	log.Println("[INFO] Deleting Barracuda WAF resource " + name)
-
-	resourceEndpoint := "/signed-certificate"
-	request := &APIRequest{
-		Method: "delete",
-		URL:    resourceEndpoint,
-	}
-
-	err := client.DeleteBarracudaWAFResource(name, request)
-
-	if err != nil {
-		return fmt.Errorf("Unable to delete the Barracuda WAF resource (%s) (%v)", name, err)
-	}
-
-	return nil
-}
-
-func hydrateBarracudaWAFLetsEncryptCertificateResource(d *schema.ResourceData, method string, endpoint string) *APIRequest {
-
-	//resourcePayload : payload for the resource
-	resourcePayload := map[string]interface{}{
-		"san-cert":                   d.Get("san_cert"),
-		"name":                       d.Get("name").(string),
-		"common-name":                d.Get("common_name").(string),
-		"auto-renew-cert":            d.Get("auto_renew_cert").(string),
-		"schedule-renewal-day":       d.Get("schedule_renewal_day").(string),
-		"allow-private-key-export":   d.Get("allow_private_key_export").(string),
-		"multi-cert-trusted-service": d.Get("multi_cert_trusted_service").(string),
-	}
+	if d.HasChanges("auto_renew_cert", "schedule_renewal_day") {
+		log.Println("[INFO] Updating Barracuda WAF resource " + name)
 
-	// parameters not supported for updates
-	if method == "put" {
-		updatePayloadExceptions := [...]string{""}
-		for _, param := range updatePayloadExceptions {
-			delete(resourcePayload, param)
-		}
-	}
+		err := client.UpdateLetsEncryptCertificate(ctx, name, hydrateBarracudaWAFLetsEncryptCertificateResource(d))
 
-	// remove empty parameters from resource payload
-	for key, val := range resourcePayload {
-		if reflect.ValueOf(val).Len() == 0 {
-			delete(resourcePayload, key)
+		if err != nil {
+			log.Printf("[ERROR] Unable to update the Barracuda WAF resource (%s) (%v) ", name, err)
+			return barracudaWAFDiagnostics(err, resourceCudaWAFLetsEncryptCertificate().Schema, "Unable to update the Barracuda WAF resource (%s)", name)
 		}
 	}
 
-	return &APIRequest{
-		URL:  endpoint,
-		Body: resourcePayload,
-	}
+	return resourceCudaWAFLetsEncryptCertificateRead(ctx, d, m)
 }
 
-func (b *BarracudaWAF) hydrateBarracudaWAFLetsEncryptCertificateSubResource(
-	d *schema.ResourceData,
-	name string,
-	endpoint string,
-) error {
+func resourceCudaWAFLetsEncryptCertificateCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	return forceNewIfBarracudaWAFCertificateExpires(d)
+}
 
-	for subResource, subResourceParams := range subResourceLetsEncryptCertificateParams {
-		subResourceParamsLength := d.Get(subResource + ".#").(int)
+func resourceCudaWAFLetsEncryptCertificateDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
 
-		log.Printf("[INFO] Updating Barracuda WAF sub resource (%s) (%s)", name, subResource)
+	name := d.Id()
 
-		for i := 0; i < subResourceParamsLength; i++ {
-			subResourcePayload := map[string]string{}
-			suffix := fmt.Sprintf(".%d", i)
+	log.Println("[INFO] Deleting Barracuda WAF resource " + name)
 
-			for _, param := range subResourceParams {
-				paramSuffix := fmt.Sprintf(".%s", param)
-				paramVaule := d.Get(subResource + suffix + paramSuffix).(string)
+	err := client.DeleteLetsEncryptCertificate(ctx, name)
 
-				if len(paramVaule) > 0 {
-					param = strings.Replace(param, "_", "-", -1)
-					subResourcePayload[param] = paramVaule
-				}
-			}
+	if err != nil {
+		return barracudaWAFDiagnostics(err, resourceCudaWAFLetsEncryptCertificate().Schema, "Unable to delete the Barracuda WAF resource (%s)", name)
+	}
 
-			err := b.UpdateBarracudaWAFSubResource(name, endpoint, &APIRequest{
-				URL:  strings.Replace(subResource, "_", "-", -1),
-				Body: subResourcePayload,
-			})
+	return nil
+}
 
-			if err != nil {
-				return err
-			}
-		}
+func hydrateBarracudaWAFLetsEncryptCertificateResource(d *schema.ResourceData) *waf.Certificate {
+	return &waf.Certificate{
+		SANCert:                 expandStringList(d.Get("san_cert").([]interface{})),
+		Name:                    d.Get("name").(string),
+		CommonName:              d.Get("common_name").(string),
+		AutoRenewCert:           d.Get("auto_renew_cert").(string),
+		ScheduleRenewalDay:      d.Get("schedule_renewal_day").(string),
+		AllowPrivateKeyExport:   d.Get("allow_private_key_export").(string),
+		MultiCertTrustedService: d.Get("multi_cert_trusted_service").(string),
 	}
+}
 
-	return nil
+// resourceCudaWAFLetsEncryptCertificateV0 : schema of the version 0 state of Let's Encrypt certificates, which is
+// a valid version 1 state.
+func resourceCudaWAFLetsEncryptCertificateV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"allow_private_key_export":   {Type: schema.TypeString, Optional: true},
+			"auto_renew_cert":            {Type: schema.TypeString, Optional: true},
+			"common_name":                {Type: schema.TypeString, Required: true},
+			"multi_cert_trusted_service": {Type: schema.TypeString, Required: true},
+			"schedule_renewal_day":       {Type: schema.TypeString, Optional: true},
+			"san_cert":                   {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"name":                       {Type: schema.TypeString, Required: true},
+		},
+	}
 }
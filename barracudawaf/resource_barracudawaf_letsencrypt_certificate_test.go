@@ -1,10 +1,14 @@
 package barracudawaf
 
 import (
+	"context"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -58,34 +62,132 @@ func TestAccBarracudaWAFLetsEncryptCertificate_basic(t *testing.T) {
 
 func testCheckLetsEncryptCertificateExists(name string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*BarracudaWAF)
+		client := testAccProvider.Meta().(*waf.Client)
 
-		resourceEndpoint := "/signed-certificate/" + name
-		request := &APIRequest{
-			Method: "get",
-			URL:    resourceEndpoint,
+		if _, err := client.GetLetsEncryptCertificate(context.Background(), name); err != nil {
+			return fmt.Errorf("letsencrypt certificate (%s) not found on the system (%v)", name, err)
 		}
 
-		letsEncryptCerts, err := client.GetBarracudaWAFResource(name, request)
-		if err != nil {
-			return err
-		}
+		return nil
+	}
+}
 
-		if letsEncryptCerts == nil {
-			return fmt.Errorf("letsencrypt certificate %s was not created.", name)
-		}
+func TestBarracudaWAFLetsEncryptCertificate_crud(t *testing.T) {
+	crud := newTestResourceCRUD(t, resourceCudaWAFLetsEncryptCertificate())
+	crud.server.SetObject("services/DemoApp1", nil)
 
-		var dataItems map[string]interface{}
-		for _, dataItems = range letsEncryptCerts.Data {
-			if dataItems["name"] == name {
-				break
-			}
-		}
+	raw := map[string]interface{}{
+		"name":                       "DemoLetsEncryptCert",
+		"common_name":                "www.example.com",
+		"multi_cert_trusted_service": "DemoApp1",
+		"auto_renew_cert":            "Yes",
+	}
+
+	d := crud.create(raw)
+
+	testCheckParams(t, crud.server.LastRequest("POST", "/certificates/letsencrypt").Body, map[string]interface{}{
+		"common-name":                "www.example.com",
+		"multi-cert-trusted-service": "DemoApp1",
+	})
+	testCheckAttributes(t, d, map[string]string{"common_name": "www.example.com", "auto_renew_cert": "Yes"})
+
+	crud.server.SetObject("signed-certificate/DemoLetsEncryptCert", map[string]interface{}{
+		"common-name":     "www.example.com",
+		"auto-renew-cert": "Yes",
+		"issuer":          "R3",
+		"expiry":          "2027-01-17 12:30:00",
+		"serial":          "04A1B2C3",
+		"san-certificate": []interface{}{"www.example.com", "example.com"},
+	})
+
+	raw["schedule_renewal_day"] = "30"
+	d = crud.update(d.Id(), raw)
+
+	testCheckParams(t, crud.server.LastRequest("PUT", "/certificates/letsencrypt/DemoLetsEncryptCert").Body, map[string]interface{}{
+		"schedule-renewal-day": "30",
+		"common-name":          nil,
+	})
+	testCheckAttributes(t, d, map[string]string{
+		"common_name":          "www.example.com",
+		"schedule_renewal_day": "30",
+		"issuer":               "R3",
+		"expiry":               "2027-01-17 12:30:00",
+		"serial":               "04A1B2C3",
+		"san_certificate.#":    "2",
+	})
+
+	crud.delete(d.Id(), raw)
+	if crud.server.Object("signed-certificate/DemoLetsEncryptCert") != nil {
+		t.Fatalf("expected the certificate to be deleted")
+	}
 
-		if dataItems["name"] != name {
-			return fmt.Errorf("letsencrypt certificate (%s) not found on the system", name)
+	if d = crud.read(d.Id(), raw); d.Id() != "" {
+		t.Errorf("expected the deleted certificate to be removed from the state")
+	}
+}
+
+func TestBarracudaWAFLetsEncryptCertificate_renewal(t *testing.T) {
+	resource := resourceCudaWAFLetsEncryptCertificate()
+	expiry := time.Now().Add(48 * time.Hour).UTC().Format(time.RFC3339)
+
+	state := &terraform.InstanceState{
+		ID: "DemoLetsEncryptCert",
+		Attributes: map[string]string{
+			"id":                         "DemoLetsEncryptCert",
+			"name":                       "DemoLetsEncryptCert",
+			"common_name":                "www.example.com",
+			"multi_cert_trusted_service": "DemoApp1",
+			"allow_private_key_export":   "No",
+			"auto_renew_cert":            "Yes",
+			"schedule_renewal_day":       "30",
+			"san_cert.#":                 "0",
+			"issuer":                     "R3",
+			"expiry":                     expiry,
+			"serial":                     "04A1B2C3",
+			"san_certificate.#":          "1",
+			"san_certificate.0":          "www.example.com",
+		},
+	}
+
+	cases := []struct {
+		earlyRenewalHours int
+		requiresNew       bool
+	}{
+		{earlyRenewalHours: 0, requiresNew: false},
+		{earlyRenewalHours: 24, requiresNew: false},
+		{earlyRenewalHours: 72, requiresNew: true},
+	}
+
+	for _, c := range cases {
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":                       "DemoLetsEncryptCert",
+			"common_name":                "www.example.com",
+			"multi_cert_trusted_service": "DemoApp1",
+			"early_renewal_hours":        c.earlyRenewalHours,
+		})
+
+		diff, err := resource.Diff(context.Background(), state, config, nil)
+		if err != nil {
+			t.Fatalf("%d: unexpected error: %v", c.earlyRenewalHours, err)
 		}
 
-		return nil
+		if requiresNew := diff != nil && diff.RequiresNew(); requiresNew != c.requiresNew {
+			t.Errorf("%d hours: expected requires new to be %t", c.earlyRenewalHours, c.requiresNew)
+		}
 	}
 }
+
+func TestHydrateBarracudaWAFLetsEncryptCertificateResource(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCudaWAFLetsEncryptCertificate().Schema, map[string]interface{}{
+		"name":        "DemoLetsEncryptCert",
+		"common_name": "www.example.com",
+		"san_cert":    []interface{}{"example.com"},
+	})
+
+	testCheckPayload(t, hydrateBarracudaWAFLetsEncryptCertificateResource(d), map[string]interface{}{
+		"name":                 "DemoLetsEncryptCert",
+		"common-name":          "www.example.com",
+		"san-cert":             []interface{}{"example.com"},
+		"schedule-renewal-day": nil,
+	})
+}
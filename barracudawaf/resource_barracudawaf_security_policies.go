@@ -1,207 +1,151 @@
 package barracudawaf
 
 import (
-	"fmt"
+	"context"
 	"log"
-	"strings"
 
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
-var (
-	subResourceSecurityPoliciesParams = map[string][]string{}
-)
-
 func resourceCudaWAFSecurityPolicies() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceCudaWAFSecurityPoliciesCreate,
-		Read:   resourceCudaWAFSecurityPoliciesRead,
-		Update: resourceCudaWAFSecurityPoliciesUpdate,
-		Delete: resourceCudaWAFSecurityPoliciesDelete,
+		CreateContext: resourceCudaWAFSecurityPoliciesCreate,
+		ReadContext:   resourceCudaWAFSecurityPoliciesRead,
+		UpdateContext: resourceCudaWAFSecurityPoliciesUpdate,
+		DeleteContext: resourceCudaWAFSecurityPoliciesDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceCudaWAFSecurityPoliciesV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: upgradeBarracudaWAFIdentityState,
+			},
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Read:   schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
 
 		Schema: map[string]*schema.Schema{
-			"based_on": {Type: schema.TypeString, Optional: true},
-			"name":     {Type: schema.TypeString, Required: true, Description: "Policy Name"},
+			"based_on": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"name": {Type: schema.TypeString, Required: true, Description: "Policy Name"},
 		},
 
 		Description: "`barracudawaf_security_policies` manages `Security Policies` on the Barracuda Web Application Firewall.",
 	}
 }
 
-func resourceCudaWAFSecurityPoliciesCreate(d *schema.ResourceData, m interface{}) error {
-	client := m.(*BarracudaWAF)
+func resourceCudaWAFSecurityPoliciesCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
 
 	name := d.Get("name").(string)
 
 	log.Println("[INFO] Creating Barracuda WAF resource " + name)
 
-	resourceEndpoint := "/security-policies"
-	err := client.CreateBarracudaWAFResource(name, hydrateBarracudaWAFSecurityPoliciesResource(d, "post", resourceEndpoint))
+	err := client.CreateSecurityPolicy(ctx, hydrateBarracudaWAFSecurityPoliciesResource(d))
 
 	if err != nil {
 		log.Printf("[ERROR] Unable to create Barracuda WAF resource (%s) (%v) ", name, err)
-		return err
-	}
-
-	err = client.hydrateBarracudaWAFSecurityPoliciesSubResource(d, name, resourceEndpoint)
-
-	if err != nil {
-		log.Printf("[ERROR] Unable to create Barracuda WAF sub resource (%s) (%v) ", name, err)
-		return err
+		return barracudaWAFDiagnostics(err, resourceCudaWAFSecurityPolicies().Schema, "Unable to create Barracuda WAF resource (%s)", name)
 	}
 
 	d.SetId(name)
-	return resourceCudaWAFSecurityPoliciesRead(d, m)
+	return resourceCudaWAFSecurityPoliciesRead(ctx, d, m)
 }
 
-func resourceCudaWAFSecurityPoliciesRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(*BarracudaWAF)
+func resourceCudaWAFSecurityPoliciesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
 
 	name := d.Id()
 	log.Println("[INFO] Fetching Barracuda WAF resource " + name)
 
-	resourceEndpoint := "/security-policies"
-	request := &APIRequest{
-		Method: "get",
-		URL:    resourceEndpoint,
-	}
-
-	var dataItems map[string]interface{}
-	resources, err := client.GetBarracudaWAFResource(name, request)
-
-	if err != nil {
-		log.Printf("[ERROR] Unable to Retrieve Barracuda WAF resource (%s) (%v) ", name, err)
-		return err
-	}
+	securityPolicy, err := client.GetSecurityPolicy(ctx, name)
 
-	if resources.Data == nil {
+	if waf.IsNotFound(err) {
 		log.Printf("[WARN] Barracuda WAF resource (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
 	}
 
-	for _, dataItems = range resources.Data {
-		if dataItems["name"] == name {
-			break
-		}
+	if err != nil {
+		log.Printf("[ERROR] Unable to Retrieve Barracuda WAF resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFSecurityPolicies().Schema, "Unable to Retrieve Barracuda WAF resource (%s)", name)
 	}
 
-	if dataItems["name"] != name {
-		return fmt.Errorf("Barracuda WAF resource (%s) not found on the system", name)
+	err = setBarracudaWAFResourceData(d, resourceCudaWAFSecurityPolicies().Schema, securityPolicy)
+
+	if err != nil {
+		log.Printf("[ERROR] Unable to set Barracuda WAF resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFSecurityPolicies().Schema, "Unable to set Barracuda WAF resource (%s)", name)
 	}
 
 	d.Set("name", name)
 	return nil
 }
 
-func resourceCudaWAFSecurityPoliciesUpdate(d *schema.ResourceData, m interface{}) error {
-	client := m.(*BarracudaWAF)
+func resourceCudaWAFSecurityPoliciesUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
 
 	name := d.Id()
 
 	log.Println("[INFO] Updating Barracuda WAF resource " + name)
 
-	resourceEndpoint := "/security-policies"
-	err := client.UpdateBarracudaWAFResource(name, hydrateBarracudaWAFSecurityPoliciesResource(d, "put", resourceEndpoint))
+	err := client.UpdateSecurityPolicy(ctx, name, hydrateBarracudaWAFSecurityPoliciesResource(d))
 
 	if err != nil {
-		log.Printf("[ERROR] Unable to update the Barracuda WAF resource (%s) (%v)", name, err)
-		return err
+		log.Printf("[ERROR] Unable to update the Barracuda WAF resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFSecurityPolicies().Schema, "Unable to update the Barracuda WAF resource (%s)", name)
 	}
 
-	err = client.hydrateBarracudaWAFSecurityPoliciesSubResource(d, name, resourceEndpoint)
-
-	if err != nil {
-		log.Printf("[ERROR] Unable to update the Barracuda WAF sub resource (%s) (%v)", name, err)
-		return err
-	}
+	renameBarracudaWAFResource(d)
 
-	return resourceCudaWAFSecurityPoliciesRead(d, m)
+	return resourceCudaWAFSecurityPoliciesRead(ctx, d, m)
 }
 
-func resourceCudaWAFSecurityPoliciesDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(*BarracudaWAF)
+func resourceCudaWAFSecurityPoliciesDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
 
 	name := d.Id()
 
 	log.Println("[INFO] Deleting Barracuda WAF resource " + name)
 
-	resourceEndpoint := "/security-policies"
-	request := &APIRequest{
-		Method: "delete",
-		URL:    resourceEndpoint,
-	}
-
-	err := client.DeleteBarracudaWAFResource(name, request)
+	err := client.DeleteSecurityPolicy(ctx, name)
 
 	if err != nil {
-		return fmt.Errorf("Unable to delete the Barracuda WAF resource (%s) (%v)", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFSecurityPolicies().Schema, "Unable to delete the Barracuda WAF resource (%s)", name)
 	}
 
 	return nil
 }
 
-func hydrateBarracudaWAFSecurityPoliciesResource(d *schema.ResourceData, method string, endpoint string) *APIRequest {
-
-	//resourcePayload : payload for the resource
-	resourcePayload := map[string]string{"based-on": d.Get("based_on").(string), "name": d.Get("name").(string)}
-
-	// parameters not supported for updates
-	if method == "put" {
-		updatePayloadExceptions := [...]string{"based-on"}
-		for _, param := range updatePayloadExceptions {
-			delete(resourcePayload, param)
-		}
-	}
-
-	// remove empty parameters from resource payload
-	for key, val := range resourcePayload {
-		if len(val) == 0 {
-			delete(resourcePayload, key)
-		}
-	}
-
-	return &APIRequest{
-		URL:  endpoint,
-		Body: resourcePayload,
+func hydrateBarracudaWAFSecurityPoliciesResource(d *schema.ResourceData) *waf.SecurityPolicy {
+	return &waf.SecurityPolicy{
+		Name:    d.Get("name").(string),
+		BasedOn: d.Get("based_on").(string),
 	}
 }
 
-func (b *BarracudaWAF) hydrateBarracudaWAFSecurityPoliciesSubResource(
-	d *schema.ResourceData,
-	name string,
-	endpoint string,
-) error {
-
-	for subResource, subResourceParams := range subResourceSecurityPoliciesParams {
-		subResourceParamsLength := d.Get(subResource + ".#").(int)
-
-		log.Printf("[INFO] Updating Barracuda WAF sub resource (%s) (%s)", name, subResource)
-
-		for i := 0; i < subResourceParamsLength; i++ {
-			subResourcePayload := map[string]string{}
-			suffix := fmt.Sprintf(".%d", i)
-
-			for _, param := range subResourceParams {
-				paramSuffix := fmt.Sprintf(".%s", param)
-				paramVaule := d.Get(subResource + suffix + paramSuffix).(string)
-
-				if len(paramVaule) > 0 {
-					param = strings.Replace(param, "_", "-", -1)
-					subResourcePayload[param] = paramVaule
-				}
-			}
-
-			err := b.UpdateBarracudaWAFSubResource(name, endpoint, &APIRequest{
-				URL:  strings.Replace(subResource, "_", "-", -1),
-				Body: subResourcePayload,
-			})
-
-			if err != nil {
-				return err
-			}
-		}
+// resourceCudaWAFSecurityPoliciesV0 : schema of the version 0 state of security policies, which is
+// a valid version 1 state.
+func resourceCudaWAFSecurityPoliciesV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"based_on": {Type: schema.TypeString, Optional: true},
+			"name":     {Type: schema.TypeString, Required: true},
+		},
 	}
-
-	return nil
 }
@@ -1,10 +1,13 @@
 package barracudawaf
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -27,40 +30,106 @@ func TestAccBarracudaWAFSecurityPolicy_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("barracudawaf_security_policies.demo_security_policy_1", "name", "DemoPolicy1"),
 				),
 			},
+			{
+				ResourceName:            "barracudawaf_security_policies.demo_security_policy_1",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"based_on"},
+			},
 		},
 	})
 }
 
 func testCheckSecurityPolicyExists(name string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*BarracudaWAF)
+		client := testAccProvider.Meta().(*waf.Client)
 
-		resourceEndpoint := "/security-policies/" + name
-		request := &APIRequest{
-			Method: "get",
-			URL:    resourceEndpoint,
+		if _, err := client.GetSecurityPolicy(context.Background(), name); err != nil {
+			return fmt.Errorf("security policy (%s) not found on the system (%v)", name, err)
 		}
 
-		securityPolicies, err := client.GetBarracudaWAFResource(name, request)
-		if err != nil {
-			return err
-		}
+		return nil
+	}
+}
 
-		if securityPolicies == nil {
-			return fmt.Errorf("security policy %s was not created.", name)
-		}
+func TestBarracudaWAFSecurityPolicy_crud(t *testing.T) {
+	crud := newTestResourceCRUD(t, resourceCudaWAFSecurityPolicies())
 
-		var dataItems map[string]interface{}
-		for _, dataItems = range securityPolicies.Data {
-			if dataItems["name"] == name {
-				break
-			}
-		}
+	raw := map[string]interface{}{"name": "DemoPolicy1", "based_on": "Create New"}
 
-		if dataItems["name"] != name {
-			return fmt.Errorf("security policy (%s) not found on the system", name)
-		}
+	d := crud.create(raw)
 
-		return nil
+	testCheckParams(t, crud.server.Object("security-policies/DemoPolicy1"), map[string]interface{}{"based-on": "Create New"})
+	testCheckAttributes(t, d, map[string]string{"name": "DemoPolicy1", "based_on": "Create New"})
+
+	d = crud.update(d.Id(), raw)
+
+	testCheckParams(t, crud.server.LastRequest("PUT", "/security-policies/DemoPolicy1").Body, map[string]interface{}{
+		"name":     "DemoPolicy1",
+		"based-on": nil,
+	})
+
+	crud.delete(d.Id(), raw)
+	if crud.server.Object("security-policies/DemoPolicy1") != nil {
+		t.Fatalf("expected the security policy to be deleted")
+	}
+
+	if d = crud.read(d.Id(), raw); d.Id() != "" {
+		t.Errorf("expected the deleted security policy to be removed from the state")
+	}
+}
+
+func TestBarracudaWAFSecurityPolicy_rename(t *testing.T) {
+	crud := newTestResourceCRUD(t, resourceCudaWAFSecurityPolicies())
+
+	raw := map[string]interface{}{"name": "DemoPolicy1", "based_on": "Create New"}
+
+	d := crud.create(raw)
+
+	raw["name"] = "DemoPolicy2"
+	d = crud.update(d.Id(), raw)
+
+	if d.Id() != "DemoPolicy2" {
+		t.Errorf("expected the ID to follow the new name, got %s", d.Id())
+	}
+
+	testCheckParams(t, crud.server.LastRequest("PUT", "/security-policies/DemoPolicy1").Body, map[string]interface{}{
+		"name": "DemoPolicy2",
+	})
+
+	if crud.server.Object("security-policies/DemoPolicy2") == nil || crud.server.Object("security-policies/DemoPolicy1") != nil {
+		t.Errorf("expected the security policy to be renamed in place")
+	}
+
+	if d = crud.read(d.Id(), raw); d.Id() != "DemoPolicy2" {
+		t.Errorf("expected the renamed security policy to be kept in the state")
+	}
+}
+
+func TestHydrateBarracudaWAFSecurityPoliciesResource(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCudaWAFSecurityPolicies().Schema, map[string]interface{}{
+		"name":     "DemoPolicy1",
+		"based_on": "default",
+	})
+
+	testCheckPayload(t, hydrateBarracudaWAFSecurityPoliciesResource(d), map[string]interface{}{
+		"name":     "DemoPolicy1",
+		"based-on": "default",
+	})
+}
+
+func TestBarracudaWAFSecurityPolicies_canceledContext(t *testing.T) {
+	crud := newTestResourceCRUD(t, resourceCudaWAFSecurityPolicies())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	d := schema.TestResourceDataRaw(t, crud.resource.Schema, map[string]interface{}{"name": "DemoPolicy1"})
+	if diags := crud.resource.CreateContext(ctx, d, crud.client); !diags.HasError() {
+		t.Fatalf("expected the create to fail with a canceled context")
+	}
+
+	if requests := crud.server.Requests("POST", "/security-policies"); len(requests) != 0 {
+		t.Errorf("expected no request to be sent, got %d", len(requests))
 	}
 }
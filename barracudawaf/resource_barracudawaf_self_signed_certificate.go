@@ -1,261 +1,272 @@
 package barracudawaf
 
 import (
-	"fmt"
+	"context"
 	"log"
-	"reflect"
-	"strings"
 
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-)
-
-var (
-	subResourceSelfSignedCertificateParams = map[string][]string{}
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceCudaWAFSelfSignedCertificate() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceCudaWAFSelfSignedCertificateCreate,
-		Read:   resourceCudaWAFSelfSignedCertificateRead,
-		Update: resourceCudaWAFSelfSignedCertificateUpdate,
-		Delete: resourceCudaWAFSelfSignedCertificateDelete,
+		CreateContext: resourceCudaWAFSelfSignedCertificateCreate,
+		ReadContext:   resourceCudaWAFSelfSignedCertificateRead,
+		UpdateContext: resourceCudaWAFSelfSignedCertificateUpdate,
+		DeleteContext: resourceCudaWAFSelfSignedCertificateDelete,
+		CustomizeDiff: resourceCudaWAFSelfSignedCertificateCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceCudaWAFSelfSignedCertificateV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: upgradeBarracudaWAFIdentityState,
+			},
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Read:   schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
 
 		Schema: map[string]*schema.Schema{
-			"city":                {Type: schema.TypeString, Optional: true, Description: "Locality Name"},
-			"common_name":         {Type: schema.TypeString, Required: true, Description: "Common Name"},
-			"country_code":        {Type: schema.TypeString, Required: true, Description: "Country"},
-			"elliptic_curve_name": {Type: schema.TypeString, Optional: true, Description: "Elliptic Curve Name"},
-			"key_size":            {Type: schema.TypeString, Optional: true, Description: "Key Size"},
-			"key_type":            {Type: schema.TypeString, Optional: true, Description: "Select Key Type:"},
+			"city": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Locality Name",
+				ForceNew:    true,
+			},
+			"common_name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Common Name",
+				ForceNew:    true,
+			},
+			"country_code": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Country",
+				ValidateFunc: validateBarracudaWAFCountryCode,
+				ForceNew:     true,
+			},
+			"elliptic_curve_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Elliptic Curve Name",
+				ForceNew:    true,
+			},
+			"key_size": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Key Size",
+				ValidateFunc: validateBarracudaWAFKeySize,
+				ForceNew:     true,
+			},
+			"key_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Select Key Type:",
+				ValidateFunc: validateBarracudaWAFKeyType,
+				ForceNew:     true,
+			},
 			"allow_private_key_export": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "If set to <b>Yes</b>, the Private Key gets downloaded along with the certificate.",
+				ValidateFunc: validateBarracudaWAFYesNo,
+				ForceNew:     true,
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "None",
+				ForceNew:    true,
+			},
+			"organization_name": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Description: "If set to <b>Yes</b>, the Private Key gets downloaded along with the certificate.",
+				Description: "Organization Name",
+				ForceNew:    true,
+			},
+			"organizational_unit": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Organizational Unit Name",
+				ForceNew:    true,
 			},
-			"name":                {Type: schema.TypeString, Required: true, Description: "None"},
-			"organization_name":   {Type: schema.TypeString, Optional: true, Description: "Organization Name"},
-			"organizational_unit": {Type: schema.TypeString, Optional: true, Description: "Organizational Unit Name"},
 			"san_certificate": {
 				Type:     schema.TypeList,
 				Optional: true,
+				Computed: true,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
 				Description: "SAN Certificate",
+				ForceNew:    true,
+			},
+			"state": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "State or Province",
+				ForceNew:    true,
+			},
+			"early_renewal_hours": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Hours before the expiry of the certificate from which it is replaced",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"expiry": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Expiry of the certificate",
+			},
+			"serial": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Serial Number of the certificate",
+			},
+			"issuer": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Issuer of the certificate",
+			},
+			"subject": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Subject of the certificate",
+			},
+			"sha256_fingerprint": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "SHA-256 fingerprint of the certificate",
 			},
-			"state": {Type: schema.TypeString, Optional: true, Description: "State or Province"},
 		},
 
 		Description: "`barracudawaf_self_signed_certificate` manages `Self Signed Certificate` on the Barracuda Web Application Firewall.",
 	}
 }
 
-func resourceCudaWAFSelfSignedCertificateCreate(d *schema.ResourceData, m interface{}) error {
-	client := m.(*BarracudaWAF)
+func resourceCudaWAFSelfSignedCertificateCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
 
 	name := d.Get("name").(string)
 
 	log.Println("[INFO] Creating Barracuda WAF resource " + name)
 
-	resourceEndpoint := "/self-signed-certificate"
-	err := client.CreateBarracudaWAFResource(
-		name,
-		hydrateBarracudaWAFSelfSignedCertificateResource(d, "post", resourceEndpoint),
-	)
+	err := client.CreateCertificate(ctx, waf.SelfSignedCertificates, hydrateBarracudaWAFSelfSignedCertificateResource(d))
 
 	if err != nil {
 		log.Printf("[ERROR] Unable to create Barracuda WAF resource (%s) (%v) ", name, err)
-		return err
-	}
-
-	err = client.hydrateBarracudaWAFSelfSignedCertificateSubResource(d, name, resourceEndpoint)
-
-	if err != nil {
-		log.Printf("[ERROR] Unable to create Barracuda WAF sub resource (%s) (%v) ", name, err)
-		return err
+		return barracudaWAFDiagnostics(err, resourceCudaWAFSelfSignedCertificate().Schema, "Unable to create Barracuda WAF resource (%s)", name)
 	}
 
 	d.SetId(name)
-	return resourceCudaWAFSelfSignedCertificateRead(d, m)
+	return resourceCudaWAFSelfSignedCertificateRead(ctx, d, m)
 }
 
-func resourceCudaWAFSelfSignedCertificateRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(*BarracudaWAF)
+func resourceCudaWAFSelfSignedCertificateRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
 
 	name := d.Id()
 	log.Println("[INFO] Fetching Barracuda WAF resource " + name)
 
-	resourceEndpoint := "/self-signed-certificate"
-	request := &APIRequest{
-		Method: "get",
-		URL:    resourceEndpoint,
-	}
-
-	var dataItems map[string]interface{}
-	resources, err := client.GetBarracudaWAFResource(name, request)
-
-	if err != nil {
-		log.Printf("[ERROR] Unable to Retrieve Barracuda WAF resource (%s) (%v) ", name, err)
-		return err
-	}
+	certificate, err := client.GetCertificate(ctx, waf.SelfSignedCertificates, name)
 
-	if resources.Data == nil {
+	if waf.IsNotFound(err) {
 		log.Printf("[WARN] Barracuda WAF resource (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
 	}
 
-	for _, dataItems = range resources.Data {
-		if dataItems["name"] == name {
-			break
-		}
+	if err != nil {
+		log.Printf("[ERROR] Unable to Retrieve Barracuda WAF resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFSelfSignedCertificate().Schema, "Unable to Retrieve Barracuda WAF resource (%s)", name)
 	}
 
-	if dataItems["name"] != name {
-		return fmt.Errorf("Barracuda WAF resource (%s) not found on the system", name)
+	err = setBarracudaWAFResourceData(d, resourceCudaWAFSelfSignedCertificate().Schema, certificate)
+
+	if err != nil {
+		log.Printf("[ERROR] Unable to set Barracuda WAF resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFSelfSignedCertificate().Schema, "Unable to set Barracuda WAF resource (%s)", name)
 	}
 
 	d.Set("name", name)
 	return nil
 }
 
-func resourceCudaWAFSelfSignedCertificateUpdate(d *schema.ResourceData, m interface{}) error {
-	client := m.(*BarracudaWAF)
-
-	name := d.Id()
-
-	log.Println("[INFO] Updating Barracuda WAF resource " + name)
-
-	resourceEndpoint := "/self-signed-certificate"
-	err := client.UpdateBarracudaWAFResource(
-		name,
-		hydrateBarracudaWAFSelfSignedCertificateResource(d, "put", resourceEndpoint),
-	)
-
-	if err != nil {
-		log.Printf("[ERROR] Unable to update the Barracuda WAF resource (%s) (%v)", name, err)
-		return err
-	}
-
-	err = client.hydrateBarracudaWAFSelfSignedCertificateSubResource(d, name, resourceEndpoint)
-
-	if err != nil {
-		log.Printf("[ERROR] Unable to update the Barracuda WAF sub resource (%s) (%v)", name, err)
-		return err
-	}
+// resourceCudaWAFSelfSignedCertificateUpdate : every attribute the WAF stores forces a new
+// certificate, only the early_renewal_hours held by Terraform can change in place.
+func resourceCudaWAFSelfSignedCertificateUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return resourceCudaWAFSelfSignedCertificateRead(ctx, d, m)
+}
 
-	return resourceCudaWAFSelfSignedCertificateRead(d, m)
+func resourceCudaWAFSelfSignedCertificateCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	return forceNewIfBarracudaWAFCertificateExpires(d)
 }
 
-func resourceCudaWAFSelfSignedCertificateDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(*BarracudaWAF)
+func resourceCudaWAFSelfSignedCertificateDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
 
 	name := d.Id()
 
 	log.Println("[INFO] Deleting Barracuda WAF resource " + name)
 
-	resourceEndpoint := "/self-signed-certificate"
-	request := &APIRequest{
-		Method: "delete",
-		URL:    resourceEndpoint,
-	}
-
-	err := client.DeleteBarracudaWAFResource(name, request)
+	err := client.DeleteCertificate(ctx, waf.SelfSignedCertificates, name)
 
 	if err != nil {
-		return fmt.Errorf("Unable to delete the Barracuda WAF resource (%s) (%v)", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFSelfSignedCertificate().Schema, "Unable to delete the Barracuda WAF resource (%s)", name)
 	}
 
 	return nil
 }
 
-func hydrateBarracudaWAFSelfSignedCertificateResource(d *schema.ResourceData, method string, endpoint string) *APIRequest {
-
-	//resourcePayload : payload for the resource
-	resourcePayload := map[string]interface{}{
-		"city":                     d.Get("city").(string),
-		"common-name":              d.Get("common_name").(string),
-		"country-code":             d.Get("country_code").(string),
-		"elliptic-curve-name":      d.Get("elliptic_curve_name").(string),
-		"key-size":                 d.Get("key_size").(string),
-		"key-type":                 d.Get("key_type").(string),
-		"allow-private-key-export": d.Get("allow_private_key_export").(string),
-		"name":                     d.Get("name").(string),
-		"organization-name":        d.Get("organization_name").(string),
-		"organizational-unit":      d.Get("organizational_unit").(string),
-		"san-certificate":          d.Get("san_certificate"),
-		"state":                    d.Get("state").(string),
-	}
-
-	// parameters not supported for updates
-	if method == "put" {
-		updatePayloadExceptions := [...]string{
-			"city",
-			"common-name",
-			"country-code",
-			"elliptic-curve-name",
-			"key-size",
-			"key-type",
-			"allow-private-key-export",
-			"name",
-			"organization-name",
-			"organizational-unit",
-			"san-certificate",
-			"state",
-		}
-		for _, param := range updatePayloadExceptions {
-			delete(resourcePayload, param)
-		}
-	}
-
-	// remove empty parameters from resource payload
-	for key, val := range resourcePayload {
-		if reflect.ValueOf(val).Len() == 0 {
-			delete(resourcePayload, key)
-		}
-	}
-
-	return &APIRequest{
-		URL:  endpoint,
-		Body: resourcePayload,
+func hydrateBarracudaWAFSelfSignedCertificateResource(d *schema.ResourceData) *waf.Certificate {
+	return &waf.Certificate{
+		City:                  d.Get("city").(string),
+		CommonName:            d.Get("common_name").(string),
+		CountryCode:           d.Get("country_code").(string),
+		EllipticCurveName:     d.Get("elliptic_curve_name").(string),
+		KeySize:               d.Get("key_size").(string),
+		KeyType:               d.Get("key_type").(string),
+		AllowPrivateKeyExport: d.Get("allow_private_key_export").(string),
+		Name:                  d.Get("name").(string),
+		OrganizationName:      d.Get("organization_name").(string),
+		OrganizationalUnit:    d.Get("organizational_unit").(string),
+		SANCertificate:        expandStringList(d.Get("san_certificate").([]interface{})),
+		State:                 d.Get("state").(string),
 	}
 }
 
-func (b *BarracudaWAF) hydrateBarracudaWAFSelfSignedCertificateSubResource(
-	d *schema.ResourceData,
-	name string,
-	endpoint string,
-) error {
-
-	for subResource, subResourceParams := range subResourceSelfSignedCertificateParams {
-		subResourceParamsLength := d.Get(subResource + ".#").(int)
-
-		log.Printf("[INFO] Updating Barracuda WAF sub resource (%s) (%s)", name, subResource)
-
-		for i := 0; i < subResourceParamsLength; i++ {
-			subResourcePayload := map[string]string{}
-			suffix := fmt.Sprintf(".%d", i)
-
-			for _, param := range subResourceParams {
-				paramSuffix := fmt.Sprintf(".%s", param)
-				paramVaule := d.Get(subResource + suffix + paramSuffix).(string)
-
-				if len(paramVaule) > 0 {
-					param = strings.Replace(param, "_", "-", -1)
-					subResourcePayload[param] = paramVaule
-				}
-			}
-
-			err := b.UpdateBarracudaWAFSubResource(name, endpoint, &APIRequest{
-				URL:  strings.Replace(subResource, "_", "-", -1),
-				Body: subResourcePayload,
-			})
-
-			if err != nil {
-				return err
-			}
-		}
+// resourceCudaWAFSelfSignedCertificateV0 : schema of the version 0 state of self signed certificates, which is
+// a valid version 1 state.
+func resourceCudaWAFSelfSignedCertificateV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"city":                     {Type: schema.TypeString, Optional: true},
+			"common_name":              {Type: schema.TypeString, Required: true},
+			"country_code":             {Type: schema.TypeString, Required: true},
+			"elliptic_curve_name":      {Type: schema.TypeString, Optional: true},
+			"key_size":                 {Type: schema.TypeString, Optional: true},
+			"key_type":                 {Type: schema.TypeString, Optional: true},
+			"allow_private_key_export": {Type: schema.TypeString, Optional: true},
+			"name":                     {Type: schema.TypeString, Required: true},
+			"organization_name":        {Type: schema.TypeString, Optional: true},
+			"organizational_unit":      {Type: schema.TypeString, Optional: true},
+			"san_certificate":          {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"state":                    {Type: schema.TypeString, Optional: true},
+		},
 	}
-
-	return nil
 }
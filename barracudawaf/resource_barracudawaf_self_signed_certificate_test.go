@@ -1,10 +1,14 @@
 package barracudawaf
 
 import (
+	"context"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -44,34 +48,114 @@ func TestAccBarracudaWAFSelfSignedCertificate_basic(t *testing.T) {
 
 func testCheckSelfSignedCertificateExists(name string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*BarracudaWAF)
+		client := testAccProvider.Meta().(*waf.Client)
 
-		resourceEndpoint := "/self-signed-certificate/" + name
-		request := &APIRequest{
-			Method: "get",
-			URL:    resourceEndpoint,
+		if _, err := client.GetCertificate(context.Background(), waf.SelfSignedCertificates, name); err != nil {
+			return fmt.Errorf("self signed certificate (%s) not found on the system (%v)", name, err)
 		}
 
-		selfSignedCerts, err := client.GetBarracudaWAFResource(name, request)
-		if err != nil {
-			return err
-		}
+		return nil
+	}
+}
 
-		if selfSignedCerts == nil {
-			return fmt.Errorf("self signed certificate %s was not created.", name)
-		}
+func TestBarracudaWAFSelfSignedCertificate_crud(t *testing.T) {
+	crud := newTestResourceCRUD(t, resourceCudaWAFSelfSignedCertificate())
+
+	raw := map[string]interface{}{
+		"name":         "DemoSelfSignedCert",
+		"common_name":  "barracuda.example.com",
+		"country_code": "US",
+		"key_size":     "2048",
+		"key_type":     "RSA",
+	}
 
-		var dataItems map[string]interface{}
-		for _, dataItems = range selfSignedCerts.Data {
-			if dataItems["name"] == name {
-				break
-			}
+	d := crud.create(raw)
+
+	testCheckParams(t, crud.server.Object("self-signed-certificate/DemoSelfSignedCert"), map[string]interface{}{
+		"common-name":  "barracuda.example.com",
+		"country-code": "US",
+	})
+	testCheckAttributes(t, d, map[string]string{"common_name": "barracuda.example.com", "key_size": "2048"})
+
+	crud.delete(d.Id(), raw)
+	if crud.server.Object("self-signed-certificate/DemoSelfSignedCert") != nil {
+		t.Fatalf("expected the certificate to be deleted")
+	}
+
+	if d = crud.read(d.Id(), raw); d.Id() != "" {
+		t.Errorf("expected the deleted certificate to be removed from the state")
+	}
+}
+
+func TestHydrateBarracudaWAFSelfSignedCertificateResource(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCudaWAFSelfSignedCertificate().Schema, map[string]interface{}{
+		"name":            "DemoSelfSignedCert",
+		"common_name":     "barracuda.example.com",
+		"san_certificate": []interface{}{"DNS:www.example.com"},
+	})
+
+	testCheckPayload(t, hydrateBarracudaWAFSelfSignedCertificateResource(d), map[string]interface{}{
+		"name":            "DemoSelfSignedCert",
+		"common-name":     "barracuda.example.com",
+		"san-certificate": []interface{}{"DNS:www.example.com"},
+		"city":            nil,
+	})
+}
+
+func TestBarracudaWAFSelfSignedCertificate_renewal(t *testing.T) {
+	resource := resourceCudaWAFSelfSignedCertificate()
+
+	cases := []struct {
+		expiry      time.Time
+		requiresNew bool
+	}{
+		{expiry: time.Now().Add(-time.Hour), requiresNew: true},      // expired
+		{expiry: time.Now().Add(24 * time.Hour), requiresNew: true},  // within the 48 early renewal hours
+		{expiry: time.Now().Add(96 * time.Hour), requiresNew: false}, // the early renewal hours change in place
+	}
+
+	for _, c := range cases {
+		state := &terraform.InstanceState{
+			ID: "DemoSelfSignedCert",
+			Attributes: map[string]string{
+				"id":                       "DemoSelfSignedCert",
+				"name":                     "DemoSelfSignedCert",
+				"common_name":              "barracuda.example.com",
+				"city":                     "",
+				"country_code":             "US",
+				"state":                    "",
+				"organization_name":        "",
+				"organizational_unit":      "",
+				"elliptic_curve_name":      "",
+				"key_size":                 "2048",
+				"key_type":                 "rsa",
+				"allow_private_key_export": "No",
+				"san_certificate.#":        "0",
+				"early_renewal_hours":      "0",
+				"expiry":                   c.expiry.UTC().Format(time.RFC3339),
+				"serial":                   "5F3A9C",
+				"issuer":                   "CN=barracuda.example.com",
+				"subject":                  "CN=barracuda.example.com",
+				"sha256_fingerprint":       "9C:0F:5E:2B",
+			},
 		}
 
-		if dataItems["name"] != name {
-			return fmt.Errorf("self signed certificate (%s) not found on the system", name)
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":                "DemoSelfSignedCert",
+			"common_name":         "barracuda.example.com",
+			"country_code":        "US",
+			"key_size":            "2048",
+			"key_type":            "rsa",
+			"early_renewal_hours": 48,
+		})
+
+		diff, err := resource.Diff(context.Background(), state, config, nil)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.expiry, err)
 		}
 
-		return nil
+		if diff.RequiresNew() != c.requiresNew {
+			t.Errorf("%s: expected requires new to be %t", c.expiry, c.requiresNew)
+		}
 	}
 }
@@ -1,53 +1,85 @@
 package barracudawaf
 
 import (
-	"fmt"
+	"context"
 	"log"
-	"strings"
 
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
-var (
-	subResourceServersParams = map[string][]string{
-		"ssl_policy": {
-			"client_certificate",
-			"enable_ssl_compatibility_mode",
-			"validate_certificate",
-			"enable_https",
-			"enable_sni",
-			"enable_ssl_3",
-			"enable_tls_1",
-			"enable_tls_1_1",
-			"enable_tls_1_2",
-			"enable_tls_1_3",
+func resourceCudaWAFServers() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceCudaWAFServersCreate,
+		ReadContext:   resourceCudaWAFServersRead,
+		UpdateContext: resourceCudaWAFServersUpdate,
+		DeleteContext: resourceCudaWAFServersDelete,
+		CustomizeDiff: resourceCudaWAFServersCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: importBarracudaWAFNamedParentsResource("service_name"),
 		},
-		"connection_pooling": {
-			"keepalive_timeout",
-			"enable_connection_pooling",
+
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceCudaWAFServersV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: upgradeBarracudaWAFDeprecatedParentState("service_name"),
+			},
 		},
-	}
-)
 
-func resourceCudaWAFServers() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceCudaWAFServersCreate,
-		Read:   resourceCudaWAFServersRead,
-		Update: resourceCudaWAFServersUpdate,
-		Delete: resourceCudaWAFServersDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Read:   schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
 
 		Schema: map[string]*schema.Schema{
-			"address_version": {Type: schema.TypeString, Optional: true, Description: "Version"},
-			"comments":        {Type: schema.TypeString, Optional: true, Description: "Comments"},
-			"name":            {Type: schema.TypeString, Optional: true, Description: "Server Name"},
-			"hostname":        {Type: schema.TypeString, Optional: true, Description: "Hostname"},
-			"identifier":      {Type: schema.TypeString, Optional: true, Description: "Identifier"},
-			"ip_address":      {Type: schema.TypeString, Optional: true, Description: "Server IP"},
-			"port":            {Type: schema.TypeString, Optional: true, Description: "Server Port"},
-			"status":          {Type: schema.TypeString, Optional: true, Description: "Status"},
+			"address_version": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Version",
+				ValidateFunc: validateBarracudaWAFAddressVersion,
+				ForceNew:     true,
+			},
+			"comments": {Type: schema.TypeString, Optional: true, Description: "Comments"},
+			"name":     {Type: schema.TypeString, Required: true, Description: "Server Name"},
+			"hostname": {Type: schema.TypeString, Optional: true, Description: "Hostname"},
+			"identifier": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Identifier",
+				ValidateFunc: validateBarracudaWAFServerIdentifier,
+			},
+			"ip_address": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Server IP",
+				ValidateFunc: validateBarracudaWAFIPAddress,
+			},
+			"port": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Server Port",
+				ValidateFunc: validateBarracudaWAFPort,
+			},
+			"status": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Status",
+				ValidateFunc: validateBarracudaWAFServerStatus,
+			},
 			"ssl_policy": {
 				Type:     schema.TypeList,
 				Optional: true,
+				Computed: true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"client_certificate": {
@@ -56,63 +88,109 @@ func resourceCudaWAFServers() *schema.Resource {
 							Description: "Client Certificate",
 						},
 						"enable_ssl_compatibility_mode": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: "Enable SSL Compatibility Mode",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "Enable SSL Compatibility Mode",
+							ValidateFunc: validateBarracudaWAFYesNo,
 						},
 						"validate_certificate": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: "Validate Server Certificate",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "Validate Server Certificate",
+							ValidateFunc: validateBarracudaWAFYesNo,
 						},
 						"enable_https": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: "Server uses SSL",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "Server uses SSL",
+							ValidateFunc: validateBarracudaWAFYesNo,
 						},
 						"enable_sni": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: "Enable SNI",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "Enable SNI",
+							ValidateFunc: validateBarracudaWAFYesNo,
 						},
 						"enable_ssl_3": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: "SSL 3.0 (Insecure)",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "SSL 3.0 (Insecure)",
+							ValidateFunc: validateBarracudaWAFYesNo,
 						},
 						"enable_tls_1": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: "TLS 1.0 (Insecure)",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "TLS 1.0 (Insecure)",
+							ValidateFunc: validateBarracudaWAFYesNo,
+						},
+						"enable_tls_1_1": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "TLS 1.1",
+							ValidateFunc: validateBarracudaWAFYesNo,
+						},
+						"enable_tls_1_2": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "TLS 1.2",
+							ValidateFunc: validateBarracudaWAFYesNo,
+						},
+						"enable_tls_1_3": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "TLS 1.3",
+							ValidateFunc: validateBarracudaWAFYesNo,
 						},
-						"enable_tls_1_1": {Type: schema.TypeString, Optional: true, Description: "TLS 1.1"},
-						"enable_tls_1_2": {Type: schema.TypeString, Optional: true, Description: "TLS 1.2"},
-						"enable_tls_1_3": {Type: schema.TypeString, Optional: true, Description: "TLS 1.3"},
 					},
 				},
 			},
 			"connection_pooling": {
 				Type:     schema.TypeList,
 				Optional: true,
+				Computed: true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"keepalive_timeout": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: "Keepalive Timeout",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "Keepalive Timeout",
+							ValidateFunc: validateBarracudaWAFIntString(0, 2147483647),
 						},
 						"enable_connection_pooling": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: "Enable Connection Pooling",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "Enable Connection Pooling",
+							ValidateFunc: validateBarracudaWAFYesNo,
 						},
 					},
 				},
 			},
+			"service_name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"parent", "service_name"},
+				Description:  "Name of the service the server is configured in",
+			},
 			"parent": {
-				Type:     schema.TypeList,
-				Elem:     &schema.Schema{Type: schema.TypeString},
-				Required: true,
+				Type:         schema.TypeList,
+				Optional:     true,
+				Computed:     true,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				ExactlyOneOf: []string{"parent", "service_name"},
+				Deprecated:   "use service_name instead",
+				Description:  "Name of the service the server is configured in, as a single element list",
 			},
 		},
 
@@ -120,185 +198,249 @@ func resourceCudaWAFServers() *schema.Resource {
 	}
 }
 
-func resourceCudaWAFServersCreate(d *schema.ResourceData, m interface{}) error {
-	client := m.(*BarracudaWAF)
+func resourceCudaWAFServersCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
 
 	name := d.Get("name").(string)
+	service := d.Get("service_name").(string)
 
 	log.Println("[INFO] Creating Barracuda WAF resource " + name)
 
-	resourceEndpoint := "/services/" + d.Get("parent.0").(string) + "/servers"
-	err := client.CreateBarracudaWAFResource(name, hydrateBarracudaWAFServersResource(d, "post", resourceEndpoint))
+	err := client.CreateServer(ctx, service, hydrateBarracudaWAFServersResource(d))
 
 	if err != nil {
 		log.Printf("[ERROR] Unable to create Barracuda WAF resource (%s) (%v) ", name, err)
-		return err
+		return barracudaWAFDiagnostics(err, resourceCudaWAFServers().Schema, "Unable to create Barracuda WAF resource (%s)", name)
 	}
 
-	err = client.hydrateBarracudaWAFServersSubResource(d, name, resourceEndpoint)
+	err = hydrateBarracudaWAFServersSubResource(ctx, client, d, service, name)
 
 	if err != nil {
 		log.Printf("[ERROR] Unable to create Barracuda WAF sub resource (%s) (%v) ", name, err)
-		return err
+		return barracudaWAFDiagnostics(err, resourceCudaWAFServers().Schema, "Unable to create Barracuda WAF sub resource (%s)", name)
 	}
 
 	d.SetId(name)
-	return resourceCudaWAFServersRead(d, m)
+	return resourceCudaWAFServersRead(ctx, d, m)
 }
 
-func resourceCudaWAFServersRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(*BarracudaWAF)
+func resourceCudaWAFServersRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
 
 	name := d.Id()
+	service := d.Get("service_name").(string)
 	log.Println("[INFO] Fetching Barracuda WAF resource " + name)
 
-	resourceEndpoint := "/services/" + d.Get("parent.0").(string) + "/servers"
-	request := &APIRequest{
-		Method: "get",
-		URL:    resourceEndpoint,
-	}
+	server, err := client.GetServer(ctx, service, name)
 
-	var dataItems map[string]interface{}
-	resources, err := client.GetBarracudaWAFResource(name, request)
+	if waf.IsNotFound(err) {
+		log.Printf("[WARN] Barracuda WAF resource (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
 
 	if err != nil {
 		log.Printf("[ERROR] Unable to Retrieve Barracuda WAF resource (%s) (%v) ", name, err)
-		return err
+		return barracudaWAFDiagnostics(err, resourceCudaWAFServers().Schema, "Unable to Retrieve Barracuda WAF resource (%s)", name)
 	}
 
-	if resources.Data == nil {
-		log.Printf("[WARN] Barracuda WAF resource (%s) not found, removing from state", d.Id())
-		d.SetId("")
-		return nil
-	}
+	resourceSchema := resourceCudaWAFServers().Schema
+	err = setBarracudaWAFResourceData(d, resourceSchema, server, "service_name")
 
-	for _, dataItems = range resources.Data {
-		if dataItems["name"] == name {
-			break
-		}
+	if err != nil {
+		log.Printf("[ERROR] Unable to set Barracuda WAF resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFServers().Schema, "Unable to set Barracuda WAF resource (%s)", name)
 	}
 
-	if dataItems["name"] != name {
-		return fmt.Errorf("Barracuda WAF resource (%s) not found on the system", name)
+	err = readBarracudaWAFServersSubResource(ctx, client, d, resourceSchema, service, name)
+
+	if err != nil {
+		log.Printf("[ERROR] Unable to Retrieve Barracuda WAF sub resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFServers().Schema, "Unable to Retrieve Barracuda WAF sub resource (%s)", name)
 	}
 
+	setBarracudaWAFDeprecatedParent(d, service)
 	d.Set("name", name)
 	return nil
 }
 
-func resourceCudaWAFServersUpdate(d *schema.ResourceData, m interface{}) error {
-	client := m.(*BarracudaWAF)
+func resourceCudaWAFServersUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
 
 	name := d.Id()
+	service := d.Get("service_name").(string)
 
 	log.Println("[INFO] Updating Barracuda WAF resource " + name)
 
-	resourceEndpoint := "/services/" + d.Get("parent.0").(string) + "/servers"
-	err := client.UpdateBarracudaWAFResource(name, hydrateBarracudaWAFServersResource(d, "put", resourceEndpoint))
+	err := client.UpdateServer(ctx, service, name, hydrateBarracudaWAFServersResource(d))
 
 	if err != nil {
-		log.Printf("[ERROR] Unable to update the Barracuda WAF resource (%s) (%v)", name, err)
-		return err
+		log.Printf("[ERROR] Unable to update the Barracuda WAF resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFServers().Schema, "Unable to update the Barracuda WAF resource (%s)", name)
 	}
 
-	err = client.hydrateBarracudaWAFServersSubResource(d, name, resourceEndpoint)
+	name = renameBarracudaWAFResource(d)
+
+	err = hydrateBarracudaWAFServersSubResource(ctx, client, d, service, name)
 
 	if err != nil {
-		log.Printf("[ERROR] Unable to update the Barracuda WAF sub resource (%s) (%v)", name, err)
+		log.Printf("[ERROR] Unable to update the Barracuda WAF sub resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFServers().Schema, "Unable to update the Barracuda WAF sub resource (%s)", name)
+	}
+
+	return resourceCudaWAFServersRead(ctx, d, m)
+}
+
+func resourceCudaWAFServersCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if err := setBarracudaWAFServiceNameFromParent(d); err != nil {
 		return err
 	}
 
-	return resourceCudaWAFServersRead(d, m)
+	oldService, newService := d.GetChange("service_name")
+
+	_, err := forceNewIfBarracudaWAFParentChanges(
+		ctx,
+		d,
+		m,
+		[]string{"service_name"},
+		waf.ServicePath(oldService.(string)),
+		waf.ServicePath(newService.(string)),
+		func(ctx context.Context) error {
+			_, err := m.(*waf.Client).GetService(ctx, oldService.(string))
+			return err
+		},
+	)
+
+	return err
 }
 
-func resourceCudaWAFServersDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(*BarracudaWAF)
+func resourceCudaWAFServersDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
 
 	name := d.Id()
+	service := d.Get("service_name").(string)
 
 	log.Println("[INFO] Deleting Barracuda WAF resource " + name)
 
-	resourceEndpoint := "/services/" + d.Get("parent.0").(string) + "/servers"
-	request := &APIRequest{
-		Method: "delete",
-		URL:    resourceEndpoint,
-	}
-
-	err := client.DeleteBarracudaWAFResource(name, request)
+	err := client.DeleteServer(ctx, service, name)
 
 	if err != nil {
-		return fmt.Errorf("Unable to delete the Barracuda WAF resource (%s) (%v)", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFServers().Schema, "Unable to delete the Barracuda WAF resource (%s)", name)
 	}
 
 	return nil
 }
 
-func hydrateBarracudaWAFServersResource(d *schema.ResourceData, method string, endpoint string) *APIRequest {
-
-	//resourcePayload : payload for the resource
-	resourcePayload := map[string]string{
-		"address-version": d.Get("address_version").(string),
-		"comments":        d.Get("comments").(string),
-		"name":            d.Get("name").(string),
-		"hostname":        d.Get("hostname").(string),
-		"identifier":      d.Get("identifier").(string),
-		"ip-address":      d.Get("ip_address").(string),
-		"port":            d.Get("port").(string),
-		"status":          d.Get("status").(string),
+func hydrateBarracudaWAFServersResource(d *schema.ResourceData) *waf.Server {
+	return &waf.Server{
+		AddressVersion: d.Get("address_version").(string),
+		Comments:       d.Get("comments").(string),
+		Name:           d.Get("name").(string),
+		Hostname:       d.Get("hostname").(string),
+		Identifier:     d.Get("identifier").(string),
+		IPAddress:      d.Get("ip_address").(string),
+		Port:           d.Get("port").(string),
+		Status:         d.Get("status").(string),
 	}
+}
 
-	// parameters not supported for updates
-	if method == "put" {
-		updatePayloadExceptions := [...]string{"address-version"}
-		for _, param := range updatePayloadExceptions {
-			delete(resourcePayload, param)
+func hydrateBarracudaWAFServersSubResource(ctx context.Context, client *waf.Client, d *schema.ResourceData, service string, name string) error {
+	log.Printf("[INFO] Updating Barracuda WAF sub resources (%s)", name)
+
+	sslPolicy := &waf.SSLPolicy{}
+	if expandBarracudaWAFSubResource(d, "ssl_policy", sslPolicy) {
+		if err := client.UpdateServerSSLPolicy(ctx, service, name, sslPolicy); err != nil {
+			return &subResourceError{subResource: "ssl_policy", err: err}
 		}
 	}
 
-	// remove empty parameters from resource payload
-	for key, val := range resourcePayload {
-		if len(val) == 0 {
-			delete(resourcePayload, key)
+	connectionPooling := &waf.ConnectionPooling{}
+	if expandBarracudaWAFSubResource(d, "connection_pooling", connectionPooling) {
+		if err := client.UpdateServerConnectionPooling(ctx, service, name, connectionPooling); err != nil {
+			return &subResourceError{subResource: "connection_pooling", err: err}
 		}
 	}
 
-	return &APIRequest{
-		URL:  endpoint,
-		Body: resourcePayload,
-	}
+	return nil
 }
 
-func (b *BarracudaWAF) hydrateBarracudaWAFServersSubResource(d *schema.ResourceData, name string, endpoint string) error {
-
-	for subResource, subResourceParams := range subResourceServersParams {
-		subResourceParamsLength := d.Get(subResource + ".#").(int)
-
-		log.Printf("[INFO] Updating Barracuda WAF sub resource (%s) (%s)", name, subResource)
-
-		for i := 0; i < subResourceParamsLength; i++ {
-			subResourcePayload := map[string]string{}
-			suffix := fmt.Sprintf(".%d", i)
+// readBarracudaWAFServersSubResource : sets the sub resources of the server, shared with
+// the barracudawaf_servers data source.
+func readBarracudaWAFServersSubResource(
+	ctx context.Context,
+	client *waf.Client,
+	d *schema.ResourceData,
+	resourceSchema map[string]*schema.Schema,
+	service string,
+	name string,
+) error {
+	log.Printf("[INFO] Fetching Barracuda WAF sub resources (%s)", name)
+
+	sslPolicy, err := client.GetServerSSLPolicy(ctx, service, name)
+	if err != nil {
+		return err
+	}
 
-			for _, param := range subResourceParams {
-				paramSuffix := fmt.Sprintf(".%s", param)
-				paramVaule := d.Get(subResource + suffix + paramSuffix).(string)
+	if err := setBarracudaWAFSubResourceData(d, resourceSchema, "ssl_policy", sslPolicy); err != nil {
+		return err
+	}
 
-				if len(paramVaule) > 0 {
-					param = strings.Replace(param, "_", "-", -1)
-					subResourcePayload[param] = paramVaule
-				}
-			}
+	connectionPooling, err := client.GetServerConnectionPooling(ctx, service, name)
+	if err != nil {
+		return err
+	}
 
-			err := b.UpdateBarracudaWAFSubResource(name, endpoint, &APIRequest{
-				URL:  strings.Replace(subResource, "_", "-", -1),
-				Body: subResourcePayload,
-			})
+	return setBarracudaWAFSubResourceData(d, resourceSchema, "connection_pooling", connectionPooling)
+}
 
-			if err != nil {
-				return err
-			}
-		}
+// resourceCudaWAFServersV0 : schema of the version 0 state of servers and content rule
+// servers, the names of the parents were held by the "parent" list.
+func resourceCudaWAFServersV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"comments":        {Type: schema.TypeString, Optional: true, Computed: true},
+			"name":            {Type: schema.TypeString, Optional: true, Computed: true},
+			"hostname":        {Type: schema.TypeString, Optional: true, Computed: true},
+			"identifier":      {Type: schema.TypeString, Optional: true, Computed: true},
+			"ip_address":      {Type: schema.TypeString, Optional: true, Computed: true},
+			"address_version": {Type: schema.TypeString, Optional: true, Computed: true},
+			"port":            {Type: schema.TypeString, Optional: true, Computed: true},
+			"status":          {Type: schema.TypeString, Optional: true, Computed: true},
+			"ssl_policy": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"client_certificate":            {Type: schema.TypeString, Optional: true, Computed: true},
+						"enable_ssl_compatibility_mode": {Type: schema.TypeString, Optional: true, Computed: true},
+						"validate_certificate":          {Type: schema.TypeString, Optional: true, Computed: true},
+						"enable_https":                  {Type: schema.TypeString, Optional: true, Computed: true},
+						"enable_sni":                    {Type: schema.TypeString, Optional: true, Computed: true},
+						"enable_ssl_3":                  {Type: schema.TypeString, Optional: true, Computed: true},
+						"enable_tls_1":                  {Type: schema.TypeString, Optional: true, Computed: true},
+						"enable_tls_1_1":                {Type: schema.TypeString, Optional: true, Computed: true},
+						"enable_tls_1_2":                {Type: schema.TypeString, Optional: true, Computed: true},
+						"enable_tls_1_3":                {Type: schema.TypeString, Optional: true, Computed: true},
+					},
+				},
+			},
+			"connection_pooling": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"keepalive_timeout":         {Type: schema.TypeString, Optional: true, Computed: true},
+						"enable_connection_pooling": {Type: schema.TypeString, Optional: true, Computed: true},
+					},
+				},
+			},
+			"parent": {
+				Type:     schema.TypeList,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Required: true,
+			},
+		},
 	}
-
-	return nil
 }
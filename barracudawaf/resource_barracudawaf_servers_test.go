@@ -1,10 +1,14 @@
 package barracudawaf
 
 import (
+	"context"
 	"fmt"
+	"reflect"
 	"testing"
 
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -29,7 +33,7 @@ resource "barracudawaf_servers" "demo_server_1" {
     status          = "In Service"
     port            = "80"
     comments        = "Creating the Demo Server"
-    parent          = [ "DemoApp1" ]
+    service_name    = "DemoApp1"
 
     depends_on = [ barracudawaf_services.demo_app_1 ]
 }
@@ -51,40 +55,343 @@ func TestAccBarracudaWAFServer_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("barracudawaf_servers.demo_server_1", "identifier", "IP Address"),
 				),
 			},
+			{
+				ResourceName:      "barracudawaf_servers.demo_server_1",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateId:     "DemoApp1/DemoServer1",
+			},
 		},
 	})
 }
 
 func testCheckServerExists(name string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*BarracudaWAF)
+		client := testAccProvider.Meta().(*waf.Client)
 
-		resourceEndpoint := "/services/DemoApp1/servers/" + name
-		request := &APIRequest{
-			Method: "get",
-			URL:    resourceEndpoint,
+		if _, err := client.GetServer(context.Background(), "DemoApp1", name); err != nil {
+			return fmt.Errorf("server (%s) not found on the system (%v)", name, err)
 		}
 
-		servers, err := client.GetBarracudaWAFResource(name, request)
-		if err != nil {
-			return err
+		return nil
+	}
+}
+
+func TestBarracudaWAFServer_crud(t *testing.T) {
+	crud := newTestResourceCRUD(t, resourceCudaWAFServers())
+	crud.server.SetObject("services/DemoApp1", nil)
+
+	raw := map[string]interface{}{
+		"name":               "DemoServer1",
+		"ip_address":         "99.86.47.44",
+		"identifier":         "IP Address",
+		"address_version":    "IPv4",
+		"status":             "In Service",
+		"port":               "80",
+		"service_name":       "DemoApp1",
+		"connection_pooling": []interface{}{map[string]interface{}{"keepalive_timeout": "900000"}},
+	}
+
+	d := crud.create(raw)
+
+	testCheckParams(t, crud.server.Object("services/DemoApp1/servers/DemoServer1"), map[string]interface{}{
+		"ip-address": "99.86.47.44",
+		"port":       "80",
+	})
+	testCheckParams(t, crud.server.SubResource("services/DemoApp1/servers/DemoServer1", "connection-pooling"), map[string]interface{}{
+		"keepalive-timeout": "900000",
+	})
+	testCheckAttributes(t, d, map[string]string{
+		"status":                                 "In Service",
+		"connection_pooling.0.keepalive_timeout": "900000",
+	})
+
+	raw["status"] = "Out of Service Maintenance"
+	d = crud.update(d.Id(), raw)
+
+	testCheckParams(t, crud.server.LastRequest("PUT", "/services/DemoApp1/servers/DemoServer1").Body, map[string]interface{}{
+		"status":          "Out of Service Maintenance",
+		"address-version": nil,
+	})
+	testCheckAttributes(t, d, map[string]string{"status": "Out of Service Maintenance", "service_name": "DemoApp1"})
+
+	crud.delete(d.Id(), raw)
+	if crud.server.Object("services/DemoApp1/servers/DemoServer1") != nil {
+		t.Fatalf("expected the server to be deleted")
+	}
+
+	if d = crud.read(d.Id(), raw); d.Id() != "" {
+		t.Errorf("expected the deleted server to be removed from the state")
+	}
+}
+
+func TestBarracudaWAFServer_rename(t *testing.T) {
+	crud := newTestResourceCRUD(t, resourceCudaWAFServers())
+	services := crud.with(resourceCudaWAFServices())
+
+	service := map[string]interface{}{"name": "DemoApp1", "ip_address": "172.30.1.4", "port": "80", "type": "HTTP"}
+	services.create(service)
+
+	raw := map[string]interface{}{
+		"name":               "DemoServer1",
+		"ip_address":         "99.86.47.44",
+		"port":               "80",
+		"service_name":       "DemoApp1",
+		"connection_pooling": []interface{}{map[string]interface{}{"keepalive_timeout": "900000"}},
+	}
+
+	d := crud.create(raw)
+
+	raw["name"] = "DemoServer2"
+	d = crud.update(d.Id(), raw)
+
+	if d.Id() != "DemoServer2" {
+		t.Errorf("expected the ID to follow the new name, got %s", d.Id())
+	}
+
+	testCheckParams(t, crud.server.LastRequest("PUT", "/services/DemoApp1/servers/DemoServer1").Body, map[string]interface{}{
+		"name": "DemoServer2",
+	})
+	crud.server.LastRequest("PUT", "/services/DemoApp1/servers/DemoServer2/connection-pooling")
+
+	if crud.server.Object("services/DemoApp1/servers/DemoServer1") != nil {
+		t.Errorf("expected the old name to be released")
+	}
+
+	service["name"] = "DemoApp2"
+	services.update("DemoApp1", service)
+
+	raw["service_name"] = "DemoApp2"
+	if d = crud.read(d.Id(), raw); d.Id() != "DemoServer2" {
+		t.Fatalf("expected the server to resolve under the renamed service")
+	}
+
+	testCheckAttributes(t, d, map[string]string{"ip_address": "99.86.47.44", "service_name": "DemoApp2"})
+}
+
+func TestBarracudaWAFServer_deprecatedParent(t *testing.T) {
+	crud := newTestResourceCRUD(t, resourceCudaWAFServers())
+	crud.server.SetObject("services/DemoApp1", nil)
+	crud.server.SetObject("services/DemoApp2", nil)
+
+	config := map[string]interface{}{
+		"name":       "DemoServer1",
+		"ip_address": "99.86.47.44",
+		"port":       "80",
+		"parent":     []interface{}{"DemoApp1"},
+	}
+
+	diff, err := crud.resource.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), crud.client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	state, diags := crud.resource.Apply(context.Background(), nil, diff, crud.client)
+	if diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+
+	if crud.server.Object("services/DemoApp1/servers/DemoServer1") == nil {
+		t.Fatalf("expected the server to be created in the service named by parent")
+	}
+
+	if state.Attributes["service_name"] != "DemoApp1" || state.Attributes["parent.0"] != "DemoApp1" {
+		t.Errorf("expected service_name and parent to name the service, got %v", state.Attributes)
+	}
+
+	// the version 0 state keeps the parent list along with service_name
+	upgraded, err := crud.resource.StateUpgraders[0].Upgrade(context.Background(), map[string]interface{}{
+		"id":     "DemoServer1",
+		"name":   "DemoServer1",
+		"parent": []interface{}{"DemoApp1"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(upgraded["parent"], []interface{}{"DemoApp1"}) || upgraded["service_name"] != "DemoApp1" {
+		t.Errorf("expected the upgraded state to name the service by both attributes, got %v", upgraded)
+	}
+
+	if diff, err = crud.resource.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), crud.client); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if diff != nil && (diff.RequiresNew() || diff.Attributes["service_name"] != nil || diff.Attributes["parent.0"] != nil) {
+		t.Errorf("expected no change while the configuration uses parent, got %v", diff.Attributes)
+	}
+
+	config["parent"] = []interface{}{"DemoApp2"}
+	if diff, err = crud.resource.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), crud.client); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !diff.RequiresNew() || diff.Attributes["service_name"].New != "DemoApp2" {
+		t.Errorf("expected moving the server with parent to force a new resource, got %v", diff)
+	}
+
+	delete(config, "parent")
+	config["service_name"] = "DemoApp1"
+	if diff, err = crud.resource.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), crud.client); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if diff != nil && (diff.RequiresNew() || diff.Attributes["service_name"] != nil || diff.Attributes["parent.0"] != nil) {
+		t.Errorf("expected no change when parent is replaced by service_name, got %v", diff.Attributes)
+	}
+
+	for _, raw := range []map[string]interface{}{
+		{"name": "DemoServer1"},
+		{"name": "DemoServer1", "service_name": "DemoApp1", "parent": []interface{}{"DemoApp1"}},
+	} {
+		if diags := crud.resource.Validate(terraform.NewResourceConfigRaw(raw)); !diags.HasError() {
+			t.Errorf("expected exactly one of parent and service_name to be required for %v", raw)
 		}
+	}
+}
+
+func TestBarracudaWAFServer_parentChange(t *testing.T) {
+	cases := []struct {
+		name        string
+		services    []string // services existing on the WAF
+		rename      bool     // DemoApp1 is renamed to DemoApp2 in the same plan
+		parent      string
+		requiresNew bool
+	}{
+		{name: "renamed", services: []string{"DemoApp1"}, rename: true, parent: "DemoApp2", requiresNew: false},
+		{name: "created", services: []string{"DemoApp1"}, parent: "DemoApp2", requiresNew: true},
+		{name: "existing", services: []string{"DemoApp1", "DemoApp3"}, parent: "DemoApp3", requiresNew: true},
+		{name: "renamed already", services: []string{"DemoApp2"}, parent: "DemoApp2", requiresNew: false},
+	}
 
-		if servers == nil {
-			return fmt.Errorf("server %s was not created.", name)
+	for _, c := range cases {
+		crud := newTestResourceCRUD(t, resourceCudaWAFServers())
+		for _, service := range c.services {
+			crud.server.SetObject("services/"+service, nil)
 		}
 
-		var dataItems map[string]interface{}
-		for _, dataItems = range servers.Data {
-			if dataItems["name"] == name {
-				break
+		if c.rename {
+			serviceState := &terraform.InstanceState{
+				ID: "DemoApp1",
+				Attributes: map[string]string{
+					"id":              "DemoApp1",
+					"name":            "DemoApp1",
+					"address_version": "IPv4",
+					"group":           "default",
+					"type":            "HTTP",
+					"vsite":           "default",
+				},
+			}
+			serviceConfig := terraform.NewResourceConfigRaw(map[string]interface{}{"name": "DemoApp2"})
+
+			diff, err := resourceCudaWAFServices().Diff(context.Background(), serviceState, serviceConfig, crud.client)
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", c.name, err)
+			}
+
+			if diff.RequiresNew() {
+				t.Fatalf("%s: expected the service to be renamed in place", c.name)
 			}
 		}
 
-		if dataItems["name"] != name {
-			return fmt.Errorf("server (%s) not found on the system", name)
+		state := &terraform.InstanceState{
+			ID: "DemoServer1",
+			Attributes: map[string]string{
+				"id":              "DemoServer1",
+				"name":            "DemoServer1",
+				"address_version": "IPv4",
+				"service_name":    "DemoApp1",
+			},
 		}
 
-		return nil
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":         "DemoServer1",
+			"service_name": c.parent,
+		})
+
+		diff, err := crud.resource.Diff(context.Background(), state, config, crud.client)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.name, err)
+		}
+
+		if diff.RequiresNew() != c.requiresNew {
+			t.Errorf("%s: expected requires new to be %t", c.name, c.requiresNew)
+		}
+	}
+}
+
+func TestBarracudaWAFServer_removedArgument(t *testing.T) {
+	resource := resourceCudaWAFServers()
+
+	state := &terraform.InstanceState{
+		ID: "DemoServer1",
+		Attributes: map[string]string{
+			"id":                                     "DemoServer1",
+			"name":                                   "DemoServer1",
+			"address_version":                        "IPv4",
+			"identifier":                             "Hostname",
+			"hostname":                               "web1.example.com",
+			"comments":                               "web tier",
+			"ip_address":                             "99.86.47.44",
+			"port":                                   "8080",
+			"status":                                 "Out of Service Maintenance",
+			"service_name":                           "DemoApp1",
+			"connection_pooling.#":                   "1",
+			"connection_pooling.0.keepalive_timeout": "1000",
+		},
+	}
+
+	config := map[string]interface{}{
+		"name":               "DemoServer1",
+		"service_name":       "DemoApp1",
+		"connection_pooling": []interface{}{map[string]interface{}{}},
+	}
+
+	diff, err := resource.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the free-text arguments removed from the configuration are cleared
+	for _, key := range []string{"hostname", "comments"} {
+		if attr, ok := diff.Attributes[key]; !ok || attr.New != "" {
+			t.Errorf("expected %s to be cleared, got %+v", key, attr)
+		}
 	}
+
+	// the other arguments keep the value read back from the WAF
+	for _, key := range []string{"identifier", "ip_address", "port", "status", "connection_pooling.0.keepalive_timeout"} {
+		if attr, ok := diff.Attributes[key]; ok {
+			t.Errorf("expected %s not to change, got %+v", key, attr)
+		}
+	}
+
+	if diff.RequiresNew() {
+		t.Errorf("expected the server to be updated in place")
+	}
+
+	d := schema.TestResourceDataRaw(t, resource.Schema, config)
+
+	testCheckPayload(t, hydrateBarracudaWAFServersResource(d), map[string]interface{}{
+		"hostname": "",
+		"comments": "",
+		"port":     nil,
+		"status":   nil,
+	})
+}
+
+func TestHydrateBarracudaWAFServersResource(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCudaWAFServers().Schema, map[string]interface{}{
+		"name":            "DemoServer1",
+		"address_version": "IPv4",
+		"hostname":        "",
+		"service_name":    "DemoApp1",
+	})
+
+	testCheckPayload(t, hydrateBarracudaWAFServersResource(d), map[string]interface{}{
+		"name":            "DemoServer1",
+		"address-version": "IPv4",
+		"hostname":        "",
+		"service_name":    nil,
+	})
 }
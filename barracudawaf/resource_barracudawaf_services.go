@@ -1,100 +1,141 @@
 package barracudawaf
 
 import (
-	"fmt"
+	"context"
 	"log"
-	"reflect"
-	"strings"
 
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
-var (
-	subResourceServicesParams = map[string][]string{
-		"basic_security": {
-			"web_firewall_log_level",
-			"mode",
-			"trusted_hosts_action",
-			"trusted_hosts_group",
-			"ignore_case",
-			"client_ip_addr_header",
-			"rate_control_pool",
-			"rate_control_status",
-			"web_firewall_policy",
-		},
-		"ssl_security": {
-			"certificate",
-			"ciphers",
-			"ecdsa_certificate",
-			"include_hsts_sub_domains",
-			"hsts_max_age",
-			"selected_ciphers",
-			"override_ciphers_ssl3",
-			"override_ciphers_tls_1_1",
-			"override_ciphers_tls_1_2",
-			"override_ciphers_tls_1_3",
-			"override_ciphers_tls_1",
-			"enable_pfs",
-			"enable_ssl_3",
-			"enable_tls_1",
-			"enable_tls_1_1",
-			"enable_tls_1_2",
-			"enable_tls_1_3",
-			"enable_hsts",
-			"enable_ocsp_stapling",
-			"sni_certificate",
-			"domain",
-			"sni_ecdsa_certificate",
-			"enable_sni",
-			"enable_strict_sni_check",
-			"status",
-			"ssl_tls_presets",
+func resourceCudaWAFServices() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceCudaWAFServicesCreate,
+		ReadContext:   resourceCudaWAFServicesRead,
+		UpdateContext: resourceCudaWAFServicesUpdate,
+		DeleteContext: resourceCudaWAFServicesDelete,
+		CustomizeDiff: resourceCudaWAFServicesCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
 		},
-		"instant_ssl": {
-			"status",
-			"sharepoint_rewrite_support",
-			"secure_site_domain",
+
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceCudaWAFServicesV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: upgradeBarracudaWAFIdentityState,
+			},
 		},
-	}
-)
 
-func resourceCudaWAFServices() *schema.Resource {
-	return &schema.Resource{
-		Create: resourceCudaWAFServicesCreate,
-		Read:   resourceCudaWAFServicesRead,
-		Update: resourceCudaWAFServicesUpdate,
-		Delete: resourceCudaWAFServicesDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Read:   schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
 
 		Schema: map[string]*schema.Schema{
-			"address_version":    {Type: schema.TypeString, Optional: true, Description: "Version"},
-			"mask":               {Type: schema.TypeString, Optional: true, Description: "Mask"},
-			"session_timeout":    {Type: schema.TypeString, Optional: true, Description: "Session Timeout"},
-			"enable_access_logs": {Type: schema.TypeString, Optional: true, Description: "Enable Access Logs"},
-			"app_id":             {Type: schema.TypeString, Optional: true, Description: "Service App Id"},
-			"comments":           {Type: schema.TypeString, Optional: true, Description: "Comments"},
-			"group":              {Type: schema.TypeString, Optional: true, Description: "Service Group"},
-			"ip_address":         {Type: schema.TypeString, Optional: true, Description: "VIP"},
-			"cloud_ip_select":    {Type: schema.TypeString, Optional: true},
-			"name":               {Type: schema.TypeString, Required: true, Description: "Web Application Name"},
-			"port":               {Type: schema.TypeString, Optional: true, Description: "Port"},
-			"status":             {Type: schema.TypeString, Optional: true, Description: "Status"},
-			"type":               {Type: schema.TypeString, Optional: true, Description: "Type"},
-			"certificate":        {Type: schema.TypeString, Optional: true},
-			"vsite":              {Type: schema.TypeString, Optional: true, Description: "Vsite"},
+			"address_version": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Version",
+				ValidateFunc: validateBarracudaWAFAddressVersion,
+				ForceNew:     true,
+			},
+			"mask": {Type: schema.TypeString, Optional: true, Computed: true, Description: "Mask"},
+			"session_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Session Timeout",
+				ValidateFunc: validateBarracudaWAFIntString(0, 86400),
+			},
+			"enable_access_logs": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Enable Access Logs",
+				ValidateFunc: validateBarracudaWAFYesNo,
+			},
+			"app_id":   {Type: schema.TypeString, Optional: true, Description: "Service App Id"},
+			"comments": {Type: schema.TypeString, Optional: true, Description: "Comments"},
+			"group": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Service Group",
+				ForceNew:    true,
+			},
+			"ip_address": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "VIP",
+				ValidateFunc: validateBarracudaWAFIPAddress,
+			},
+			"cloud_ip_select": {Type: schema.TypeString, Optional: true, Computed: true},
+			"name":            {Type: schema.TypeString, Required: true, Description: "Web Application Name"},
+			"port": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Port",
+				ValidateFunc: validateBarracudaWAFPort,
+			},
+			"status": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Status",
+				ValidateFunc: validateBarracudaWAFOnOff,
+			},
+			"type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Type",
+				ValidateFunc: validateBarracudaWAFServiceType,
+				ForceNew:     true,
+			},
+			"certificate": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Certificate the service is created with, set `ssl_security` to change it",
+				ForceNew:    true,
+			},
+			"vsite": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Vsite",
+				ForceNew:    true,
+			},
 			"basic_security": {
 				Type:     schema.TypeList,
 				Optional: true,
+				Computed: true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"web_firewall_log_level": {
 							Type:        schema.TypeString,
 							Optional:    true,
+							Computed:    true,
 							Description: "Web Firewall Log Level",
 						},
-						"mode": {Type: schema.TypeString, Optional: true, Description: "Mode"},
+						"mode": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "Mode",
+							ValidateFunc: validateBarracudaWAFMode,
+						},
 						"trusted_hosts_action": {
 							Type:        schema.TypeString,
 							Optional:    true,
+							Computed:    true,
 							Description: "Trusted Hosts Action",
 						},
 						"trusted_hosts_group": {
@@ -102,7 +143,13 @@ func resourceCudaWAFServices() *schema.Resource {
 							Optional:    true,
 							Description: "Trusted Hosts Group",
 						},
-						"ignore_case": {Type: schema.TypeString, Optional: true, Description: "Ignore case"},
+						"ignore_case": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "Ignore case",
+							ValidateFunc: validateBarracudaWAFYesNo,
+						},
 						"client_ip_addr_header": {
 							Type:        schema.TypeString,
 							Optional:    true,
@@ -111,16 +158,20 @@ func resourceCudaWAFServices() *schema.Resource {
 						"rate_control_pool": {
 							Type:        schema.TypeString,
 							Optional:    true,
+							Computed:    true,
 							Description: "Rate Control Pool",
 						},
 						"rate_control_status": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: "Rate Control Status",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "Rate Control Status",
+							ValidateFunc: validateBarracudaWAFOnOff,
 						},
 						"web_firewall_policy": {
 							Type:        schema.TypeString,
 							Optional:    true,
+							Computed:    true,
 							Description: "Web Firewall Policy",
 						},
 					},
@@ -129,32 +180,39 @@ func resourceCudaWAFServices() *schema.Resource {
 			"ssl_security": {
 				Type:     schema.TypeList,
 				Optional: true,
+				Computed: true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"certificate": {
 							Type:        schema.TypeString,
 							Optional:    true,
+							Computed:    true,
 							Description: "Certificate",
 						},
-						"ciphers": {Type: schema.TypeString, Optional: true, Description: "Ciphers"},
+						"ciphers": {Type: schema.TypeString, Optional: true, Computed: true, Description: "Ciphers"},
 						"ecdsa_certificate": {
 							Type:        schema.TypeString,
 							Optional:    true,
 							Description: "ECDSA Certificate",
 						},
 						"include_hsts_sub_domains": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: "Include HSTS Sub-Domains",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "Include HSTS Sub-Domains",
+							ValidateFunc: validateBarracudaWAFYesNo,
 						},
 						"hsts_max_age": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: "HSTS Max-Age",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "HSTS Max-Age",
+							ValidateFunc: validateBarracudaWAFIntString(0, 2147483647),
 						},
 						"selected_ciphers": {
 							Type:     schema.TypeList,
 							Optional: true,
+							Computed: true,
 							Elem: &schema.Schema{
 								Type: schema.TypeString,
 							},
@@ -163,6 +221,7 @@ func resourceCudaWAFServices() *schema.Resource {
 						"override_ciphers_ssl3": {
 							Type:     schema.TypeList,
 							Optional: true,
+							Computed: true,
 							Elem: &schema.Schema{
 								Type: schema.TypeString,
 							},
@@ -171,6 +230,7 @@ func resourceCudaWAFServices() *schema.Resource {
 						"override_ciphers_tls_1_1": {
 							Type:     schema.TypeList,
 							Optional: true,
+							Computed: true,
 							Elem: &schema.Schema{
 								Type: schema.TypeString,
 							},
@@ -179,6 +239,7 @@ func resourceCudaWAFServices() *schema.Resource {
 						"override_ciphers_tls_1_2": {
 							Type:     schema.TypeList,
 							Optional: true,
+							Computed: true,
 							Elem: &schema.Schema{
 								Type: schema.TypeString,
 							},
@@ -187,6 +248,7 @@ func resourceCudaWAFServices() *schema.Resource {
 						"override_ciphers_tls_1_3": {
 							Type:     schema.TypeList,
 							Optional: true,
+							Computed: true,
 							Elem: &schema.Schema{
 								Type: schema.TypeString,
 							},
@@ -195,38 +257,67 @@ func resourceCudaWAFServices() *schema.Resource {
 						"override_ciphers_tls_1": {
 							Type:     schema.TypeList,
 							Optional: true,
+							Computed: true,
 							Elem: &schema.Schema{
 								Type: schema.TypeString,
 							},
 							Description: "Override ciphers for TLS 1.0",
 						},
 						"enable_pfs": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: "Enable Perfect Forward Secrecy",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "Enable Perfect Forward Secrecy",
+							ValidateFunc: validateBarracudaWAFYesNo,
 						},
 						"enable_ssl_3": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: "SSL 3.0 (Insecure)",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "SSL 3.0 (Insecure)",
+							ValidateFunc: validateBarracudaWAFYesNo,
 						},
 						"enable_tls_1": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: "TLS 1.0 (Insecure)",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "TLS 1.0 (Insecure)",
+							ValidateFunc: validateBarracudaWAFYesNo,
+						},
+						"enable_tls_1_1": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "TLS 1.1",
+							ValidateFunc: validateBarracudaWAFYesNo,
+						},
+						"enable_tls_1_2": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "TLS 1.2",
+							ValidateFunc: validateBarracudaWAFYesNo,
+						},
+						"enable_tls_1_3": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "TLS 1.3",
+							ValidateFunc: validateBarracudaWAFYesNo,
 						},
-						"enable_tls_1_1": {Type: schema.TypeString, Optional: true, Description: "TLS 1.1"},
-						"enable_tls_1_2": {Type: schema.TypeString, Optional: true, Description: "TLS 1.2"},
-						"enable_tls_1_3": {Type: schema.TypeString, Optional: true, Description: "TLS 1.3"},
 						"enable_hsts": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: "Enable HSTS",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "Enable HSTS",
+							ValidateFunc: validateBarracudaWAFYesNo,
 						},
 						"enable_ocsp_stapling": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: "Enable OCSP Stapling",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "Enable OCSP Stapling",
+							ValidateFunc: validateBarracudaWAFYesNo,
 						},
 						"sni_certificate": {
 							Type:     schema.TypeList,
@@ -252,16 +343,31 @@ func resourceCudaWAFServices() *schema.Resource {
 							},
 							Description: "Domain ECDSA Certificate",
 						},
-						"enable_sni": {Type: schema.TypeString, Optional: true, Description: "Enable SNI"},
+						"enable_sni": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "Enable SNI",
+							ValidateFunc: validateBarracudaWAFYesNo,
+						},
 						"enable_strict_sni_check": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: "Enable Strict SNI Check",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "Enable Strict SNI Check",
+							ValidateFunc: validateBarracudaWAFYesNo,
+						},
+						"status": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "Status",
+							ValidateFunc: validateBarracudaWAFOnOff,
 						},
-						"status": {Type: schema.TypeString, Optional: true, Description: "Status"},
 						"ssl_tls_presets": {
 							Type:        schema.TypeString,
 							Optional:    true,
+							Computed:    true,
 							Description: "SSL/TLS Quick Settings",
 						},
 					},
@@ -273,21 +379,26 @@ func resourceCudaWAFServices() *schema.Resource {
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
-				Description: "Secure Site Domain",
+				Description: "Secure Site Domain the service is created with, set `instant_ssl` to change it",
+				ForceNew:    true,
 			},
 			"instant_ssl": {
 				Type:     schema.TypeList,
 				Optional: true,
+				Computed: true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"status": {
-							Type:        schema.TypeString,
-							Optional:    true,
-							Description: "Status",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							Description:  "Status",
+							ValidateFunc: validateBarracudaWAFOnOff,
 						},
 						"sharepoint_rewrite_support": {
 							Type:        schema.TypeString,
 							Optional:    true,
+							Computed:    true,
 							Description: "SharePoint Rewrite Support",
 						},
 						"secure_site_domain": {
@@ -307,199 +418,294 @@ func resourceCudaWAFServices() *schema.Resource {
 	}
 }
 
-func resourceCudaWAFServicesCreate(d *schema.ResourceData, m interface{}) error {
-	client := m.(*BarracudaWAF)
+func resourceCudaWAFServicesCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
 
 	name := d.Get("name").(string)
 
 	log.Println("[INFO] Creating Barracuda WAF resource " + name)
 
-	resourceEndpoint := "/services"
-	err := client.CreateBarracudaWAFResource(name, hydrateBarracudaWAFServicesResource(d, "post", resourceEndpoint))
+	err := client.CreateService(ctx, hydrateBarracudaWAFServicesResource(d))
 
 	if err != nil {
 		log.Printf("[ERROR] Unable to create Barracuda WAF resource (%s) (%v) ", name, err)
-		return err
+		return barracudaWAFDiagnostics(err, resourceCudaWAFServices().Schema, "Unable to create Barracuda WAF resource (%s)", name)
 	}
 
-	err = client.hydrateBarracudaWAFServicesSubResource(d, name, resourceEndpoint)
+	err = hydrateBarracudaWAFServicesSubResource(ctx, client, d, name)
 
 	if err != nil {
 		log.Printf("[ERROR] Unable to create Barracuda WAF sub resource (%s) (%v) ", name, err)
-		return err
+		return barracudaWAFDiagnostics(err, resourceCudaWAFServices().Schema, "Unable to create Barracuda WAF sub resource (%s)", name)
 	}
 
 	d.SetId(name)
-	return resourceCudaWAFServicesRead(d, m)
+	return resourceCudaWAFServicesRead(ctx, d, m)
 }
 
-func resourceCudaWAFServicesRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(*BarracudaWAF)
+func resourceCudaWAFServicesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
 
 	name := d.Id()
 	log.Println("[INFO] Fetching Barracuda WAF resource " + name)
 
-	resourceEndpoint := "/services"
-	request := &APIRequest{
-		Method: "get",
-		URL:    resourceEndpoint,
-	}
+	service, err := client.GetService(ctx, name)
 
-	var dataItems map[string]interface{}
-	resources, err := client.GetBarracudaWAFResource(name, request)
+	if waf.IsNotFound(err) {
+		log.Printf("[WARN] Barracuda WAF resource (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
 
 	if err != nil {
 		log.Printf("[ERROR] Unable to Retrieve Barracuda WAF resource (%s) (%v) ", name, err)
-		return err
+		return barracudaWAFDiagnostics(err, resourceCudaWAFServices().Schema, "Unable to Retrieve Barracuda WAF resource (%s)", name)
 	}
 
-	if resources.Data == nil {
-		log.Printf("[WARN] Barracuda WAF resource (%s) not found, removing from state", d.Id())
-		d.SetId("")
-		return nil
-	}
+	// certificate and secure_site_domain are only sent when the service is created, the WAF
+	// returns the values of the SSL Security and Instant SSL groups for them
+	resourceSchema := resourceCudaWAFServices().Schema
+	err = setBarracudaWAFResourceData(d, resourceSchema, service, "certificate", "secure_site_domain")
 
-	for _, dataItems = range resources.Data {
-		if dataItems["name"] == name {
-			break
-		}
+	if err != nil {
+		log.Printf("[ERROR] Unable to set Barracuda WAF resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFServices().Schema, "Unable to set Barracuda WAF resource (%s)", name)
 	}
 
-	if dataItems["name"] != name {
-		return fmt.Errorf("Barracuda WAF resource (%s) not found on the system", name)
+	err = readBarracudaWAFServicesSubResource(ctx, client, d, resourceSchema, name)
+
+	if err != nil {
+		log.Printf("[ERROR] Unable to Retrieve Barracuda WAF sub resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFServices().Schema, "Unable to Retrieve Barracuda WAF sub resource (%s)", name)
 	}
 
 	d.Set("name", name)
 	return nil
 }
 
-func resourceCudaWAFServicesUpdate(d *schema.ResourceData, m interface{}) error {
-	client := m.(*BarracudaWAF)
+func resourceCudaWAFServicesUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
 
 	name := d.Id()
 
 	log.Println("[INFO] Updating Barracuda WAF resource " + name)
 
-	resourceEndpoint := "/services"
-	err := client.UpdateBarracudaWAFResource(name, hydrateBarracudaWAFServicesResource(d, "put", resourceEndpoint))
+	err := client.UpdateService(ctx, name, hydrateBarracudaWAFServicesResource(d))
 
 	if err != nil {
-		log.Printf("[ERROR] Unable to update the Barracuda WAF resource (%s) (%v)", name, err)
-		return err
+		log.Printf("[ERROR] Unable to update the Barracuda WAF resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFServices().Schema, "Unable to update the Barracuda WAF resource (%s)", name)
 	}
 
-	err = client.hydrateBarracudaWAFServicesSubResource(d, name, resourceEndpoint)
+	name = renameBarracudaWAFResource(d)
+
+	err = hydrateBarracudaWAFServicesSubResource(ctx, client, d, name)
 
 	if err != nil {
-		log.Printf("[ERROR] Unable to update the Barracuda WAF sub resource (%s) (%v)", name, err)
-		return err
+		log.Printf("[ERROR] Unable to update the Barracuda WAF sub resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFServices().Schema, "Unable to update the Barracuda WAF sub resource (%s)", name)
 	}
 
-	return resourceCudaWAFServicesRead(d, m)
+	return resourceCudaWAFServicesRead(ctx, d, m)
 }
 
-func resourceCudaWAFServicesDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(*BarracudaWAF)
+// resourceCudaWAFServicesCustomizeDiff : announces the rename of the service, its servers
+// and content rules are moved along with it instead of being recreated.
+func resourceCudaWAFServicesCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if barracudaWAFDiffRequiresNew(d, resourceCudaWAFServices().Schema) {
+		return nil
+	}
+
+	oldName, newName := d.GetChange("name")
+	expectBarracudaWAFRename(d, m, waf.ServicePath(oldName.(string)), waf.ServicePath(newName.(string)))
+
+	return nil
+}
+
+func resourceCudaWAFServicesDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
 
 	name := d.Id()
 
 	log.Println("[INFO] Deleting Barracuda WAF resource " + name)
 
-	resourceEndpoint := "/services"
-	request := &APIRequest{
-		Method: "delete",
-		URL:    resourceEndpoint,
-	}
-
-	err := client.DeleteBarracudaWAFResource(name, request)
+	err := client.DeleteService(ctx, name)
 
 	if err != nil {
-		return fmt.Errorf("Unable to delete the Barracuda WAF resource (%s) (%v)", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFServices().Schema, "Unable to delete the Barracuda WAF resource (%s)", name)
 	}
 
 	return nil
 }
 
-func hydrateBarracudaWAFServicesResource(d *schema.ResourceData, method string, endpoint string) *APIRequest {
-
-	//resourcePayload : payload for the resource
-	resourcePayload := map[string]interface{}{
-		"address-version":    d.Get("address_version").(string),
-		"mask":               d.Get("mask").(string),
-		"session-timeout":    d.Get("session_timeout").(string),
-		"enable-access-logs": d.Get("enable_access_logs").(string),
-		"app-id":             d.Get("app_id").(string),
-		"comments":           d.Get("comments").(string),
-		"group":              d.Get("group").(string),
-		"ip-address":         d.Get("ip_address").(string),
-		"cloud-ip-select":    d.Get("cloud_ip_select").(string),
-		"name":               d.Get("name").(string),
-		"port":               d.Get("port").(string),
-		"status":             d.Get("status").(string),
-		"type":               d.Get("type").(string),
-		"certificate":        d.Get("certificate").(string),
-		"vsite":              d.Get("vsite").(string),
-		"secure-site-domain": d.Get("secure_site_domain"),
+func hydrateBarracudaWAFServicesResource(d *schema.ResourceData) *waf.Service {
+	return &waf.Service{
+		AddressVersion:   d.Get("address_version").(string),
+		Mask:             d.Get("mask").(string),
+		SessionTimeout:   d.Get("session_timeout").(string),
+		EnableAccessLogs: d.Get("enable_access_logs").(string),
+		AppID:            d.Get("app_id").(string),
+		Comments:         d.Get("comments").(string),
+		Group:            d.Get("group").(string),
+		IPAddress:        d.Get("ip_address").(string),
+		CloudIPSelect:    d.Get("cloud_ip_select").(string),
+		Name:             d.Get("name").(string),
+		Port:             d.Get("port").(string),
+		Status:           d.Get("status").(string),
+		Type:             d.Get("type").(string),
+		Certificate:      d.Get("certificate").(string),
+		Vsite:            d.Get("vsite").(string),
+		SecureSiteDomain: expandStringList(d.Get("secure_site_domain").([]interface{})),
 	}
+}
 
-	log.Println("[DEBUG] Resource payload for the service REST Call : ", resourcePayload)
+func hydrateBarracudaWAFServicesSubResource(ctx context.Context, client *waf.Client, d *schema.ResourceData, name string) error {
+	log.Printf("[INFO] Updating Barracuda WAF sub resources (%s)", name)
 
-	// parameters not supported for updates
-	if method == "put" {
-		updatePayloadExceptions := [...]string{"address-version", "type", "group", "vsite", "certificate", "secure-site-domain"}
-		for _, param := range updatePayloadExceptions {
-			delete(resourcePayload, param)
+	basicSecurity := &waf.ServiceBasicSecurity{}
+	if expandBarracudaWAFSubResource(d, "basic_security", basicSecurity) {
+		if err := client.UpdateServiceBasicSecurity(ctx, name, basicSecurity); err != nil {
+			return &subResourceError{subResource: "basic_security", err: err}
 		}
 	}
 
-	// remove empty parameters from resource payload
-	for key, val := range resourcePayload {
-		if reflect.ValueOf(val).Len() == 0 {
-			delete(resourcePayload, key)
+	sslSecurity := &waf.ServiceSSLSecurity{}
+	if expandBarracudaWAFSubResource(d, "ssl_security", sslSecurity) {
+		if err := client.UpdateServiceSSLSecurity(ctx, name, sslSecurity); err != nil {
+			return &subResourceError{subResource: "ssl_security", err: err}
 		}
 	}
 
-	return &APIRequest{
-		URL:  endpoint,
-		Body: resourcePayload,
+	instantSSL := &waf.ServiceInstantSSL{}
+	if expandBarracudaWAFSubResource(d, "instant_ssl", instantSSL) {
+		if err := client.UpdateServiceInstantSSL(ctx, name, instantSSL); err != nil {
+			return &subResourceError{subResource: "instant_ssl", err: err}
+		}
 	}
-}
-
-func (b *BarracudaWAF) hydrateBarracudaWAFServicesSubResource(d *schema.ResourceData, name string, endpoint string) error {
 
-	for subResource, subResourceParams := range subResourceServicesParams {
-		subResourceParamsLength := d.Get(subResource + ".#").(int)
+	return nil
+}
 
-		log.Printf("[INFO] Updating Barracuda WAF sub resource (%s) (%s)", name, subResource)
+// readBarracudaWAFServicesSubResource : sets the sub resources of the service, shared with
+// the barracudawaf_service data source.
+func readBarracudaWAFServicesSubResource(
+	ctx context.Context,
+	client *waf.Client,
+	d *schema.ResourceData,
+	resourceSchema map[string]*schema.Schema,
+	name string,
+) error {
+	log.Printf("[INFO] Fetching Barracuda WAF sub resources (%s)", name)
+
+	basicSecurity, err := client.GetServiceBasicSecurity(ctx, name)
+	if err != nil {
+		return err
+	}
 
-		for i := 0; i < subResourceParamsLength; i++ {
-			subResourcePayload := make(map[string]interface{})
-			suffix := fmt.Sprintf(".%d", i)
+	if err := setBarracudaWAFSubResourceData(d, resourceSchema, "basic_security", basicSecurity); err != nil {
+		return err
+	}
 
-			for _, param := range subResourceParams {
-				paramSuffix := fmt.Sprintf(".%s", param)
-				paramVaule := d.Get(subResource + suffix + paramSuffix)
+	sslSecurity, err := client.GetServiceSSLSecurity(ctx, name)
+	if err != nil {
+		return err
+	}
 
-				if reflect.ValueOf(paramVaule).Kind() == reflect.String {
-					paramVaule = paramVaule.(string)
-				}
+	if err := setBarracudaWAFSubResourceData(d, resourceSchema, "ssl_security", sslSecurity); err != nil {
+		return err
+	}
 
-				if reflect.ValueOf(paramVaule).Len() > 0 {
-					param = strings.Replace(param, "_", "-", -1)
-					subResourcePayload[param] = paramVaule
-				}
-			}
+	instantSSL, err := client.GetServiceInstantSSL(ctx, name)
+	if err != nil {
+		return err
+	}
 
-			err := b.UpdateBarracudaWAFSubResource(name, endpoint, &APIRequest{
-				URL:  strings.Replace(subResource, "_", "-", -1),
-				Body: subResourcePayload,
-			})
+	return setBarracudaWAFSubResourceData(d, resourceSchema, "instant_ssl", instantSSL)
+}
 
-			if err != nil {
-				return err
-			}
-		}
+// resourceCudaWAFServicesV0 : schema of the version 0 state of services, which is
+// a valid version 1 state.
+func resourceCudaWAFServicesV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"address_version":    {Type: schema.TypeString, Optional: true},
+			"mask":               {Type: schema.TypeString, Optional: true},
+			"session_timeout":    {Type: schema.TypeString, Optional: true},
+			"enable_access_logs": {Type: schema.TypeString, Optional: true},
+			"app_id":             {Type: schema.TypeString, Optional: true},
+			"comments":           {Type: schema.TypeString, Optional: true},
+			"group":              {Type: schema.TypeString, Optional: true},
+			"ip_address":         {Type: schema.TypeString, Optional: true},
+			"cloud_ip_select":    {Type: schema.TypeString, Optional: true},
+			"name":               {Type: schema.TypeString, Required: true},
+			"port":               {Type: schema.TypeString, Optional: true},
+			"status":             {Type: schema.TypeString, Optional: true},
+			"type":               {Type: schema.TypeString, Optional: true},
+			"certificate":        {Type: schema.TypeString, Optional: true},
+			"vsite":              {Type: schema.TypeString, Optional: true},
+			"basic_security": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"web_firewall_log_level": {Type: schema.TypeString, Optional: true},
+						"mode":                   {Type: schema.TypeString, Optional: true},
+						"trusted_hosts_action":   {Type: schema.TypeString, Optional: true},
+						"trusted_hosts_group":    {Type: schema.TypeString, Optional: true},
+						"ignore_case":            {Type: schema.TypeString, Optional: true},
+						"client_ip_addr_header":  {Type: schema.TypeString, Optional: true},
+						"rate_control_pool":      {Type: schema.TypeString, Optional: true},
+						"rate_control_status":    {Type: schema.TypeString, Optional: true},
+						"web_firewall_policy":    {Type: schema.TypeString, Optional: true},
+					},
+				},
+			},
+			"ssl_security": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"certificate":              {Type: schema.TypeString, Optional: true},
+						"ciphers":                  {Type: schema.TypeString, Optional: true},
+						"ecdsa_certificate":        {Type: schema.TypeString, Optional: true},
+						"include_hsts_sub_domains": {Type: schema.TypeString, Optional: true},
+						"hsts_max_age":             {Type: schema.TypeString, Optional: true},
+						"selected_ciphers":         {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+						"override_ciphers_ssl3":    {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+						"override_ciphers_tls_1_1": {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+						"override_ciphers_tls_1_2": {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+						"override_ciphers_tls_1_3": {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+						"override_ciphers_tls_1":   {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+						"enable_pfs":               {Type: schema.TypeString, Optional: true},
+						"enable_ssl_3":             {Type: schema.TypeString, Optional: true},
+						"enable_tls_1":             {Type: schema.TypeString, Optional: true},
+						"enable_tls_1_1":           {Type: schema.TypeString, Optional: true},
+						"enable_tls_1_2":           {Type: schema.TypeString, Optional: true},
+						"enable_tls_1_3":           {Type: schema.TypeString, Optional: true},
+						"enable_hsts":              {Type: schema.TypeString, Optional: true},
+						"enable_ocsp_stapling":     {Type: schema.TypeString, Optional: true},
+						"sni_certificate":          {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+						"domain":                   {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+						"sni_ecdsa_certificate":    {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+						"enable_sni":               {Type: schema.TypeString, Optional: true},
+						"enable_strict_sni_check":  {Type: schema.TypeString, Optional: true},
+						"status":                   {Type: schema.TypeString, Optional: true},
+						"ssl_tls_presets":          {Type: schema.TypeString, Optional: true},
+					},
+				},
+			},
+			"secure_site_domain": {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"instant_ssl": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"status":                     {Type: schema.TypeString, Optional: true},
+						"sharepoint_rewrite_support": {Type: schema.TypeString, Optional: true},
+						"secure_site_domain":         {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+					},
+				},
+			},
+		},
 	}
-
-	return nil
 }
@@ -1,20 +1,21 @@
 package barracudawaf
 
 import (
+	"context"
 	"fmt"
-	"os"
 	"testing"
 
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
+// BARRACUDA_WAF_PROVIDER : credentials are read from the BARRACUDA_WAF_* environment variables
 var BARRACUDA_WAF_PROVIDER = `
 provider "barracudawaf" {
-    address  = "` + os.Getenv("BARRACUDA_WAF_IP") + `"
-    username = "` + os.Getenv("BARRACUDA_WAF_USERNAME") + `"
-    port     = "8443"
-    password = "` + os.Getenv("BARRACUDA_WAF_PASSWORD") + `"
 }
 `
 
@@ -52,40 +53,282 @@ func TestAccBarracudaWAFService_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("barracudawaf_services.demo_app_1", "name", "DemoApp1"),
 				),
 			},
+			{
+				ResourceName:      "barracudawaf_services.demo_app_1",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
 
 func testCheckServiceExists(name string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*BarracudaWAF)
+		client := testAccProvider.Meta().(*waf.Client)
 
-		resourceEndpoint := "/services/" + name
-		request := &APIRequest{
-			Method: "get",
-			URL:    resourceEndpoint,
+		if _, err := client.GetService(context.Background(), name); err != nil {
+			return fmt.Errorf("service (%s) not found on the system (%v)", name, err)
 		}
 
-		service, err := client.GetBarracudaWAFResource(name, request)
+		return nil
+	}
+}
+
+func TestBarracudaWAFService_crud(t *testing.T) {
+	crud := newTestResourceCRUD(t, resourceCudaWAFServices())
+
+	raw := map[string]interface{}{
+		"name":            "DemoApp1",
+		"ip_address":      "172.30.1.4",
+		"port":            "90",
+		"type":            "HTTP",
+		"vsite":           "default",
+		"address_version": "IPv4",
+		"status":          "On",
+		"group":           "default",
+		"comments":        "Demo Service with Terraform",
+		"basic_security":  []interface{}{map[string]interface{}{"mode": "Active"}},
+	}
+
+	d := crud.create(raw)
+
+	testCheckParams(t, crud.server.Object("services/DemoApp1"), map[string]interface{}{
+		"ip-address": "172.30.1.4",
+		"port":       "90",
+		"type":       "HTTP",
+		"mask":       nil,
+	})
+	testCheckParams(t, crud.server.SubResource("services/DemoApp1", "basic-security"), map[string]interface{}{
+		"mode": "Active",
+	})
+	testCheckAttributes(t, d, map[string]string{
+		"port":                  "90",
+		"type":                  "HTTP",
+		"basic_security.0.mode": "Active",
+	})
+
+	raw["port"] = "8080"
+	raw["comments"] = "Updated Demo Service"
+	d = crud.update(d.Id(), raw)
+
+	testCheckParams(t, crud.server.LastRequest("PUT", "/services/DemoApp1").Body, map[string]interface{}{
+		"port":            "8080",
+		"type":            nil,
+		"address-version": nil,
+	})
+	testCheckAttributes(t, d, map[string]string{"port": "8080", "comments": "Updated Demo Service"})
+
+	crud.server.SetObject("services/DemoApp1", map[string]interface{}{"port": float64(443), "status": "Off"})
+	d = crud.read(d.Id(), raw)
+	testCheckAttributes(t, d, map[string]string{"port": "443", "status": "Off"})
+
+	crud.delete(d.Id(), raw)
+	if crud.server.Object("services/DemoApp1") != nil {
+		t.Fatalf("expected the service to be deleted")
+	}
+
+	if d = crud.read(d.Id(), raw); d.Id() != "" {
+		t.Errorf("expected the deleted service to be removed from the state")
+	}
+}
+
+func TestBarracudaWAFService_createOnlyParameters(t *testing.T) {
+	crud := newTestResourceCRUD(t, resourceCudaWAFServices())
+
+	raw := map[string]interface{}{
+		"name":               "DemoApp1",
+		"ip_address":         "172.30.1.4",
+		"port":               "443",
+		"type":               "HTTPS",
+		"certificate":        "DemoCert1",
+		"secure_site_domain": []interface{}{"www.example.com"},
+	}
+
+	d := crud.create(raw)
+
+	testCheckParams(t, crud.server.LastRequest("POST", "/services").Body, map[string]interface{}{
+		"certificate":        "DemoCert1",
+		"secure-site-domain": []interface{}{"www.example.com"},
+	})
+
+	// the WAF returns the certificate rotated through ssl_security and the domains of
+	// instant_ssl in the parameter groups of the service
+	crud.server.SetObject("services/DemoApp1", map[string]interface{}{
+		"name":         "DemoApp1",
+		"ip-address":   "172.30.1.4",
+		"port":         float64(443),
+		"type":         "HTTPS",
+		"SSL Security": map[string]interface{}{"certificate": "DemoCert2", "status": "On"},
+		"Instant SSL":  map[string]interface{}{"secure-site-domain": []interface{}{"www.example.org"}},
+	})
+
+	if diags := crud.resource.ReadContext(context.Background(), d, crud.client); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+
+	testCheckAttributes(t, d, map[string]string{
+		"certificate":          "DemoCert1",
+		"secure_site_domain.0": "www.example.com",
+	})
+
+	diff, err := crud.resource.Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(raw), crud.client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if diff.RequiresNew() {
+		t.Errorf("expected the service not to be replaced, got %v", diff.Attributes)
+	}
+}
+
+func TestBarracudaWAFService_rename(t *testing.T) {
+	crud := newTestResourceCRUD(t, resourceCudaWAFServices())
+
+	raw := map[string]interface{}{
+		"name":         "DemoApp1",
+		"ip_address":   "172.30.1.4",
+		"port":         "443",
+		"type":         "HTTPS",
+		"ssl_security": []interface{}{map[string]interface{}{"enable_tls_1_3": "Yes"}},
+	}
+
+	d := crud.create(raw)
+	crud.server.SetObject("services/DemoApp1/servers/DemoServer1", nil)
+	crud.server.SetObject("services/DemoApp1/content-rules/DemoRule1", nil)
+
+	raw["name"] = "DemoApp2"
+	d = crud.update(d.Id(), raw)
+
+	if d.Id() != "DemoApp2" {
+		t.Errorf("expected the ID to follow the new name, got %s", d.Id())
+	}
+
+	crud.server.LastRequest("PUT", "/services/DemoApp1")
+	crud.server.LastRequest("PUT", "/services/DemoApp2/ssl-security")
+
+	if len(crud.server.Requests("DELETE", "/")) != 0 || len(crud.server.Requests("POST", "/services")) != 1 {
+		t.Errorf("expected the service to be renamed without being recreated")
+	}
+
+	for _, path := range []string{"services/DemoApp2/servers/DemoServer1", "services/DemoApp2/content-rules/DemoRule1"} {
+		if crud.server.Object(path) == nil {
+			t.Errorf("expected %s to be carried over by the rename", path)
+		}
+	}
+
+	if crud.server.Object("services/DemoApp1") != nil {
+		t.Errorf("expected the old name to be released")
+	}
+}
+
+func TestBarracudaWAFService_upgradeV0State(t *testing.T) {
+	// states of a HTTPS service written by the provider before versioning, by Terraform 0.11
+	// (flatmap) and by Terraform 0.12 and later (JSON)
+	rawStates := map[string]*tfprotov5.RawState{
+		"flatmap": {Flatmap: map[string]string{
+			"id":                                 "DemoApp1",
+			"name":                               "DemoApp1",
+			"type":                               "HTTPS",
+			"certificate":                        "DemoCert1",
+			"secure_site_domain.#":               "1",
+			"secure_site_domain.0":               "www.example.com",
+			"ssl_security.#":                     "1",
+			"ssl_security.0.certificate":         "DemoCert1",
+			"ssl_security.0.domain.#":            "0",
+			"instant_ssl.#":                      "1",
+			"instant_ssl.0.status":               "On",
+			"instant_ssl.0.secure_site_domain.#": "0",
+		}},
+		"json": {JSON: []byte(`{
+			"id": "DemoApp1",
+			"name": "DemoApp1",
+			"type": "HTTPS",
+			"certificate": "DemoCert1",
+			"secure_site_domain": ["www.example.com"],
+			"ssl_security": [{"certificate": "DemoCert1", "domain": null}],
+			"instant_ssl": [{"status": "On", "sharepoint_rewrite_support": null, "secure_site_domain": null}]
+		}`)},
+	}
+
+	server := schema.NewGRPCProviderServer(Provider())
+
+	schemas, err := server.GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stateType := schemas.ResourceSchemas["barracudawaf_services"].ValueType()
+
+	for name, rawState := range rawStates {
+		resp, err := server.UpgradeResourceState(context.Background(), &tfprotov5.UpgradeResourceStateRequest{
+			TypeName: "barracudawaf_services",
+			Version:  0,
+			RawState: rawState,
+		})
+		if err != nil || len(resp.Diagnostics) > 0 {
+			t.Fatalf("%s: unexpected error: %v %v", name, err, resp.Diagnostics)
+		}
+
+		state, err := resp.UpgradedState.Unmarshal(stateType)
 		if err != nil {
-			return err
+			t.Fatalf("%s: unexpected error: %v", name, err)
 		}
 
-		if service == nil {
-			return fmt.Errorf("service %s was not created.", name)
+		expected := map[*tftypes.AttributePath]string{
+			tftypes.NewAttributePath().WithAttributeName("certificate"):                                                        "DemoCert1",
+			tftypes.NewAttributePath().WithAttributeName("secure_site_domain").WithElementKeyInt(0):                            "www.example.com",
+			tftypes.NewAttributePath().WithAttributeName("ssl_security").WithElementKeyInt(0).WithAttributeName("certificate"): "DemoCert1",
+			tftypes.NewAttributePath().WithAttributeName("instant_ssl").WithElementKeyInt(0).WithAttributeName("status"):       "On",
 		}
 
-		var dataItems map[string]interface{}
-		for _, dataItems = range service.Data {
-			if dataItems["name"] == name {
-				break
+		for path, value := range expected {
+			attribute, _, err := tftypes.WalkAttributePath(state, path)
+			if err != nil {
+				t.Errorf("%s: expected %s to be upgraded, got %v", name, path, err)
+				continue
 			}
-		}
 
-		if dataItems["name"] != name {
-			return fmt.Errorf("service (%s) not found on the system", name)
+			var upgraded string
+			if err := attribute.(tftypes.Value).As(&upgraded); err != nil || upgraded != value {
+				t.Errorf("%s: expected %s to be %q, got %q (%v)", name, path, value, upgraded, err)
+			}
 		}
+	}
+}
 
-		return nil
+func TestHydrateBarracudaWAFServicesResource(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCudaWAFServices().Schema, map[string]interface{}{
+		"name":               "DemoApp1",
+		"type":               "HTTPS",
+		"certificate":        "DemoCert",
+		"port":               "443",
+		"secure_site_domain": []interface{}{"example.com"},
+	})
+
+	testCheckPayload(t, hydrateBarracudaWAFServicesResource(d), map[string]interface{}{
+		"name":               "DemoApp1",
+		"type":               "HTTPS",
+		"certificate":        "DemoCert",
+		"secure-site-domain": []interface{}{"example.com"},
+		"comments":           "",
+		"mask":               nil,
+	})
+}
+
+func TestBarracudaWAFService_validationError(t *testing.T) {
+	crud := newTestResourceCRUD(t, resourceCudaWAFServices())
+	crud.server.RejectNext("PUT", map[string]string{"hsts-max-age": "must be a number"})
+
+	d := schema.TestResourceDataRaw(t, crud.resource.Schema, map[string]interface{}{
+		"name":         "DemoApp1",
+		"ip_address":   "172.30.1.4",
+		"port":         "443",
+		"type":         "HTTPS",
+		"ssl_security": []interface{}{map[string]interface{}{"hsts_max_age": "a year"}},
+	})
+
+	diags := crud.resource.CreateContext(context.Background(), d, crud.client)
+
+	if len(diags) != 1 || diags[0].Summary != "ssl_security.0.hsts_max_age: must be a number" {
+		t.Fatalf("expected the error to point at the hsts_max_age attribute, got %#v", diags)
 	}
 }
@@ -1,31 +1,119 @@
 package barracudawaf
 
 import (
-	"fmt"
+	"context"
+	"crypto/x509"
 	"log"
-	"reflect"
-	"strings"
 
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-)
-
-var (
-	subResourceSignedCertificateParams = map[string][]string{}
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceCudaWAFSignedCertificate() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceCudaWAFSignedCertificateCreate,
-		Read:   resourceCudaWAFSignedCertificateRead,
-		Update: resourceCudaWAFSignedCertificateUpdate,
-		Delete: resourceCudaWAFSignedCertificateDelete,
+		CreateContext: resourceCudaWAFSignedCertificateCreate,
+		ReadContext:   resourceCudaWAFSignedCertificateRead,
+		UpdateContext: resourceCudaWAFSignedCertificateUpdate,
+		DeleteContext: resourceCudaWAFSignedCertificateDelete,
+		CustomizeDiff: resourceCudaWAFSignedCertificateCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceCudaWAFSignedCertificateV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: upgradeBarracudaWAFSecretState(
+					"signed_certificate",
+					"certificate_key",
+					"certificate_password",
+					"encrypt_password",
+				),
+			},
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Read:   schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
 
 		Schema: map[string]*schema.Schema{
-			"assign_associated_key": {Type: schema.TypeString, Optional: true},
-			"signed_certificate":    {Type: schema.TypeString, Optional: true},
-			"certificate_key":       {Type: schema.TypeString, Optional: true},
-			"certificate_password":  {Type: schema.TypeString, Optional: true},
-			"certificate_type":      {Type: schema.TypeString, Optional: true},
+			"assign_associated_key": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateBarracudaWAFYesNo,
+				ForceNew:     true,
+			},
+			"signed_certificate": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Sensitive:        true,
+				StateFunc:        hashBarracudaWAFSecret,
+				ExactlyOneOf:     []string{"signed_certificate", "signed_certificate_file"},
+				DiffSuppressFunc: suppressBarracudaWAFImportedUpload,
+				Description:      "PEM certificate, optionally followed by its chain and private key, or PKCS #12 bundle, base64 encoded. Only its hash is stored in the state.",
+				ForceNew:         true,
+			},
+			"signed_certificate_file": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressBarracudaWAFImportedUpload,
+				Description:      "Path of the PEM certificate or PKCS #12 bundle, replaces signed_certificate",
+				ForceNew:         true,
+			},
+			"certificate_key": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Sensitive:        true,
+				StateFunc:        hashBarracudaWAFSecret,
+				ConflictsWith:    []string{"certificate_key_file"},
+				DiffSuppressFunc: suppressBarracudaWAFImportedUpload,
+				Description:      "PEM private key of the certificate. Only its hash is stored in the state.",
+				ForceNew:         true,
+			},
+			"certificate_key_file": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressBarracudaWAFImportedUpload,
+				Description:      "Path of the PEM private key of the certificate, replaces certificate_key",
+				ForceNew:         true,
+			},
+			"certificate_key_file_sha256": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "SHA-256 hash of the content of certificate_key_file the certificate was uploaded with",
+			},
+			"certificate_request_name": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ConflictsWith:    []string{"certificate_key", "certificate_key_file"},
+				DiffSuppressFunc: suppressBarracudaWAFImportedUpload,
+				Description:      "Name of the certificate signing request the certificate is signed for, its private key generated on the WAF is assigned to the certificate",
+				ForceNew:         true,
+			},
+			"certificate_password": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Sensitive:        true,
+				StateFunc:        hashBarracudaWAFSecret,
+				DiffSuppressFunc: suppressBarracudaWAFImportedUpload,
+				Description:      "Password of the PKCS #12 bundle or of the encrypted private key. Only its hash is stored in the state.",
+				ForceNew:         true,
+			},
+			"certificate_type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "PEM Certificate or PKCS12 Token, detected from the certificate when not set",
+				ForceNew:    true,
+			},
 			"download_type": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -34,234 +122,397 @@ func resourceCudaWAFSignedCertificate() *schema.Resource {
 			"encrypt_password": {
 				Type:        schema.TypeString,
 				Optional:    true,
+				Sensitive:   true,
+				StateFunc:   hashBarracudaWAFSecret,
 				Description: "Encryption Password is used to extract the private key from PKCS #12 token.",
 			},
 			"intermediary_certificates": {
 				Type:     schema.TypeList,
 				Optional: true,
+				Computed: true,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
-				Description: "Intermediary Certificates",
+				DiffSuppressFunc: suppressBarracudaWAFUploadedChain,
+				Description:      "Intermediary Certificates uploaded with the certificate, see `barracudawaf_certificate_chain` to change them",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Certificate Name",
+				ForceNew:    true,
+			},
+			"auto_renew_cert": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "None",
+				ValidateFunc: validateBarracudaWAFYesNo,
+			},
+			"common_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Common Name",
+				ForceNew:    true,
+			},
+			"allow_private_key_export": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateBarracudaWAFYesNo,
+				ForceNew:     true,
+			},
+			"schedule_renewal_day": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "None",
+				ValidateFunc: validateBarracudaWAFIntString(1, 90),
+			},
+			"early_renewal_hours": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Hours before the expiry of the certificate from which it is replaced",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"expiry": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Expiry of the certificate",
+			},
+			"serial": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Serial Number of the certificate",
+			},
+			"issuer": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Issuer of the certificate",
+			},
+			"subject": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Subject of the certificate",
+			},
+			"sha256_fingerprint": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "SHA-256 fingerprint of the certificate",
+			},
+			"key_type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Key Type of the certificate",
 			},
-			"name":                     {Type: schema.TypeString, Optional: true, Description: "Certificate Name"},
-			"auto_renew_cert":          {Type: schema.TypeString, Optional: true, Description: "None"},
-			"common_name":              {Type: schema.TypeString, Optional: true, Description: "Common Name"},
-			"expiry":                   {Type: schema.TypeString, Optional: true},
-			"key_type":                 {Type: schema.TypeString, Optional: true, Description: "Select Key Type:"},
-			"allow_private_key_export": {Type: schema.TypeString, Optional: true},
-			"schedule_renewal_day":     {Type: schema.TypeString, Optional: true, Description: "None"},
-			"serial":                   {Type: schema.TypeString, Optional: true},
 		},
 
 		Description: "`barracudawaf_signed_certificate` manages `Signed Certificate` on the Barracuda Web Application Firewall.",
 	}
 }
 
-func resourceCudaWAFSignedCertificateCreate(d *schema.ResourceData, m interface{}) error {
-	client := m.(*BarracudaWAF)
+func resourceCudaWAFSignedCertificateCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
 
 	name := d.Get("name").(string)
 
 	log.Println("[INFO] Creating Barracuda WAF resource " + name)
 
-	resourceEndpoint := "/signed-certificate"
-	err := client.CreateBarracudaWAFResource(name, hydrateBarracudaWAFSignedCertificateResource(d, "post", resourceEndpoint))
+	upload, err := expandBarracudaWAFCertificateUpload(ctx, client, d.Get)
+	if err != nil {
+		log.Printf("[ERROR] Invalid certificate for Barracuda WAF resource (%s) (%v) ", name, err)
+		return diag.Errorf("Invalid certificate for Barracuda WAF resource (%s): %v", name, err)
+	}
+
+	err = client.CreateCertificate(ctx, waf.SignedCertificates, upload.hydrate(hydrateBarracudaWAFSignedCertificateResource(d)))
 
 	if err != nil {
 		log.Printf("[ERROR] Unable to create Barracuda WAF resource (%s) (%v) ", name, err)
-		return err
+		return barracudaWAFDiagnostics(err, resourceCudaWAFSignedCertificate().Schema, "Unable to create Barracuda WAF resource (%s)", name)
 	}
 
-	err = client.hydrateBarracudaWAFSignedCertificateSubResource(d, name, resourceEndpoint)
+	d.SetId(name)
 
+	keyFileHash, err := hashBarracudaWAFFile(d.Get("certificate_key_file").(string))
 	if err != nil {
-		log.Printf("[ERROR] Unable to create Barracuda WAF sub resource (%s) (%v) ", name, err)
-		return err
+		log.Printf("[WARN] Unable to hash the key file of Barracuda WAF resource (%s) (%v)", name, err)
 	}
+	d.Set("certificate_key_file_sha256", keyFileHash)
 
-	d.SetId(name)
-	return resourceCudaWAFSignedCertificateRead(d, m)
+	return resourceCudaWAFSignedCertificateRead(ctx, d, m)
 }
 
-func resourceCudaWAFSignedCertificateRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(*BarracudaWAF)
+func resourceCudaWAFSignedCertificateRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
 
 	name := d.Id()
 	log.Println("[INFO] Fetching Barracuda WAF resource " + name)
 
-	resourceEndpoint := "/signed-certificate"
-	request := &APIRequest{
-		Method: "get",
-		URL:    resourceEndpoint,
-	}
-
-	var dataItems map[string]interface{}
-	resources, err := client.GetBarracudaWAFResource(name, request)
-
-	if err != nil {
-		log.Printf("[ERROR] Unable to Retrieve Barracuda WAF resource (%s) (%v) ", name, err)
-		return err
-	}
+	certificate, err := client.GetCertificate(ctx, waf.SignedCertificates, name)
 
-	if resources.Data == nil {
+	if waf.IsNotFound(err) {
 		log.Printf("[WARN] Barracuda WAF resource (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
 	}
 
-	for _, dataItems = range resources.Data {
-		if dataItems["name"] == name {
-			break
-		}
+	if err != nil {
+		log.Printf("[ERROR] Unable to Retrieve Barracuda WAF resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFSignedCertificate().Schema, "Unable to Retrieve Barracuda WAF resource (%s)", name)
 	}
 
-	if dataItems["name"] != name {
-		return fmt.Errorf("Barracuda WAF resource (%s) not found on the system", name)
+	err = setBarracudaWAFResourceData(
+		d,
+		resourceCudaWAFSignedCertificate().Schema,
+		certificate,
+		"signed_certificate",
+		"signed_certificate_file",
+		"certificate_key",
+		"certificate_key_file",
+		"certificate_key_file_sha256",
+		"certificate_password",
+		"encrypt_password",
+	)
+
+	if err != nil {
+		log.Printf("[ERROR] Unable to set Barracuda WAF resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFSignedCertificate().Schema, "Unable to set Barracuda WAF resource (%s)", name)
 	}
 
 	d.Set("name", name)
 	return nil
 }
 
-func resourceCudaWAFSignedCertificateUpdate(d *schema.ResourceData, m interface{}) error {
-	client := m.(*BarracudaWAF)
+func resourceCudaWAFSignedCertificateUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
 
 	name := d.Id()
 
 	log.Println("[INFO] Updating Barracuda WAF resource " + name)
 
-	resourceEndpoint := "/signed-certificate"
-	err := client.UpdateBarracudaWAFResource(name, hydrateBarracudaWAFSignedCertificateResource(d, "put", resourceEndpoint))
+	err := client.UpdateCertificate(ctx, waf.SignedCertificates, name, hydrateBarracudaWAFSignedCertificateResource(d))
 
 	if err != nil {
-		log.Printf("[ERROR] Unable to update the Barracuda WAF resource (%s) (%v)", name, err)
-		return err
+		log.Printf("[ERROR] Unable to update the Barracuda WAF resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFSignedCertificate().Schema, "Unable to update the Barracuda WAF resource (%s)", name)
 	}
 
-	err = client.hydrateBarracudaWAFSignedCertificateSubResource(d, name, resourceEndpoint)
+	return resourceCudaWAFSignedCertificateRead(ctx, d, m)
+}
 
-	if err != nil {
-		log.Printf("[ERROR] Unable to update the Barracuda WAF sub resource (%s) (%v)", name, err)
-		return err
-	}
+// suppressBarracudaWAFUploadedChain : suppresses the changes of the intermediary certificates
+// once the certificate is uploaded, they are then owned by barracudawaf_certificate_chain.
+func suppressBarracudaWAFUploadedChain(k, old, new string, d *schema.ResourceData) bool {
+	return d.Id() != ""
+}
 
-	return resourceCudaWAFSignedCertificateRead(d, m)
+// suppressBarracudaWAFImportedUpload : suppresses the inputs of an imported certificate, see
+// isBarracudaWAFImportedCertificate.
+func suppressBarracudaWAFImportedUpload(k, old, new string, d *schema.ResourceData) bool {
+	return old == "" && d.Id() != "" && isBarracudaWAFImportedCertificate(d.GetChange)
 }
 
-func resourceCudaWAFSignedCertificateDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(*BarracudaWAF)
+// isBarracudaWAFImportedCertificate : reports whether the state of the certificate was imported,
+// without the certificate it was uploaded with, and the configured PEM certificate is the one
+// on the WAF. The inputs it was uploaded with are then left out of the plan instead of
+// replacing the certificate.
+func isBarracudaWAFImportedCertificate(getChange func(string) (interface{}, interface{})) bool {
+	oldCertificate, newCertificate := getChange("signed_certificate")
+	oldFile, newFile := getChange("signed_certificate_file")
+	if oldCertificate.(string) != "" || oldFile.(string) != "" {
+		return false
+	}
 
-	name := d.Id()
+	fingerprint, _ := getChange("sha256_fingerprint")
+	if fingerprint.(string) == "" {
+		return false
+	}
 
-	log.Println("[INFO] Deleting Barracuda WAF resource " + name)
+	content, err := readBarracudaWAFContent(func(key string) interface{} {
+		if key == "signed_certificate_file" {
+			return newFile
+		}
+		return newCertificate
+	}, "signed_certificate")
+	if err != nil {
+		return false
+	}
+
+	blocks, _, err := splitBarracudaWAFPEM(content)
+	if err != nil || len(blocks) == 0 {
+		return false
+	}
+
+	leaf, err := x509.ParseCertificate(blocks[0].Bytes)
+	return err == nil && barracudaWAFFingerprint(leaf) == normalizeBarracudaWAFFingerprint(fingerprint.(string))
+}
 
-	resourceEndpoint := "/signed-certificate"
-	request := &APIRequest{
-		Method: "delete",
-		URL:    resourceEndpoint,
+// hasBarracudaWAFUploadChange : reports whether an attribute uploaded with the certificate
+// changes. The secrets are stored hashed, their configured value is hashed to be compared.
+func hasBarracudaWAFUploadChange(d *schema.ResourceDiff, attribute string) bool {
+	if !d.HasChange(attribute) {
+		return false
 	}
 
-	err := client.DeleteBarracudaWAFResource(name, request)
+	old, new := d.GetChange(attribute)
+	if old == "" && d.Id() != "" && isBarracudaWAFImportedCertificate(d.GetChange) {
+		return false
+	}
 
-	if err != nil {
-		return fmt.Errorf("Unable to delete the Barracuda WAF resource (%s) (%v)", name, err)
+	if stateFunc := resourceCudaWAFSignedCertificate().Schema[attribute].StateFunc; stateFunc != nil {
+		return old != stateFunc(new)
 	}
 
-	return nil
+	return true
 }
 
-func hydrateBarracudaWAFSignedCertificateResource(d *schema.ResourceData, method string, endpoint string) *APIRequest {
-
-	//resourcePayload : payload for the resource
-	resourcePayload := map[string]interface{}{
-		"assign-associated-key":     d.Get("assign_associated_key").(string),
-		"signed-certificate":        d.Get("signed_certificate").(string),
-		"certificate-key":           d.Get("certificate_key").(string),
-		"certificate-password":      d.Get("certificate_password").(string),
-		"certificate-type":          d.Get("certificate_type").(string),
-		"download-type":             d.Get("download_type").(string),
-		"encrypt-password":          d.Get("encrypt_password").(string),
-		"intermediary-certificates": d.Get("intermediary_certificates"),
-		"name":                      d.Get("name").(string),
-		"auto-renew-cert":           d.Get("auto_renew_cert").(string),
-		"common-name":               d.Get("common_name").(string),
-		"expiry":                    d.Get("expiry").(string),
-		"key-type":                  d.Get("key_type").(string),
-		"allow-private-key-export":  d.Get("allow_private_key_export").(string),
-		"schedule-renewal-day":      d.Get("schedule_renewal_day").(string),
-		"serial":                    d.Get("serial").(string),
+// resourceCudaWAFSignedCertificateCustomizeDiff : validates the certificate when it is uploaded
+// and replaces it when the certificate of its PEM file or the content of its key file changes,
+// or when it nears its expiry.
+func resourceCudaWAFSignedCertificateCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	uploadAttributes := []string{
+		"signed_certificate",
+		"signed_certificate_file",
+		"certificate_key",
+		"certificate_key_file",
+		"certificate_password",
+		"certificate_request_name",
 	}
 
-	// parameters not supported for updates
-	if method == "put" {
-		updatePayloadExceptions := [...]string{
-			"assign-associated-key",
-			"signed-certificate",
-			"certificate-key",
-			"certificate-password",
-			"certificate-type",
-			"intermediary-certificates",
-			"name",
-			"common-name",
-			"expiry",
-			"key-type",
-			"allow-private-key-export",
-			"serial",
-		}
-		for _, param := range updatePayloadExceptions {
-			delete(resourcePayload, param)
+	changed := d.Id() == ""
+	for _, attribute := range uploadAttributes {
+		if !d.NewValueKnown(attribute) {
+			return forceNewIfBarracudaWAFCertificateExpires(d)
 		}
+
+		changed = changed || hasBarracudaWAFUploadChange(d, attribute)
 	}
 
-	// remove empty parameters from resource payload
-	for key, val := range resourcePayload {
-		if reflect.ValueOf(val).Len() == 0 {
-			delete(resourcePayload, key)
+	// the intermediary certificates are checked with the certificate when it is created, they
+	// are then left to barracudawaf_certificate_chain
+	get := d.Get
+	if d.Id() != "" {
+		get = func(key string) interface{} {
+			if key == "intermediary_certificates" {
+				return []interface{}{}
+			}
+			return d.Get(key)
 		}
 	}
 
-	return &APIRequest{
-		URL:  endpoint,
-		Body: resourcePayload,
+	// the certificate is only checked when it is uploaded, the key of its certificate request
+	// is assigned to it afterwards
+	checkUpload := func() error {
+		client, _ := m.(*waf.Client)
+		_, err := expandBarracudaWAFCertificateUpload(ctx, client, get)
+		return err
 	}
-}
-
-func (b *BarracudaWAF) hydrateBarracudaWAFSignedCertificateSubResource(
-	d *schema.ResourceData,
-	name string,
-	endpoint string,
-) error {
 
-	for subResource, subResourceParams := range subResourceSignedCertificateParams {
-		subResourceParamsLength := d.Get(subResource + ".#").(int)
+	if changed {
+		if err := checkUpload(); err != nil {
+			return err
+		}
+	}
 
-		log.Printf("[INFO] Updating Barracuda WAF sub resource (%s) (%s)", name, subResource)
+	if path := d.Get("signed_certificate_file").(string); d.Id() != "" && path != "" {
+		leaf, err := readBarracudaWAFCertificateFile(path)
+		if err != nil {
+			return err
+		}
 
-		for i := 0; i < subResourceParamsLength; i++ {
-			subResourcePayload := map[string]string{}
-			suffix := fmt.Sprintf(".%d", i)
+		fingerprint := normalizeBarracudaWAFFingerprint(d.Get("sha256_fingerprint").(string))
+		if leaf != nil && fingerprint != "" && fingerprint != barracudaWAFFingerprint(leaf) {
+			log.Printf("[INFO] The certificate of (%s) changed, replacing Barracuda WAF resource (%s)", path, d.Id())
 
-			for _, param := range subResourceParams {
-				paramSuffix := fmt.Sprintf(".%s", param)
-				paramVaule := d.Get(subResource + suffix + paramSuffix).(string)
+			if err := checkUpload(); err != nil {
+				return err
+			}
 
-				if len(paramVaule) > 0 {
-					param = strings.Replace(param, "_", "-", -1)
-					subResourcePayload[param] = paramVaule
-				}
+			if err := d.SetNewComputed("sha256_fingerprint"); err != nil {
+				return err
 			}
+			return d.ForceNew("sha256_fingerprint")
+		}
+	}
+
+	if path := d.Get("certificate_key_file").(string); d.Id() != "" && path != "" {
+		keyFileHash, err := hashBarracudaWAFFile(path)
+		if err != nil {
+			return err
+		}
 
-			err := b.UpdateBarracudaWAFSubResource(name, endpoint, &APIRequest{
-				URL:  strings.Replace(subResource, "_", "-", -1),
-				Body: subResourcePayload,
-			})
+		if old := d.Get("certificate_key_file_sha256").(string); old != "" && old != keyFileHash {
+			log.Printf("[INFO] The key of (%s) changed, replacing Barracuda WAF resource (%s)", path, d.Id())
 
-			if err != nil {
+			if err := d.SetNewComputed("certificate_key_file_sha256"); err != nil {
 				return err
 			}
+			return d.ForceNew("certificate_key_file_sha256")
 		}
 	}
 
+	return forceNewIfBarracudaWAFCertificateExpires(d)
+}
+
+func resourceCudaWAFSignedCertificateDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
+
+	name := d.Id()
+
+	log.Println("[INFO] Deleting Barracuda WAF resource " + name)
+
+	err := client.DeleteCertificate(ctx, waf.SignedCertificates, name)
+
+	if err != nil {
+		return barracudaWAFDiagnostics(err, resourceCudaWAFSignedCertificate().Schema, "Unable to delete the Barracuda WAF resource (%s)", name)
+	}
+
 	return nil
 }
+
+// hydrateBarracudaWAFSignedCertificateResource : returns the settings of the certificate, the
+// certificate and its keys are set from the validated upload. The state only holds the hash of
+// the encryption password, it is sent when it changes.
+func hydrateBarracudaWAFSignedCertificateResource(d *schema.ResourceData) *waf.Certificate {
+	var encryptPassword string
+	if d.HasChange("encrypt_password") {
+		encryptPassword = d.Get("encrypt_password").(string)
+	}
+
+	return &waf.Certificate{
+		AssignAssociatedKey:   d.Get("assign_associated_key").(string),
+		CertificatePassword:   d.Get("certificate_password").(string),
+		CertificateType:       d.Get("certificate_type").(string),
+		DownloadType:          d.Get("download_type").(string),
+		EncryptPassword:       encryptPassword,
+		Name:                  d.Get("name").(string),
+		AutoRenewCert:         d.Get("auto_renew_cert").(string),
+		CommonName:            d.Get("common_name").(string),
+		AllowPrivateKeyExport: d.Get("allow_private_key_export").(string),
+		ScheduleRenewalDay:    d.Get("schedule_renewal_day").(string),
+	}
+}
+
+func resourceCudaWAFSignedCertificateV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"assign_associated_key":     {Type: schema.TypeString, Optional: true, Computed: true},
+			"signed_certificate":        {Type: schema.TypeString, Optional: true},
+			"certificate_key":           {Type: schema.TypeString, Optional: true},
+			"certificate_password":      {Type: schema.TypeString, Optional: true},
+			"certificate_type":          {Type: schema.TypeString, Optional: true, Computed: true},
+			"download_type":             {Type: schema.TypeString, Optional: true, Computed: true},
+			"encrypt_password":          {Type: schema.TypeString, Optional: true},
+			"intermediary_certificates": {Type: schema.TypeList, Optional: true, Computed: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"name":                      {Type: schema.TypeString, Optional: true, Computed: true},
+			"auto_renew_cert":           {Type: schema.TypeString, Optional: true, Computed: true},
+			"common_name":               {Type: schema.TypeString, Optional: true, Computed: true},
+			"allow_private_key_export":  {Type: schema.TypeString, Optional: true, Computed: true},
+			"schedule_renewal_day":      {Type: schema.TypeString, Optional: true, Computed: true},
+			"expiry":                    {Type: schema.TypeString, Computed: true},
+			"serial":                    {Type: schema.TypeString, Computed: true},
+			"key_type":                  {Type: schema.TypeString, Computed: true},
+		},
+	}
+}
@@ -1,21 +1,33 @@
 package barracudawaf
 
 import (
+	"context"
+	"encoding/base64"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
+// testBarracudaWAFPKCS12Bundle : PKCS #12 bundle protected by the password secret@123
+const testBarracudaWAFPKCS12Bundle = "MIIG2QIBAzCCBo8GCSqGSIb3DQEHAaCCBoAEggZ8MIIGeDCCA3cGCSqGSIb3DQEHBqCCA2gwggNkAgEAMIIDXQYJKoZIhvcNAQcBMBwGCiqGSIb3DQEMAQYwDgQIXyoSjDOcrdYCAggAgIIDMKPkmkUMSmtSmbv+YAWplEfvMjP0IGBa84lNd88uf7RQqBMW21hdorQGfteB0NpeiuNKXjbjCNDhDa/JXlD7wlnKu02yjjSAueaAakIR55VyyhfJj4otCDVwNNLpPa/sdKlnYi4o78AJmkkiRgRBL5Qq7QjBx1/CMtyrUrD/y45SAqv8YcqR5ehe8naCZjjPQXMmG3eJRnEaYmeI7x+wlFLxHobQJkiG6vRWESfs4S5R4lCVQhrgGZZx75ipD2OqMhK38JU8bpM5u0y6dUQZyHp88R4zLZ9gFAqhrtqOIOsihm6nrI/OM2MBNfgtSlBT/07rDf9MwIEyuvDw5l2f6anoK/H4AhAoh22inNwKzpDOwBko5eg1d02l5+74YxQTQqfzhgS/wFmgZzocsK3soHorD1dhKsWObm2WcLuWMo3qOU/+4/OKBTUkGCqZpc7TRLl+DGX+M1rXd/LK28CTjFkbE5G4JSpuyEmSFnvyxRPN3/NJH08H5RMxXGSYTmZvhoEArDQ9QV45h4eBKy4E/x60Wfq9AQBSQWNB5RJlD7/2tHMjPFkc0jlOzyusknrLdtOJ7Q+cWXg2qwSSUyEFKk4bZhbm2ltRrNjj7QTxR45JnI/S/G9kD0Gc0p1RhUvSUWAVBHNW0lyh7D3OI1uxXYPD5kUW1IQEJaihlwQGbeV/AXVCjqzPL0QMS9WFYRAVpjZdcML8HHcdkhEBxzXyso7cjlRk6vIUIZ4REBWBWr0n/7u5p4AlgNxOlzqGcYj4o5zLKWDXMZLwKVyH13A7Kvbzg2nFa0sPVmHFT1LGm7DVMkAu/GLf1PWeaVhRuHXv0vZvBpoZzhgma878nhhOCDegWrT40+SMngwgTvX9e4GR+eMuzOGxWPDfHO+MRIJBG0yzjxkJFCK6Lis8xIbGsPktUyHFz3fVgiTFounf210liMMBvwW26mtIAjHzCXnig/R4NVUwhWOAw+5Q+oe4SImGypooLmxc7Lk6hXg4zvlOIpP2aaOQrDxpJTBa2qpq5f21eoP+lv2Kwzoxzh1NamP3bTKLv6A325S8P0ANALefu6ZhpT7ZCDb9debaUgXWTjCCAvkGCSqGSIb3DQEHAaCCAuoEggLmMIIC4jCCAt4GCyqGSIb3DQEMCgECoIICpjCCAqIwHAYKKoZIhvcNAQwBAzAOBAgWEziGlG/eVAICCAAEggKAOiHXY/iQMyuuc8ezsHyjCsxuzY4lSzD4oXtrT5U9SL7+fd9MfNnPwoD+xMnPbev5IGM6EjeZd/kJXmqWXxqnCZiatGgZWgg7sdR4/T5hCx8SmD/JaGorld587V03qYqNYGrwlFrzgecYhAD9v/ppLFKaMgqVUjX9ZFtM+99Z5JC+CG7+QZNd+V2l0vgB7AW7lpDKf0foOFcMIFnM/QacwEZsJ6bYmdaLgRlmRa4ycJuQ9xzX+oAlI0aztrmSM3bjiaayVsXk6K1lXWanG+U5pWl2fPK86CcUCM3U6HQ2PMe9sKgmEZ6oatRxxrvt4JH7Efn3uBHKAnxiRjwUkd7vN00yCqgsr0rypdgUKvREolBpDq2WeXfrEbnAiQ0vLF+v3xBh5mVXVTb2gtnwk58Bau3yoiIeOHCwgIlv3BJR7dLHAh3zd/I9iyC11lvxAu6+hOinS/qGjG7C279pAE4KMvB++AHkxCXEtLNRvxueiS0k6UPBjGMQDnPwfjZ70LLpaQggXKSoY+zcBz4/HpXCQsDwcXCITxquCdFL2sjzjVvBgTWAt/s3GZB9E7MjkgG5QAWOtM6rS+x6jJk1Dwx6LqDYFhJWj7MRWTH8t0eJvfpYNnjZLa3ZWdE1Iy13ykKMPGLuU4Xh4Wu/21vuXOQVzDZnxuQQP0RIZiYthAJLNYsAUhkJjraHIACW2YRRXOrnjrzEiFGQdlBo2KQ4AnrefymbRDYrWGWHfha6LrLtWT9xj1mak9p19FqTrx9pCMyQ5eQXUxafOTnPnOtC5nkxVjRHpAie02zYZJ/1hEvB7mHnN14K3RW5KumlldAML6TA8IplUbzvVaiVZTsC4ZiJazElMCMGCSqGSIb3DQEJFTEWBBRKyAfVnNNLWreGclUsrqS6KGEybjBBMDEwDQYJYIZIAWUDBAIBBQAEIPepMB4FOX9Yc9IDCs/hCe6ZYgnx0qyInPk4m76VLrEoBAgmo0Mnfm9AjQICCAA="
+
 var SIGNED_CERT_RESOURCE_CREATE = BARRACUDA_WAF_PROVIDER + `
 resource "barracudawaf_signed_certificate" "demo_signed_cert" {
     name                     = "DemoSignedCert"
-    signed_certificate       = "MIIG2QIBAzCCBo8GCSqGSIb3DQEHAaCCBoAEggZ8MIIGeDCCA3cGCSqGSIb3DQEHBqCCA2gwggNkAgEAMIIDXQYJKoZIhvcNAQcBMBwGCiqGSIb3DQEMAQYwDgQIXyoSjDOcrdYCAggAgIIDMKPkmkUMSmtSmbv+YAWplEfvMjP0IGBa84lNd88uf7RQqBMW21hdorQGfteB0NpeiuNKXjbjCNDhDa/JXlD7wlnKu02yjjSAueaAakIR55VyyhfJj4otCDVwNNLpPa/sdKlnYi4o78AJmkkiRgRBL5Qq7QjBx1/CMtyrUrD/y45SAqv8YcqR5ehe8naCZjjPQXMmG3eJRnEaYmeI7x+wlFLxHobQJkiG6vRWESfs4S5R4lCVQhrgGZZx75ipD2OqMhK38JU8bpM5u0y6dUQZyHp88R4zLZ9gFAqhrtqOIOsihm6nrI/OM2MBNfgtSlBT/07rDf9MwIEyuvDw5l2f6anoK/H4AhAoh22inNwKzpDOwBko5eg1d02l5+74YxQTQqfzhgS/wFmgZzocsK3soHorD1dhKsWObm2WcLuWMo3qOU/+4/OKBTUkGCqZpc7TRLl+DGX+M1rXd/LK28CTjFkbE5G4JSpuyEmSFnvyxRPN3/NJH08H5RMxXGSYTmZvhoEArDQ9QV45h4eBKy4E/x60Wfq9AQBSQWNB5RJlD7/2tHMjPFkc0jlOzyusknrLdtOJ7Q+cWXg2qwSSUyEFKk4bZhbm2ltRrNjj7QTxR45JnI/S/G9kD0Gc0p1RhUvSUWAVBHNW0lyh7D3OI1uxXYPD5kUW1IQEJaihlwQGbeV/AXVCjqzPL0QMS9WFYRAVpjZdcML8HHcdkhEBxzXyso7cjlRk6vIUIZ4REBWBWr0n/7u5p4AlgNxOlzqGcYj4o5zLKWDXMZLwKVyH13A7Kvbzg2nFa0sPVmHFT1LGm7DVMkAu/GLf1PWeaVhRuHXv0vZvBpoZzhgma878nhhOCDegWrT40+SMngwgTvX9e4GR+eMuzOGxWPDfHO+MRIJBG0yzjxkJFCK6Lis8xIbGsPktUyHFz3fVgiTFounf210liMMBvwW26mtIAjHzCXnig/R4NVUwhWOAw+5Q+oe4SImGypooLmxc7Lk6hXg4zvlOIpP2aaOQrDxpJTBa2qpq5f21eoP+lv2Kwzoxzh1NamP3bTKLv6A325S8P0ANALefu6ZhpT7ZCDb9debaUgXWTjCCAvkGCSqGSIb3DQEHAaCCAuoEggLmMIIC4jCCAt4GCyqGSIb3DQEMCgECoIICpjCCAqIwHAYKKoZIhvcNAQwBAzAOBAgWEziGlG/eVAICCAAEggKAOiHXY/iQMyuuc8ezsHyjCsxuzY4lSzD4oXtrT5U9SL7+fd9MfNnPwoD+xMnPbev5IGM6EjeZd/kJXmqWXxqnCZiatGgZWgg7sdR4/T5hCx8SmD/JaGorld587V03qYqNYGrwlFrzgecYhAD9v/ppLFKaMgqVUjX9ZFtM+99Z5JC+CG7+QZNd+V2l0vgB7AW7lpDKf0foOFcMIFnM/QacwEZsJ6bYmdaLgRlmRa4ycJuQ9xzX+oAlI0aztrmSM3bjiaayVsXk6K1lXWanG+U5pWl2fPK86CcUCM3U6HQ2PMe9sKgmEZ6oatRxxrvt4JH7Efn3uBHKAnxiRjwUkd7vN00yCqgsr0rypdgUKvREolBpDq2WeXfrEbnAiQ0vLF+v3xBh5mVXVTb2gtnwk58Bau3yoiIeOHCwgIlv3BJR7dLHAh3zd/I9iyC11lvxAu6+hOinS/qGjG7C279pAE4KMvB++AHkxCXEtLNRvxueiS0k6UPBjGMQDnPwfjZ70LLpaQggXKSoY+zcBz4/HpXCQsDwcXCITxquCdFL2sjzjVvBgTWAt/s3GZB9E7MjkgG5QAWOtM6rS+x6jJk1Dwx6LqDYFhJWj7MRWTH8t0eJvfpYNnjZLa3ZWdE1Iy13ykKMPGLuU4Xh4Wu/21vuXOQVzDZnxuQQP0RIZiYthAJLNYsAUhkJjraHIACW2YRRXOrnjrzEiFGQdlBo2KQ4AnrefymbRDYrWGWHfha6LrLtWT9xj1mak9p19FqTrx9pCMyQ5eQXUxafOTnPnOtC5nkxVjRHpAie02zYZJ/1hEvB7mHnN14K3RW5KumlldAML6TA8IplUbzvVaiVZTsC4ZiJazElMCMGCSqGSIb3DQEJFTEWBBRKyAfVnNNLWreGclUsrqS6KGEybjBBMDEwDQYJYIZIAWUDBAIBBQAEIPepMB4FOX9Yc9IDCs/hCe6ZYgnx0qyInPk4m76VLrEoBAgmo0Mnfm9AjQICCAA="
+    signed_certificate       = "` + testBarracudaWAFPKCS12Bundle + `"
     certificate_type         = "PKCS12 Token"
     certificate_password     = "secret@123"
     allow_private_key_export = "Yes"
-    key_type                 = "RSA"
+    early_renewal_hours      = 720
 }
 `
 
@@ -39,34 +51,317 @@ func TestAccBarracudaWAFSignedCertificate_basic(t *testing.T) {
 
 func testCheckSignedCertificateExists(name string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*BarracudaWAF)
+		client := testAccProvider.Meta().(*waf.Client)
 
-		resourceEndpoint := "/signed-certificate/" + name
-		request := &APIRequest{
-			Method: "get",
-			URL:    resourceEndpoint,
+		if _, err := client.GetCertificate(context.Background(), waf.SignedCertificates, name); err != nil {
+			return fmt.Errorf("signed certificate (%s) not found on the system (%v)", name, err)
 		}
 
-		signedCerts, err := client.GetBarracudaWAFResource(name, request)
-		if err != nil {
-			return err
+		return nil
+	}
+}
+
+func TestBarracudaWAFSignedCertificate_crud(t *testing.T) {
+	crud := newTestResourceCRUD(t, resourceCudaWAFSignedCertificate())
+
+	leaf := newTestBarracudaWAFCertificate(t, "www.example.com", nil)
+
+	raw := map[string]interface{}{
+		"name":                 "DemoSignedCert",
+		"signed_certificate":   base64.StdEncoding.EncodeToString(leaf.pem),
+		"certificate_key":      string(leaf.keyPEM),
+		"certificate_password": "secret@123",
+	}
+
+	d := crud.create(raw)
+
+	testCheckParams(t, crud.server.Object("signed-certificate/DemoSignedCert"), map[string]interface{}{
+		"certificate-type":     "PEM Certificate",
+		"signed-certificate":   base64.StdEncoding.EncodeToString(leaf.pem),
+		"certificate-key":      base64.StdEncoding.EncodeToString(leaf.keyPEM),
+		"certificate-password": "secret@123",
+	})
+
+	state := d.State().Attributes
+	for _, attribute := range []string{"signed_certificate", "certificate_key", "certificate_password"} {
+		if state[attribute] != hashBarracudaWAFSecret(raw[attribute]) {
+			t.Errorf("expected only the hash of %s to be stored in the state, got %q", attribute, state[attribute])
 		}
+	}
+
+	crud.server.SetObject("signed-certificate/DemoSignedCert", map[string]interface{}{
+		"certificate-type":   "PEM Certificate",
+		"common-name":        "www.example.com",
+		"key-type":           "ecdsa",
+		"expiry":             "2027-01-17 12:30:00",
+		"serial":             "5F3A9C",
+		"issuer":             "CN=Demo CA",
+		"subject":            "CN=www.example.com",
+		"sha256-fingerprint": "9C:0F:5E:2B",
+	})
+
+	raw["schedule_renewal_day"] = "30"
+	d = crud.update(d.Id(), raw)
+
+	testCheckParams(t, crud.server.LastRequest("PUT", "/signed-certificate/DemoSignedCert").Body, map[string]interface{}{
+		"schedule-renewal-day": "30",
+		"signed-certificate":   nil,
+		"certificate-password": nil,
+		"encrypt-password":     nil,
+	})
+	testCheckAttributes(t, d, map[string]string{
+		"key_type":           "ecdsa",
+		"expiry":             "2027-01-17 12:30:00",
+		"serial":             "5F3A9C",
+		"issuer":             "CN=Demo CA",
+		"subject":            "CN=www.example.com",
+		"sha256_fingerprint": "9C:0F:5E:2B",
+	})
+
+	crud.delete(d.Id(), raw)
+	if crud.server.Object("signed-certificate/DemoSignedCert") != nil {
+		t.Fatalf("expected the certificate to be deleted")
+	}
+
+	if d = crud.read(d.Id(), raw); d.Id() != "" {
+		t.Errorf("expected the deleted certificate to be removed from the state")
+	}
+}
+
+func TestBarracudaWAFSignedCertificate_certificateRequest(t *testing.T) {
+	crud := newTestResourceCRUD(t, resourceCudaWAFSignedCertificate())
+
+	leaf := newTestBarracudaWAFCertificate(t, "www.example.com", nil)
+	crud.server.SetObject("certificate-request/DemoCSR", map[string]interface{}{
+		"name":                "DemoCSR",
+		"common-name":         "www.example.com",
+		"certificate-request": leaf.certificateRequest(t),
+	})
+
+	raw := map[string]interface{}{
+		"name":                     "DemoSignedCert",
+		"signed_certificate":       string(leaf.pem),
+		"certificate_request_name": "DemoCSR",
+	}
+
+	crud.create(raw)
+
+	testCheckParams(t, crud.server.LastRequest("POST", "/signed-certificate").Body, map[string]interface{}{
+		"assign-associated-key": "Yes",
+		"certificate-type":      "PEM Certificate",
+		"signed-certificate":    base64.StdEncoding.EncodeToString(leaf.pem),
+		"certificate-key":       nil,
+	})
+}
+
+func TestBarracudaWAFSignedCertificate_certificateRequestKeyMismatch(t *testing.T) {
+	crud := newTestResourceCRUD(t, resourceCudaWAFSignedCertificate())
+
+	leaf := newTestBarracudaWAFCertificate(t, "www.example.com", nil)
+	other := newTestBarracudaWAFCertificate(t, "www.example.com", nil)
+	crud.server.SetObject("certificate-request/DemoCSR", map[string]interface{}{
+		"name":                "DemoCSR",
+		"common-name":         "www.example.com",
+		"certificate-request": base64.StdEncoding.EncodeToString([]byte(other.certificateRequest(t))),
+	})
+
+	d := schema.TestResourceDataRaw(t, crud.resource.Schema, map[string]interface{}{
+		"name":                     "DemoSignedCert",
+		"signed_certificate":       string(leaf.pem),
+		"certificate_request_name": "DemoCSR",
+	})
+
+	diags := crud.resource.CreateContext(context.Background(), d, crud.client)
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "is not signed for the key of the certificate request") {
+		t.Fatalf("expected the upload to fail as the certificate does not match the pending key, got %v", diags)
+	}
+
+	if requests := crud.server.Requests("POST", "/signed-certificate"); len(requests) != 0 {
+		t.Errorf("expected the certificate not to be uploaded, got %v", requests)
+	}
+}
+
+func TestBarracudaWAFSignedCertificate_invalid(t *testing.T) {
+	resource := resourceCudaWAFSignedCertificate()
+
+	leaf := newTestBarracudaWAFCertificate(t, "www.example.com", nil)
+	other := newTestBarracudaWAFCertificate(t, "www.example.org", nil)
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":               "DemoSignedCert",
+		"signed_certificate": string(leaf.pem),
+		"certificate_key":    string(other.keyPEM),
+	})
 
-		if signedCerts == nil {
-			return fmt.Errorf("signed certificate %s was not created.", name)
+	_, err := resource.Diff(context.Background(), nil, config, nil)
+	if err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Fatalf("expected the plan to fail as the key does not match the certificate, got %v", err)
+	}
+}
+
+func TestBarracudaWAFSignedCertificate_files(t *testing.T) {
+	crud := newTestResourceCRUD(t, resourceCudaWAFSignedCertificate())
+
+	leaf := newTestBarracudaWAFCertificate(t, "www.example.com", nil)
+
+	directory, err := ioutil.TempDir("", "barracudawaf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+
+	certificateFile := filepath.Join(directory, "certificate.pem")
+	keyFile := filepath.Join(directory, "key.pem")
+	ioutil.WriteFile(certificateFile, leaf.pem, 0600)
+	ioutil.WriteFile(keyFile, leaf.keyPEM, 0600)
+
+	d := crud.create(map[string]interface{}{
+		"name":                    "DemoSignedCert",
+		"signed_certificate_file": certificateFile,
+		"certificate_key_file":    keyFile,
+	})
+
+	keyFileHash := hashBarracudaWAFSecret(string(leaf.keyPEM))
+	testCheckAttributes(t, d, map[string]string{"certificate_key_file_sha256": keyFileHash})
+
+	fingerprint := strings.ToUpper(barracudaWAFFingerprint(leaf.certificate))
+
+	cases := map[string]struct {
+		fingerprint string
+		keyFileHash string
+		requiresNew bool
+	}{
+		"same certificate":    {fingerprint: fingerprint, keyFileHash: keyFileHash, requiresNew: false},
+		"renewed certificate": {fingerprint: "9C:0F:5E:2B", keyFileHash: keyFileHash, requiresNew: true},
+		"new key":             {fingerprint: fingerprint, keyFileHash: hashBarracudaWAFSecret("previous key"), requiresNew: true},
+		"imported":            {fingerprint: fingerprint, keyFileHash: "", requiresNew: false},
+	}
+
+	for name, c := range cases {
+		state := &terraform.InstanceState{
+			ID: "DemoSignedCert",
+			Attributes: map[string]string{
+				"id":                          "DemoSignedCert",
+				"name":                        "DemoSignedCert",
+				"signed_certificate_file":     certificateFile,
+				"certificate_key_file":        keyFile,
+				"assign_associated_key":       "No",
+				"certificate_type":            "PEM Certificate",
+				"download_type":               "",
+				"intermediary_certificates.#": "0",
+				"auto_renew_cert":             "No",
+				"common_name":                 "www.example.com",
+				"allow_private_key_export":    "No",
+				"schedule_renewal_day":        "",
+				"expiry":                      leaf.certificate.NotAfter.UTC().Format(time.RFC3339),
+				"serial":                      "5F3A9C",
+				"issuer":                      "CN=www.example.com",
+				"subject":                     "CN=www.example.com",
+				"sha256_fingerprint":          c.fingerprint,
+				"certificate_key_file_sha256": c.keyFileHash,
+				"key_type":                    "ecdsa",
+			},
 		}
 
-		var dataItems map[string]interface{}
-		for _, dataItems = range signedCerts.Data {
-			if dataItems["name"] == name {
-				break
-			}
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":                    "DemoSignedCert",
+			"signed_certificate_file": certificateFile,
+			"certificate_key_file":    keyFile,
+		})
+
+		diff, err := crud.resource.Diff(context.Background(), state, config, nil)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
 		}
 
-		if dataItems["name"] != name {
-			return fmt.Errorf("signed certificate (%s) not found on the system", name)
+		if requiresNew := diff != nil && diff.RequiresNew(); requiresNew != c.requiresNew {
+			t.Errorf("%s: expected requires new to be %t", name, c.requiresNew)
 		}
+	}
+}
 
-		return nil
+func TestBarracudaWAFSignedCertificate_import(t *testing.T) {
+	crud := newTestResourceCRUD(t, resourceCudaWAFSignedCertificate())
+
+	leaf := newTestBarracudaWAFCertificate(t, "www.example.com", nil)
+	other := newTestBarracudaWAFCertificate(t, "www.example.com", nil)
+
+	directory, err := ioutil.TempDir("", "barracudawaf")
+	if err != nil {
+		t.Fatal(err)
 	}
+	defer os.RemoveAll(directory)
+
+	certificateFile := filepath.Join(directory, "certificate.pem")
+	ioutil.WriteFile(certificateFile, leaf.pem, 0600)
+
+	crud.server.SetObject("signed-certificate/DemoSignedCert", map[string]interface{}{
+		"name":               "DemoSignedCert",
+		"common-name":        "www.example.com",
+		"sha256-fingerprint": strings.ToUpper(barracudaWAFFingerprint(leaf.certificate)),
+	})
+
+	d := crud.resource.Data(nil)
+	d.SetId("DemoSignedCert")
+
+	imported, err := crud.resource.Importer.StateContext(context.Background(), d, crud.client)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if diags := crud.resource.ReadContext(context.Background(), imported[0], crud.client); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	state := imported[0].State()
+
+	cases := map[string]struct {
+		config      map[string]interface{}
+		requiresNew bool
+	}{
+		"same certificate": {
+			config:      map[string]interface{}{"signed_certificate": string(leaf.pem), "certificate_key": string(leaf.keyPEM)},
+			requiresNew: false,
+		},
+		"same certificate file": {
+			config:      map[string]interface{}{"signed_certificate_file": certificateFile, "certificate_key": string(leaf.keyPEM)},
+			requiresNew: false,
+		},
+		// the certificate request of the key assigned to the certificate is not fetched
+		"same certificate request": {
+			config:      map[string]interface{}{"signed_certificate": string(leaf.pem), "certificate_request_name": "DemoCSR"},
+			requiresNew: false,
+		},
+		"other certificate": {
+			config:      map[string]interface{}{"signed_certificate": string(other.pem), "certificate_key": string(other.keyPEM)},
+			requiresNew: true,
+		},
+	}
+
+	for name, c := range cases {
+		c.config["name"] = "DemoSignedCert"
+
+		diff, err := crud.resource.Diff(context.Background(), state, terraform.NewResourceConfigRaw(c.config), crud.client)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+
+		if requiresNew := diff != nil && diff.RequiresNew(); requiresNew != c.requiresNew {
+			t.Errorf("%s: expected requires new to be %t, got %v", name, c.requiresNew, diff)
+		}
+	}
+}
+
+func TestHydrateBarracudaWAFSignedCertificateResource(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCudaWAFSignedCertificate().Schema, map[string]interface{}{
+		"name":             "DemoSignedCert",
+		"certificate_type": "PEM Certificate",
+		"encrypt_password": "secret@123",
+	})
+
+	testCheckPayload(t, hydrateBarracudaWAFSignedCertificateResource(d), map[string]interface{}{
+		"name":               "DemoSignedCert",
+		"certificate-type":   "PEM Certificate",
+		"encrypt-password":   "secret@123",
+		"signed-certificate": nil,
+		"certificate-key":    nil,
+	})
 }
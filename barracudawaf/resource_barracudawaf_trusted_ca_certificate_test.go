@@ -1,10 +1,13 @@
 package barracudawaf
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -33,34 +36,49 @@ func TestAccBarracudaWAFTrustedCACertificate_basic(t *testing.T) {
 
 func testCheckTrustedCACertificateExists(name string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*BarracudaWAF)
+		client := testAccProvider.Meta().(*waf.Client)
 
-		resourceEndpoint := "/trusted-ca-certificate/" + name
-		request := &APIRequest{
-			Method: "get",
-			URL:    resourceEndpoint,
+		if _, err := client.GetCertificate(context.Background(), waf.TrustedCACertificates, name); err != nil {
+			return fmt.Errorf("trusted ca certificate (%s) not found on the system (%v)", name, err)
 		}
 
-		trustedCACerts, err := client.GetBarracudaWAFResource(name, request)
-		if err != nil {
-			return err
-		}
+		return nil
+	}
+}
 
-		if trustedCACerts == nil {
-			return fmt.Errorf("trusted ca certificate %s was not created.", name)
-		}
+func TestBarracudaWAFTrustedCACertificate_crud(t *testing.T) {
+	crud := newTestResourceCRUD(t, resourceCudaWAFTrustedCaCertificate())
 
-		var dataItems map[string]interface{}
-		for _, dataItems = range trustedCACerts.Data {
-			if dataItems["name"] == name {
-				break
-			}
-		}
+	raw := map[string]interface{}{
+		"name":        "DemoTrustedCACert",
+		"certificate": "MIIDdzCCAl+gAwIBAgIEAgAAuTANBgkqhkiG9w0BAQUFADBa",
+	}
 
-		if dataItems["name"] != name {
-			return fmt.Errorf("trusted ca certificate (%s) not found on the system", name)
-		}
+	d := crud.create(raw)
 
-		return nil
+	testCheckParams(t, crud.server.Object("trusted-ca-certificate/DemoTrustedCACert"), map[string]interface{}{
+		"certificate": "MIIDdzCCAl+gAwIBAgIEAgAAuTANBgkqhkiG9w0BAQUFADBa",
+	})
+	testCheckAttributes(t, d, map[string]string{"name": "DemoTrustedCACert"})
+
+	crud.delete(d.Id(), raw)
+	if crud.server.Object("trusted-ca-certificate/DemoTrustedCACert") != nil {
+		t.Fatalf("expected the certificate to be deleted")
 	}
+
+	if d = crud.read(d.Id(), raw); d.Id() != "" {
+		t.Errorf("expected the deleted certificate to be removed from the state")
+	}
+}
+
+func TestHydrateBarracudaWAFTrustedCaCertificateResource(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCudaWAFTrustedCaCertificate().Schema, map[string]interface{}{
+		"name":        "DemoTrustedCACert",
+		"certificate": "MIIDdzCCAl+gAwIBAgIEAgAAuTANBgkqhkiG9w0BAQUFADBa",
+	})
+
+	testCheckPayload(t, hydrateBarracudaWAFTrustedCaCertificateResource(d), map[string]interface{}{
+		"name":        "DemoTrustedCACert",
+		"certificate": "MIIDdzCCAl+gAwIBAgIEAgAAuTANBgkqhkiG9w0BAQUFADBa",
+	})
 }
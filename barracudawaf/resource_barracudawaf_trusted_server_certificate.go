@@ -1,220 +1,139 @@
 package barracudawaf
 
 import (
-	"fmt"
+	"context"
 	"log"
-	"strings"
 
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
-var (
-	subResourceTrustedServerCertificateParams = map[string][]string{}
-)
-
 func resourceCudaWAFTrustedServerCertificate() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceCudaWAFTrustedServerCertificateCreate,
-		Read:   resourceCudaWAFTrustedServerCertificateRead,
-		Update: resourceCudaWAFTrustedServerCertificateUpdate,
-		Delete: resourceCudaWAFTrustedServerCertificateDelete,
+		CreateContext: resourceCudaWAFTrustedServerCertificateCreate,
+		ReadContext:   resourceCudaWAFTrustedServerCertificateRead,
+		DeleteContext: resourceCudaWAFTrustedServerCertificateDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceCudaWAFTrustedServerCertificateV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: upgradeBarracudaWAFIdentityState,
+			},
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Read:   schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
 
 		Schema: map[string]*schema.Schema{
-			"name":        {Type: schema.TypeString, Optional: true, Description: "Certificate Name"},
-			"certificate": {Type: schema.TypeString, Optional: true},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Certificate Name",
+				ForceNew:    true,
+			},
+			"certificate": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
 		},
 
 		Description: "`barracudawaf_trusted_server_certificate` manages `Trusted Server Certificate` on the Barracuda Web Application Firewall.",
 	}
 }
 
-func resourceCudaWAFTrustedServerCertificateCreate(d *schema.ResourceData, m interface{}) error {
-	client := m.(*BarracudaWAF)
+func resourceCudaWAFTrustedServerCertificateCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
 
 	name := d.Get("name").(string)
 
 	log.Println("[INFO] Creating Barracuda WAF resource " + name)
 
-	resourceEndpoint := "/trusted-server-certificate"
-	err := client.CreateBarracudaWAFResource(
-		name,
-		hydrateBarracudaWAFTrustedServerCertificateResource(d, "post", resourceEndpoint),
-	)
+	err := client.CreateCertificate(ctx, waf.TrustedServerCertificates, hydrateBarracudaWAFTrustedServerCertificateResource(d))
 
 	if err != nil {
 		log.Printf("[ERROR] Unable to create Barracuda WAF resource (%s) (%v) ", name, err)
-		return err
-	}
-
-	err = client.hydrateBarracudaWAFTrustedServerCertificateSubResource(d, name, resourceEndpoint)
-
-	if err != nil {
-		log.Printf("[ERROR] Unable to create Barracuda WAF sub resource (%s) (%v) ", name, err)
-		return err
+		return barracudaWAFDiagnostics(err, resourceCudaWAFTrustedServerCertificate().Schema, "Unable to create Barracuda WAF resource (%s)", name)
 	}
 
 	d.SetId(name)
-	return resourceCudaWAFTrustedServerCertificateRead(d, m)
+	return resourceCudaWAFTrustedServerCertificateRead(ctx, d, m)
 }
 
-func resourceCudaWAFTrustedServerCertificateRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(*BarracudaWAF)
+func resourceCudaWAFTrustedServerCertificateRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
 
 	name := d.Id()
 	log.Println("[INFO] Fetching Barracuda WAF resource " + name)
 
-	resourceEndpoint := "/trusted-server-certificate"
-	request := &APIRequest{
-		Method: "get",
-		URL:    resourceEndpoint,
-	}
-
-	var dataItems map[string]interface{}
-	resources, err := client.GetBarracudaWAFResource(name, request)
+	certificate, err := client.GetCertificate(ctx, waf.TrustedServerCertificates, name)
 
-	if err != nil {
-		log.Printf("[ERROR] Unable to Retrieve Barracuda WAF resource (%s) (%v) ", name, err)
-		return err
-	}
-
-	if resources.Data == nil {
+	if waf.IsNotFound(err) {
 		log.Printf("[WARN] Barracuda WAF resource (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
 	}
 
-	for _, dataItems = range resources.Data {
-		if dataItems["name"] == name {
-			break
-		}
-	}
-
-	if dataItems["name"] != name {
-		return fmt.Errorf("Barracuda WAF resource (%s) not found on the system", name)
-	}
-
-	d.Set("name", name)
-	return nil
-}
-
-func resourceCudaWAFTrustedServerCertificateUpdate(d *schema.ResourceData, m interface{}) error {
-	client := m.(*BarracudaWAF)
-
-	name := d.Id()
-
-	log.Println("[INFO] Updating Barracuda WAF resource " + name)
-
-	resourceEndpoint := "/trusted-server-certificate"
-	err := client.UpdateBarracudaWAFResource(
-		name,
-		hydrateBarracudaWAFTrustedServerCertificateResource(d, "put", resourceEndpoint),
-	)
-
 	if err != nil {
-		log.Printf("[ERROR] Unable to update the Barracuda WAF resource (%s) (%v)", name, err)
-		return err
+		log.Printf("[ERROR] Unable to Retrieve Barracuda WAF resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFTrustedServerCertificate().Schema, "Unable to Retrieve Barracuda WAF resource (%s)", name)
 	}
 
-	err = client.hydrateBarracudaWAFTrustedServerCertificateSubResource(d, name, resourceEndpoint)
+	err = setBarracudaWAFResourceData(
+		d,
+		resourceCudaWAFTrustedServerCertificate().Schema,
+		certificate,
+		"certificate",
+	)
 
 	if err != nil {
-		log.Printf("[ERROR] Unable to update the Barracuda WAF sub resource (%s) (%v)", name, err)
-		return err
+		log.Printf("[ERROR] Unable to set Barracuda WAF resource (%s) (%v) ", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFTrustedServerCertificate().Schema, "Unable to set Barracuda WAF resource (%s)", name)
 	}
 
-	return resourceCudaWAFTrustedServerCertificateRead(d, m)
+	d.Set("name", name)
+	return nil
 }
 
-func resourceCudaWAFTrustedServerCertificateDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(*BarracudaWAF)
+func resourceCudaWAFTrustedServerCertificateDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*waf.Client)
 
 	name := d.Id()
 
 	log.Println("[INFO] Deleting Barracuda WAF resource " + name)
 
-	resourceEndpoint := "/trusted-server-certificate"
-	request := &APIRequest{
-		Method: "delete",
-		URL:    resourceEndpoint,
-	}
-
-	err := client.DeleteBarracudaWAFResource(name, request)
+	err := client.DeleteCertificate(ctx, waf.TrustedServerCertificates, name)
 
 	if err != nil {
-		return fmt.Errorf("Unable to delete the Barracuda WAF resource (%s) (%v)", name, err)
+		return barracudaWAFDiagnostics(err, resourceCudaWAFTrustedServerCertificate().Schema, "Unable to delete the Barracuda WAF resource (%s)", name)
 	}
 
 	return nil
 }
 
-func hydrateBarracudaWAFTrustedServerCertificateResource(
-	d *schema.ResourceData,
-	method string,
-	endpoint string,
-) *APIRequest {
-
-	//resourcePayload : payload for the resource
-	resourcePayload := map[string]string{
-		"name":        d.Get("name").(string),
-		"certificate": d.Get("certificate").(string),
-	}
-
-	// parameters not supported for updates
-	if method == "put" {
-		updatePayloadExceptions := [...]string{"name", "certificate"}
-		for _, param := range updatePayloadExceptions {
-			delete(resourcePayload, param)
-		}
-	}
-
-	// remove empty parameters from resource payload
-	for key, val := range resourcePayload {
-		if len(val) == 0 {
-			delete(resourcePayload, key)
-		}
-	}
-
-	return &APIRequest{
-		URL:  endpoint,
-		Body: resourcePayload,
+func hydrateBarracudaWAFTrustedServerCertificateResource(d *schema.ResourceData) *waf.Certificate {
+	return &waf.Certificate{
+		Name:        d.Get("name").(string),
+		Certificate: d.Get("certificate").(string),
 	}
 }
 
-func (b *BarracudaWAF) hydrateBarracudaWAFTrustedServerCertificateSubResource(
-	d *schema.ResourceData,
-	name string,
-	endpoint string,
-) error {
-
-	for subResource, subResourceParams := range subResourceTrustedServerCertificateParams {
-		subResourceParamsLength := d.Get(subResource + ".#").(int)
-
-		log.Printf("[INFO] Updating Barracuda WAF sub resource (%s) (%s)", name, subResource)
-
-		for i := 0; i < subResourceParamsLength; i++ {
-			subResourcePayload := map[string]string{}
-			suffix := fmt.Sprintf(".%d", i)
-
-			for _, param := range subResourceParams {
-				paramSuffix := fmt.Sprintf(".%s", param)
-				paramVaule := d.Get(subResource + suffix + paramSuffix).(string)
-
-				if len(paramVaule) > 0 {
-					param = strings.Replace(param, "_", "-", -1)
-					subResourcePayload[param] = paramVaule
-				}
-			}
-
-			err := b.UpdateBarracudaWAFSubResource(name, endpoint, &APIRequest{
-				URL:  strings.Replace(subResource, "_", "-", -1),
-				Body: subResourcePayload,
-			})
-
-			if err != nil {
-				return err
-			}
-		}
+// resourceCudaWAFTrustedServerCertificateV0 : schema of the version 0 state of trusted server certificates, which is
+// a valid version 1 state.
+func resourceCudaWAFTrustedServerCertificateV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name":        {Type: schema.TypeString, Optional: true},
+			"certificate": {Type: schema.TypeString, Optional: true},
+		},
 	}
-
-	return nil
 }
@@ -1,10 +1,13 @@
 package barracudawaf
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -33,34 +36,49 @@ func TestAccBarracudaWAFTrustedServerCertificate_basic(t *testing.T) {
 
 func testCheckTrustedServerCertificateExists(name string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*BarracudaWAF)
+		client := testAccProvider.Meta().(*waf.Client)
 
-		resourceEndpoint := "/trusted-server-certificate/" + name
-		request := &APIRequest{
-			Method: "get",
-			URL:    resourceEndpoint,
+		if _, err := client.GetCertificate(context.Background(), waf.TrustedServerCertificates, name); err != nil {
+			return fmt.Errorf("trusted server certificate (%s) not found on the system (%v)", name, err)
 		}
 
-		trustedServerCerts, err := client.GetBarracudaWAFResource(name, request)
-		if err != nil {
-			return err
-		}
+		return nil
+	}
+}
 
-		if trustedServerCerts == nil {
-			return fmt.Errorf("trusted server certificate %s was not created.", name)
-		}
+func TestBarracudaWAFTrustedServerCertificate_crud(t *testing.T) {
+	crud := newTestResourceCRUD(t, resourceCudaWAFTrustedServerCertificate())
 
-		var dataItems map[string]interface{}
-		for _, dataItems = range trustedServerCerts.Data {
-			if dataItems["name"] == name {
-				break
-			}
-		}
+	raw := map[string]interface{}{
+		"name":        "DemoTrustedServerCert",
+		"certificate": "MIIDdzCCAl+gAwIBAgIEAgAAuTANBgkqhkiG9w0BAQUFADBa",
+	}
 
-		if dataItems["name"] != name {
-			return fmt.Errorf("trusted server certificate (%s) not found on the system", name)
-		}
+	d := crud.create(raw)
 
-		return nil
+	testCheckParams(t, crud.server.Object("trusted-server-certificate/DemoTrustedServerCert"), map[string]interface{}{
+		"certificate": "MIIDdzCCAl+gAwIBAgIEAgAAuTANBgkqhkiG9w0BAQUFADBa",
+	})
+	testCheckAttributes(t, d, map[string]string{"name": "DemoTrustedServerCert"})
+
+	crud.delete(d.Id(), raw)
+	if crud.server.Object("trusted-server-certificate/DemoTrustedServerCert") != nil {
+		t.Fatalf("expected the certificate to be deleted")
 	}
+
+	if d = crud.read(d.Id(), raw); d.Id() != "" {
+		t.Errorf("expected the deleted certificate to be removed from the state")
+	}
+}
+
+func TestHydrateBarracudaWAFTrustedServerCertificateResource(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceCudaWAFTrustedServerCertificate().Schema, map[string]interface{}{
+		"name":        "DemoTrustedServerCert",
+		"certificate": "MIIDdzCCAl+gAwIBAgIEAgAAuTANBgkqhkiG9w0BAQUFADBa",
+	})
+
+	testCheckPayload(t, hydrateBarracudaWAFTrustedServerCertificateResource(d), map[string]interface{}{
+		"name":        "DemoTrustedServerCert",
+		"certificate": "MIIDdzCCAl+gAwIBAgIEAgAAuTANBgkqhkiG9w0BAQUFADBa",
+	})
 }
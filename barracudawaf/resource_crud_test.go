@@ -0,0 +1,140 @@
+package barracudawaf
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf/waftest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// testResourceCRUD : creates, reads, updates and deletes a resource against the fake WAF
+// and returns the state after each step.
+type testResourceCRUD struct {
+	t        *testing.T
+	server   *waftest.Server
+	client   *waf.Client
+	resource *schema.Resource
+}
+
+func newTestResourceCRUD(t *testing.T, resource *schema.Resource) *testResourceCRUD {
+	server := waftest.NewServer(t)
+
+	client := waf.NewClient(server.URL, "", waftest.Username, waftest.Password)
+	client.RetryMinWait = time.Millisecond
+	client.RetryMaxWait = 5 * time.Millisecond
+
+	if err := client.Login(context.Background()); err != nil {
+		t.Fatalf("unable to log in to the fake WAF: %v", err)
+	}
+
+	return &testResourceCRUD{t: t, server: server, client: client, resource: resource}
+}
+
+// with : returns the CRUD of another resource against the same fake WAF.
+func (c *testResourceCRUD) with(resource *schema.Resource) *testResourceCRUD {
+	return &testResourceCRUD{t: c.t, server: c.server, client: c.client, resource: resource}
+}
+
+// create : runs Create with the given configuration.
+func (c *testResourceCRUD) create(raw map[string]interface{}) *schema.ResourceData {
+	c.t.Helper()
+
+	d := schema.TestResourceDataRaw(c.t, c.resource.Schema, raw)
+	if diags := c.resource.CreateContext(context.Background(), d, c.client); diags.HasError() {
+		c.t.Fatalf("create: %v", diags)
+	}
+
+	if d.Id() == "" {
+		c.t.Fatalf("create: the ID was not set")
+	}
+
+	return d
+}
+
+// update : runs Update of the resource with the given ID and configuration.
+func (c *testResourceCRUD) update(id string, raw map[string]interface{}) *schema.ResourceData {
+	c.t.Helper()
+
+	d := schema.TestResourceDataRaw(c.t, c.resource.Schema, raw)
+	d.SetId(id)
+	if diags := c.resource.UpdateContext(context.Background(), d, c.client); diags.HasError() {
+		c.t.Fatalf("update: %v", diags)
+	}
+
+	return d
+}
+
+// read : runs Read of the resource with the given ID and configuration.
+func (c *testResourceCRUD) read(id string, raw map[string]interface{}) *schema.ResourceData {
+	c.t.Helper()
+
+	d := schema.TestResourceDataRaw(c.t, c.resource.Schema, raw)
+	d.SetId(id)
+	if diags := c.resource.ReadContext(context.Background(), d, c.client); diags.HasError() {
+		c.t.Fatalf("read: %v", diags)
+	}
+
+	return d
+}
+
+// delete : runs Delete of the resource with the given ID and configuration.
+func (c *testResourceCRUD) delete(id string, raw map[string]interface{}) {
+	c.t.Helper()
+
+	d := schema.TestResourceDataRaw(c.t, c.resource.Schema, raw)
+	d.SetId(id)
+	if diags := c.resource.DeleteContext(context.Background(), d, c.client); diags.HasError() {
+		c.t.Fatalf("delete: %v", diags)
+	}
+}
+
+// testCheckAttributes : fails the test when the state does not hold the expected values.
+func testCheckAttributes(t *testing.T, d *schema.ResourceData, expected map[string]string) {
+	t.Helper()
+
+	keys := make([]string, 0, len(expected))
+	for key := range expected {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if value := fmt.Sprint(d.Get(key)); value != expected[key] {
+			t.Errorf("expected %s to be %q, got %q", key, expected[key], value)
+		}
+	}
+}
+
+// testCheckParams : fails the test when the parameters do not hold the expected values,
+// a nil expected value asserts the parameter is not set.
+func testCheckParams(t *testing.T, params map[string]interface{}, expected map[string]interface{}) {
+	t.Helper()
+
+	if params == nil {
+		t.Fatalf("expected the object to exist on the WAF")
+	}
+
+	for key, val := range expected {
+		actual, ok := params[key]
+
+		switch {
+		case val == nil && ok:
+			t.Errorf("expected parameter %s not to be set, got %v", key, actual)
+		case val != nil && fmt.Sprint(actual) != fmt.Sprint(val):
+			t.Errorf("expected parameter %s to be %v, got %v", key, val, actual)
+		}
+	}
+}
+
+// testCheckPayload : fails the test when the payload of the typed WAF object does not hold
+// the expected values.
+func testCheckPayload(t *testing.T, object interface{}, expected map[string]interface{}) {
+	t.Helper()
+
+	testCheckParams(t, barracudaWAFParams(object), expected)
+}
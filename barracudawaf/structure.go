@@ -0,0 +1,503 @@
+package barracudawaf
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// barracudaWAFParams : returns the parameters of a typed WAF object keyed by their REST names.
+func barracudaWAFParams(object interface{}) map[string]interface{} {
+	params := make(map[string]interface{})
+
+	data, err := json.Marshal(object)
+	if err == nil {
+		err = json.Unmarshal(data, &params)
+	}
+
+	if err != nil {
+		log.Printf("[ERROR] Unable to convert the Barracuda WAF object (%T) (%v)", object, err)
+	}
+
+	return params
+}
+
+// toBarracudaWAFParam : converts a schema attribute name to the WAF REST parameter name.
+func toBarracudaWAFParam(attribute string) string {
+	return strings.Replace(attribute, "_", "-", -1)
+}
+
+// flattenBarracudaWAFValue : converts a value returned by the WAF REST API to the
+// representation used in the schema, all scalars are stored as strings.
+func flattenBarracudaWAFValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		items := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			items = append(items, flattenBarracudaWAFValue(item))
+		}
+		return items
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// flattenBarracudaWAFList : wraps a scalar returned by the WAF REST API into a list
+// for attributes declared as lists of strings.
+func flattenBarracudaWAFList(value interface{}) []interface{} {
+	switch v := flattenBarracudaWAFValue(value).(type) {
+	case []interface{}:
+		return v
+	case string:
+		if v == "" {
+			return []interface{}{}
+		}
+		return []interface{}{v}
+	}
+
+	return []interface{}{}
+}
+
+// flattenBarracudaWAFResourceData : maps the parameters of a typed WAF object to the attributes
+// of the given schema. Only the attributes with a scalar or list of strings type are returned.
+func flattenBarracudaWAFResourceData(
+	resourceSchema map[string]*schema.Schema,
+	data map[string]interface{},
+	skip ...string,
+) map[string]interface{} {
+	skipped := make(map[string]bool)
+	for _, attribute := range skip {
+		skipped[attribute] = true
+	}
+
+	attributes := make(map[string]interface{})
+	for attribute, attributeSchema := range resourceSchema {
+		if skipped[attribute] {
+			continue
+		}
+
+		value, ok := data[toBarracudaWAFParam(attribute)]
+		if !ok {
+			continue
+		}
+
+		switch attributeSchema.Type {
+		case schema.TypeString:
+			attributes[attribute] = flattenBarracudaWAFValue(value)
+		case schema.TypeList:
+			if _, ok := attributeSchema.Elem.(*schema.Schema); ok {
+				attributes[attribute] = flattenBarracudaWAFList(value)
+			}
+		}
+	}
+
+	return attributes
+}
+
+// setBarracudaWAFResourceData : sets the attributes of the resource from a typed WAF object.
+// Parameters the object does not hold a value for are left unchanged.
+func setBarracudaWAFResourceData(
+	d *schema.ResourceData,
+	resourceSchema map[string]*schema.Schema,
+	object interface{},
+	skip ...string,
+) error {
+	for attribute, value := range flattenBarracudaWAFResourceData(resourceSchema, barracudaWAFParams(object), skip...) {
+		if err := d.Set(attribute, value); err != nil {
+			return fmt.Errorf("error setting %s: %v", attribute, err)
+		}
+	}
+
+	return nil
+}
+
+// setBarracudaWAFSubResourceData : sets a sub resource of the resource from a typed WAF object,
+// as a single element block. Sub resources without any parameter are left unchanged.
+func setBarracudaWAFSubResourceData(
+	d *schema.ResourceData,
+	resourceSchema map[string]*schema.Schema,
+	subResource string,
+	object interface{},
+) error {
+	subResourceSchema := resourceSchema[subResource].Elem.(*schema.Resource).Schema
+	block := flattenBarracudaWAFResourceData(subResourceSchema, barracudaWAFParams(object))
+
+	if len(block) == 0 {
+		return nil
+	}
+
+	if err := d.Set(subResource, []interface{}{block}); err != nil {
+		return fmt.Errorf("error setting %s: %v", subResource, err)
+	}
+
+	return nil
+}
+
+// isBarracudaWAFEmpty : reports whether a configured value is an empty string or list. Values
+// of other types are never empty.
+func isBarracudaWAFEmpty(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	}
+
+	return false
+}
+
+// expandBarracudaWAFSubResource : decodes the configured block of a sub resource into the typed
+// WAF object, empty attributes are not set. It reports whether the block is configured.
+func expandBarracudaWAFSubResource(d *schema.ResourceData, subResource string, object interface{}) bool {
+	if d.Get(subResource+".#").(int) == 0 {
+		return false
+	}
+
+	block, ok := d.Get(subResource + ".0").(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	params := make(map[string]interface{})
+	for attribute, value := range block {
+		if !isBarracudaWAFEmpty(value) {
+			params[toBarracudaWAFParam(attribute)] = value
+		}
+	}
+
+	data, err := json.Marshal(params)
+	if err == nil {
+		err = json.Unmarshal(data, object)
+	}
+
+	if err != nil {
+		log.Printf("[ERROR] Unable to convert the Barracuda WAF sub resource (%s) (%v)", subResource, err)
+		return false
+	}
+
+	return true
+}
+
+// expandStringList : converts a list attribute to the list of its string values.
+func expandStringList(list []interface{}) []string {
+	values := make([]string, 0, len(list))
+	for _, value := range list {
+		if value, ok := value.(string); ok && value != "" {
+			values = append(values, value)
+		}
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	return values
+}
+
+// importBarracudaWAFNamedParentsResource : returns the import function for resources naming
+// each of their parents in its own attribute, e.g. "service_name". The import ID is the slash
+// separated list of the parent names followed by the name of the resource.
+func importBarracudaWAFNamedParentsResource(attributes ...string) schema.StateContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+		parts, err := splitBarracudaWAFImportID(d.Id(), attributes)
+		if err != nil {
+			return nil, err
+		}
+
+		name := parts[len(attributes)]
+
+		for i, attribute := range attributes {
+			if err := d.Set(attribute, parts[i]); err != nil {
+				return nil, err
+			}
+		}
+
+		d.Set("name", name)
+		d.SetId(name)
+
+		return []*schema.ResourceData{d}, nil
+	}
+}
+
+// splitBarracudaWAFImportID : splits an import ID into the names of the parents followed by
+// the name of the resource.
+func splitBarracudaWAFImportID(id string, parents []string) ([]string, error) {
+	parts := strings.Split(id, "/")
+
+	if len(parts) != len(parents)+1 {
+		return nil, fmt.Errorf(
+			"unexpected format of ID (%s), expected %s/<name>",
+			id,
+			"<"+strings.Join(parents, ">/<")+">",
+		)
+	}
+
+	for _, part := range parts {
+		if part == "" {
+			return nil, fmt.Errorf("unexpected format of ID (%s), names must not be empty", id)
+		}
+	}
+
+	return parts, nil
+}
+
+// upgradeBarracudaWAFParentState : returns the state upgrade function moving the names of the
+// parents held by the "parent" list of version 0 states to the given attributes, in order.
+func upgradeBarracudaWAFParentState(attributes ...string) schema.StateUpgradeFunc {
+	return func(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+		parent, _ := rawState["parent"].([]interface{})
+
+		if len(parent) != len(attributes) {
+			return nil, fmt.Errorf(
+				"unexpected parent (%v), expected [%s]",
+				parent,
+				"<"+strings.Join(attributes, ">, <")+">",
+			)
+		}
+
+		for i, attribute := range attributes {
+			rawState[attribute] = parent[i]
+		}
+		delete(rawState, "parent")
+
+		return rawState, nil
+	}
+}
+
+// upgradeBarracudaWAFDeprecatedParentState : returns the state upgrade function of the resources
+// still accepting the deprecated "parent" list, see upgradeBarracudaWAFParentState. The list is
+// kept so that configurations setting it are not changed by the upgrade.
+func upgradeBarracudaWAFDeprecatedParentState(attributes ...string) schema.StateUpgradeFunc {
+	upgrade := upgradeBarracudaWAFParentState(attributes...)
+
+	return func(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+		parent := rawState["parent"]
+
+		upgraded, err := upgrade(ctx, rawState, meta)
+		if err != nil {
+			return nil, err
+		}
+
+		upgraded["parent"] = parent
+		return upgraded, nil
+	}
+}
+
+// setBarracudaWAFServiceNameFromParent : plans service_name from the deprecated "parent" list
+// when it is configured instead, so that the resources only deal with service_name.
+func setBarracudaWAFServiceNameFromParent(d *schema.ResourceDiff) error {
+	if config := d.GetRawConfig(); !config.IsNull() && config.IsKnown() {
+		parent := config.GetAttr("parent")
+		if parent.IsNull() {
+			return nil
+		}
+
+		if !parent.IsWhollyKnown() {
+			return d.SetNewComputed("service_name")
+		}
+	}
+
+	if !d.NewValueKnown("parent") || !d.HasChange("parent") {
+		return nil
+	}
+
+	parent, _ := d.Get("parent").([]interface{})
+	if len(parent) == 0 {
+		return nil
+	}
+
+	return d.SetNew("service_name", parent[0])
+}
+
+// setBarracudaWAFDeprecatedParent : keeps the deprecated "parent" list of the state, when it is
+// used, in line with the name of the service.
+func setBarracudaWAFDeprecatedParent(d *schema.ResourceData, service string) error {
+	if parent, _ := d.Get("parent").([]interface{}); len(parent) == 0 {
+		return nil
+	}
+
+	return d.Set("parent", []interface{}{service})
+}
+
+// upgradeBarracudaWAFIdentityState : upgrades version 0 states which are valid as they are,
+// the attributes added since then are read back from the WAF on the next refresh.
+func upgradeBarracudaWAFIdentityState(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	return rawState, nil
+}
+
+// upgradeBarracudaWAFSecretState : returns the state upgrade function replacing the key
+// material held in clear by version 0 states with its hash, see hashBarracudaWAFSecret.
+func upgradeBarracudaWAFSecretState(attributes ...string) schema.StateUpgradeFunc {
+	return func(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+		for _, attribute := range attributes {
+			if value, ok := rawState[attribute].(string); ok {
+				rawState[attribute] = hashBarracudaWAFSecret(value)
+			}
+		}
+
+		return rawState, nil
+	}
+}
+
+// forceNewIfBarracudaWAFParentChanges : forces a new resource when the attributes naming its
+// parent change, objects cannot be moved to another parent. The resource is only updated in
+// place when its old parent is renamed, the WAF renames the children along with it: either
+// the parent resource announced the rename in the same plan, or the old parent is gone.
+func forceNewIfBarracudaWAFParentChanges(
+	ctx context.Context,
+	d *schema.ResourceDiff,
+	m interface{},
+	attributes []string,
+	oldParentPath string,
+	newParentPath string,
+	getOldParent func(ctx context.Context) error,
+) (bool, error) {
+	var changed []string
+	for _, attribute := range attributes {
+		if d.HasChange(attribute) {
+			changed = append(changed, attribute)
+		}
+	}
+
+	if d.Id() == "" || len(changed) == 0 {
+		return false, nil
+	}
+
+	for _, attribute := range changed {
+		if !d.NewValueKnown(attribute) {
+			return true, forceNewBarracudaWAFAttributes(d, changed)
+		}
+	}
+
+	if m.(*waf.Client).IsRenameExpected(oldParentPath, newParentPath) {
+		log.Printf("[INFO] Barracuda WAF resource (%s) moves along with its renamed parent %s", d.Id(), oldParentPath)
+		return false, nil
+	}
+
+	err := getOldParent(ctx)
+
+	if waf.IsNotFound(err) {
+		log.Printf("[INFO] Barracuda WAF resource (%s) moved along with its renamed parent %s", d.Id(), oldParentPath)
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, forceNewBarracudaWAFAttributes(d, changed)
+}
+
+// forceNewBarracudaWAFAttributes : forces a new resource for the changes of the attributes.
+func forceNewBarracudaWAFAttributes(d *schema.ResourceDiff, attributes []string) error {
+	for _, attribute := range attributes {
+		if err := d.ForceNew(attribute); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// expectBarracudaWAFRename : announces the rename of the object planned by the diff of its
+// resource, for the resources of the objects nested under it to be updated in place.
+func expectBarracudaWAFRename(d *schema.ResourceDiff, m interface{}, oldPath string, newPath string) {
+	if d.Id() != "" && d.HasChange("name") && d.NewValueKnown("name") {
+		m.(*waf.Client).ExpectRename(oldPath, newPath)
+	}
+}
+
+// barracudaWAFDiffRequiresNew : reports whether the diff changes one of the attributes of
+// the schema which can only be set when the object is created.
+func barracudaWAFDiffRequiresNew(d *schema.ResourceDiff, resourceSchema map[string]*schema.Schema) bool {
+	for attribute, attributeSchema := range resourceSchema {
+		if attributeSchema.ForceNew && d.HasChange(attribute) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// forceNewIfBarracudaWAFCertificateExpires : forces a new certificate once its expiry is within
+// the early_renewal_hours of the resource, an expired certificate is always replaced. The
+// expiry is planned as unknown so that the replacement shows why it is needed.
+func forceNewIfBarracudaWAFCertificateExpires(d *schema.ResourceDiff) error {
+	expiry := d.Get("expiry").(string)
+
+	if d.Id() == "" || expiry == "" {
+		return nil
+	}
+
+	expiresAt, err := (&waf.Certificate{Name: d.Id(), Expiry: expiry}).ExpiryTime()
+	if err != nil {
+		log.Printf("[WARN] Unable to check the renewal of Barracuda WAF certificate (%s) (%v)", d.Id(), err)
+		return nil
+	}
+
+	renewAt := expiresAt.Add(-time.Duration(d.Get("early_renewal_hours").(int)) * time.Hour)
+	if time.Now().Before(renewAt) {
+		return nil
+	}
+
+	log.Printf("[INFO] Barracuda WAF certificate (%s) expires at %s, replacing it", d.Id(), expiresAt)
+
+	if err := d.SetNewComputed("expiry"); err != nil {
+		return err
+	}
+
+	return d.ForceNew("expiry")
+}
+
+// renameBarracudaWAFResource : moves the ID of the resource to its configured name once the
+// WAF has renamed the object, and returns the name the object is addressed by.
+func renameBarracudaWAFResource(d *schema.ResourceData) string {
+	if d.HasChange("name") {
+		name := d.Get("name").(string)
+		log.Printf("[INFO] Renamed Barracuda WAF resource %s to %s", d.Id(), name)
+		d.SetId(name)
+	}
+
+	return d.Id()
+}
+
+// dataSourceBarracudaWAFSchema : returns a copy of a resource schema with every attribute
+// computed, to be used by data sources exposing the same object.
+func dataSourceBarracudaWAFSchema(resourceSchema map[string]*schema.Schema) map[string]*schema.Schema {
+	dataSourceSchema := make(map[string]*schema.Schema, len(resourceSchema))
+
+	for attribute, attributeSchema := range resourceSchema {
+		dataSourceSchema[attribute] = &schema.Schema{
+			Type:        attributeSchema.Type,
+			Computed:    true,
+			Sensitive:   attributeSchema.Sensitive,
+			Description: attributeSchema.Description,
+		}
+
+		switch elem := attributeSchema.Elem.(type) {
+		case *schema.Schema:
+			dataSourceSchema[attribute].Elem = &schema.Schema{Type: elem.Type}
+		case *schema.Resource:
+			dataSourceSchema[attribute].Elem = &schema.Resource{
+				Schema: dataSourceBarracudaWAFSchema(elem.Schema),
+			}
+		}
+	}
+
+	return dataSourceSchema
+}
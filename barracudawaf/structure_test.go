@@ -0,0 +1,52 @@
+package barracudawaf
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestExpandBarracudaWAFSubResource(t *testing.T) {
+	resourceSchema := map[string]*schema.Schema{
+		"block": {
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"status":    {Type: schema.TypeString, Optional: true},
+					"comments":  {Type: schema.TypeString, Optional: true},
+					"retries":   {Type: schema.TypeInt, Optional: true},
+					"enabled":   {Type: schema.TypeBool, Optional: true},
+					"addresses": {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+				},
+			},
+		},
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceSchema, map[string]interface{}{
+		"block": []interface{}{
+			map[string]interface{}{"status": "On", "retries": 0, "enabled": false},
+		},
+	})
+
+	var object struct {
+		Status    string   `json:"status,omitempty"`
+		Comments  *string  `json:"comments,omitempty"`
+		Retries   *int     `json:"retries,omitempty"`
+		Enabled   *bool    `json:"enabled,omitempty"`
+		Addresses []string `json:"addresses,omitempty"`
+	}
+
+	if !expandBarracudaWAFSubResource(d, "block", &object) {
+		t.Fatalf("expected the block to be configured")
+	}
+
+	if object.Status != "On" || object.Comments != nil || object.Addresses != nil {
+		t.Errorf("expected only the non-empty strings and lists to be set, got %+v", object)
+	}
+
+	if object.Retries == nil || object.Enabled == nil || *object.Retries != 0 || *object.Enabled {
+		t.Errorf("expected the numbers and booleans to be set, got %+v", object)
+	}
+}
@@ -0,0 +1,83 @@
+package barracudawaf
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var (
+	// validateBarracudaWAFYesNo : switches the WAF encodes as "Yes" or "No"
+	validateBarracudaWAFYesNo = validation.StringInSlice([]string{"Yes", "No"}, false)
+
+	// validateBarracudaWAFOnOff : switches the WAF encodes as "On" or "Off"
+	validateBarracudaWAFOnOff = validation.StringInSlice([]string{"On", "Off"}, false)
+
+	// validateBarracudaWAFAddressVersion : IP version of the addresses of services and servers
+	validateBarracudaWAFAddressVersion = validation.StringInSlice([]string{"IPv4", "IPv6"}, false)
+
+	// validateBarracudaWAFIPAddress : IPv4 or IPv6 address
+	validateBarracudaWAFIPAddress = validation.IsIPAddress
+
+	// validateBarracudaWAFPort : TCP port
+	validateBarracudaWAFPort = validateBarracudaWAFIntString(1, 65535)
+
+	// validateBarracudaWAFMode : modes of the web firewall of services and content rules
+	validateBarracudaWAFMode = validation.StringInSlice([]string{"Active", "Passive"}, false)
+
+	// validateBarracudaWAFServiceType : types of services
+	validateBarracudaWAFServiceType = validation.StringInSlice([]string{
+		"HTTP",
+		"HTTPS",
+		"Instant SSL",
+		"Redirect Service",
+		"Custom",
+		"Custom SSL",
+		"FTP",
+		"FTP SSL",
+	}, false)
+
+	// validateBarracudaWAFServerIdentifier : attributes identifying back-end servers
+	validateBarracudaWAFServerIdentifier = validation.StringInSlice([]string{"IP Address", "Hostname"}, false)
+
+	// validateBarracudaWAFServerStatus : operational states of back-end servers
+	validateBarracudaWAFServerStatus = validation.StringInSlice([]string{
+		"In Service",
+		"Out of Service Maintenance",
+		"Out of Service Sticky",
+		"Out of Service All",
+	}, false)
+
+	// validateBarracudaWAFCountryCode : two letter country codes of the subjects of certificates
+	validateBarracudaWAFCountryCode = validation.StringLenBetween(2, 2)
+
+	// validateBarracudaWAFKeyType : types of the keys of certificates, either case is accepted
+	validateBarracudaWAFKeyType = validation.StringInSlice([]string{"rsa", "ecdsa"}, true)
+
+	// validateBarracudaWAFKeySize : sizes of the RSA keys of certificates
+	validateBarracudaWAFKeySize = validation.StringInSlice([]string{"1024", "2048", "4096"}, false)
+)
+
+// validateBarracudaWAFIntString : returns a validation function for the integers the WAF
+// encodes as strings, e.g. ports and timeouts, between min and max inclusive.
+func validateBarracudaWAFIntString(min int, max int) schema.SchemaValidateFunc {
+	return func(i interface{}, k string) ([]string, []error) {
+		v, ok := i.(string)
+		if !ok {
+			return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+		}
+
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, []error{fmt.Errorf("expected %s to be an integer, got %q", k, v)}
+		}
+
+		if n < min || n > max {
+			return nil, []error{fmt.Errorf("expected %s to be in the range (%d - %d), got %d", k, min, max, n)}
+		}
+
+		return nil, nil
+	}
+}
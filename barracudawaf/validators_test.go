@@ -0,0 +1,92 @@
+package barracudawaf
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestValidateBarracudaWAFIntString(t *testing.T) {
+	validate := validateBarracudaWAFIntString(1, 65535)
+
+	cases := []struct {
+		value interface{}
+		valid bool
+	}{
+		{value: "80", valid: true},
+		{value: "65535", valid: true},
+		{value: "0", valid: false},
+		{value: "65536", valid: false},
+		{value: "http", valid: false},
+		{value: 80, valid: false},
+	}
+
+	for _, c := range cases {
+		_, errs := validate(c.value, "port")
+		if valid := len(errs) == 0; valid != c.valid {
+			t.Errorf("%v: expected valid to be %t, got errors %v", c.value, c.valid, errs)
+		}
+	}
+}
+
+func TestBarracudaWAFService_invalidConfig(t *testing.T) {
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":            "DemoApp1",
+		"ip_address":      "10.0.0.300",
+		"port":            "eighty",
+		"type":            "HTTP",
+		"address_version": "IPv4",
+		"status":          "Yes",
+		"ssl_security": []interface{}{
+			map[string]interface{}{
+				"enable_tls_1_2": "true",
+				"hsts_max_age":   "one year",
+			},
+		},
+	})
+
+	diags := resourceCudaWAFServices().Validate(config)
+
+	var invalid []string
+	for _, d := range diags {
+		invalid = append(invalid, d.Summary)
+	}
+
+	for _, attribute := range []string{"ip_address", "port", "status", "enable_tls_1_2", "hsts_max_age"} {
+		found := false
+		for _, summary := range invalid {
+			found = found || strings.Contains(summary, attribute)
+		}
+
+		if !found {
+			t.Errorf("expected %s to be rejected, got %v", attribute, invalid)
+		}
+	}
+
+	if len(diags) != 5 {
+		t.Errorf("expected 5 invalid attributes, got %v", invalid)
+	}
+}
+
+func TestBarracudaWAFServer_validConfig(t *testing.T) {
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":            "DemoServer1",
+		"identifier":      "IP Address",
+		"address_version": "IPv4",
+		"status":          "In Service",
+		"ip_address":      "10.0.0.10",
+		"port":            "80",
+		"service_name":    "DemoApp1",
+		"connection_pooling": []interface{}{
+			map[string]interface{}{
+				"enable_connection_pooling": "Yes",
+				"keepalive_timeout":         "900000",
+			},
+		},
+	})
+
+	if diags := resourceCudaWAFServers().Validate(config); diags.HasError() {
+		t.Errorf("unexpected validation errors: %v", diags)
+	}
+}
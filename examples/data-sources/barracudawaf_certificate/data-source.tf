@@ -0,0 +1,4 @@
+data "barracudawaf_certificate" "demo_cert" {
+    name = "DemoSelfSignedCert"
+    type = "self_signed"
+}
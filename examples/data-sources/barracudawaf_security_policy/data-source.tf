@@ -0,0 +1,3 @@
+data "barracudawaf_security_policy" "default" {
+    name = "default"
+}
@@ -0,0 +1,4 @@
+data "barracudawaf_servers" "demo_app_1" {
+    service = "DemoApp1"
+    status  = "In Service"
+}
@@ -0,0 +1,8 @@
+data "barracudawaf_service" "demo_app_1" {
+    name = "DemoApp1"
+}
+
+data "barracudawaf_service" "partner_portal" {
+    vsite = "Partners"
+    type  = "HTTPS"
+}
@@ -0,0 +1,5 @@
+data "barracudawaf_services" "http" {
+    vsite = "default"
+    group = "default"
+    type  = "HTTP"
+}
@@ -56,7 +56,7 @@ resource "barracudawaf_servers" "demo_server_1" {
     ip_address      = "x.x.x.x"
     port            = "80"
     comments        = "Creating the Demo Server"
-    parent          = [ barracudawaf_services.demo_app_1.name ]
+    service_name    = barracudawaf_services.demo_app_1.name
 
     depends_on      = [ barracudawaf_services.demo_app_1 ]
 }
@@ -88,7 +88,7 @@ resource "barracudawaf_servers" "demo_server_2" {
     ip_address      = "x.x.x.x"
     port            = "80"
     comments        = "Creating the Demo Server"
-    parent          = [ barracudawaf_services.demo_app_2.name ]
+    service_name    = barracudawaf_services.demo_app_2.name
 
     depends_on = [ barracudawaf_services.demo_app_2 ]
 }
@@ -106,16 +106,17 @@ resource "barracudawaf_content_rules" "demo_rule_group_1" {
     host_match          = "www.example.com"
     web_firewall_policy = "DemoPolicy1"
     mode                = "Active"
-    parent              = [ barracudawaf_services.demo_app_1.name ]
+    service_name        = barracudawaf_services.demo_app_1.name
     
     depends_on          = [ barracudawaf_security_policies.demo_security_policy_1 ]
 }
  
 resource "barracudawaf_content_rule_servers" "demo_rule_group_server_1" {
-    name        = "DemoRuleGroupServer1"
-    identifier  = "Hostname"
-    hostname    = "barracuda.com"
-    parent      = [ barracudawaf_services.demo_app_1.name, barracudawaf_content_rules.demo_rule_group_1.name ]
+    name              = "DemoRuleGroupServer1"
+    identifier        = "Hostname"
+    hostname          = "barracuda.com"
+    service_name      = barracudawaf_services.demo_app_1.name
+    content_rule_name = barracudawaf_content_rules.demo_rule_group_1.name
 
     depends_on = [ barracudawaf_content_rules.demo_rule_group_1 ]
 }
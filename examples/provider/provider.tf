@@ -8,4 +8,7 @@ provider "barracudawaf" {
     username = "xxxxxxx"
     port     = "8443"
     password = "xxxxxxx"
+
+    # verify the admin API certificate of the WAF against this CA bundle
+    ca_cert_file = "/etc/ssl/certs/waf-admin-ca.pem"
 }
@@ -0,0 +1,2 @@
+# import using the name of the certificate
+terraform import barracudawaf_trusted_ca_certificate.demo_trusted_ca_cert DemoTrustedCACert
@@ -0,0 +1,2 @@
+# import using the name of the signed certificate
+terraform import barracudawaf_certificate_chain.demo_chain DemoSignedCert
@@ -0,0 +1,4 @@
+resource "barracudawaf_certificate_chain" "demo_chain" {
+    certificate_name = barracudawaf_signed_certificate.demo_signed_cert.name
+    certificates     = file("certificates/chain.pem")
+}
@@ -0,0 +1,2 @@
+# import using the name of the certificate signing request
+terraform import barracudawaf_certificate_signing_request.demo_csr DemoCSR
@@ -0,0 +1,25 @@
+resource "barracudawaf_certificate_signing_request" "demo_csr" {
+    name              = "DemoCSR"
+    common_name       = "www.example.com"
+    country_code      = "US"
+    organization_name = "xxxxxx"
+    key_type          = "rsa"
+    key_size          = "2048"
+    san_certificate   = [ "DNS:example.com" ]
+}
+
+resource "tls_locally_signed_cert" "demo_cert" {
+    cert_request_pem      = barracudawaf_certificate_signing_request.demo_csr.certificate_request_pem
+    ca_key_algorithm      = "RSA"
+    ca_private_key_pem    = file("ca/key.pem")
+    ca_cert_pem           = file("ca/cert.pem")
+    validity_period_hours = 8760
+    allowed_uses          = [ "key_encipherment", "digital_signature", "server_auth" ]
+}
+
+resource "barracudawaf_signed_certificate" "demo_signed_cert" {
+    name                      = "DemoSignedCert"
+    signed_certificate        = tls_locally_signed_cert.demo_cert.cert_pem
+    certificate_request_name  = barracudawaf_certificate_signing_request.demo_csr.name
+    intermediary_certificates = [ file("ca/cert.pem") ]
+}
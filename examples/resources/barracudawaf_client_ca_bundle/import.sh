@@ -0,0 +1,2 @@
+# import using <service>/<bundle>, or <bundle> alone
+terraform import barracudawaf_client_ca_bundle.demo_client_cas DemoApp1/DemoClientCAs
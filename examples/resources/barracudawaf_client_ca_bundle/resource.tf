@@ -0,0 +1,5 @@
+resource "barracudawaf_client_ca_bundle" "demo_client_cas" {
+    name         = "DemoClientCAs"
+    certificates = file("certificates/client-cas.pem")
+    service_name = barracudawaf_services.demo_app_1.name
+}
@@ -0,0 +1,2 @@
+# import using <service>/<content_rule>/<server>
+terraform import barracudawaf_content_rule_servers.demo_rule_group_server_1 DemoApp1/DemoRuleGroup1/DemoRuleGroupServer1
@@ -0,0 +1,2 @@
+# import using <service>/<content_rule>
+terraform import barracudawaf_content_rules.demo_rule_group_1 DemoApp1/DemoRuleGroup1
@@ -0,0 +1,2 @@
+# import using the name of the certificate
+terraform import barracudawaf_letsencrypt_certificate.demo_letsencrypt_cert DemoLetsEncryptCert
@@ -4,6 +4,7 @@ resource "barracudawaf_letsencrypt_certificate" "demo_letsencrypt_cert" {
     allow_private_key_export   = "Yes"
     auto_renew_cert            = "Yes"
     schedule_renewal_day       = "60"
+    early_renewal_hours        = 168
 
     multi_cert_trusted_service = barracudawaf_services.application_1.name
     depends_on = [ barracudawaf_xxxx.xxxx ]
@@ -0,0 +1,2 @@
+# import using the name of the security policy
+terraform import barracudawaf_security_policies.demo_security_policy_1 DemoPolicy1
@@ -0,0 +1,2 @@
+# import using the name of the certificate
+terraform import barracudawaf_self_signed_certificate.demo_self_signed_cert DemoSelfSignedCert
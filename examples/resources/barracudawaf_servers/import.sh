@@ -0,0 +1,2 @@
+# import using <service>/<server>
+terraform import barracudawaf_servers.demo_server_1 DemoApp1/DemoServer1
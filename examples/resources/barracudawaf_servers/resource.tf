@@ -6,7 +6,7 @@ resource "barracudawaf_servers" "demo_server_1" {
     ip_address      = "x.x.x.x"
     port            = "80"
     comments        = "Creating the Demo Server"
-    parent          = [ barracudawaf_services.demo_app_1.name ]
+    service_name    = barracudawaf_services.demo_app_1.name
 
     depends_on      = [ barracudawaf_services.demo_app_2 ]
 }
\ No newline at end of file
@@ -0,0 +1,2 @@
+# import using the name of the service
+terraform import barracudawaf_services.demo_app_1 DemoApp1
@@ -0,0 +1,2 @@
+# import using the name of the certificate
+terraform import barracudawaf_signed_certificate.demo_signed_cert DemoSignedCert
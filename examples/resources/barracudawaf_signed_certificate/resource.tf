@@ -1,14 +1,10 @@
-resource "barracudawaf_self_signed_certificate" "demo_self_signed_cert_1" {
-    name                     = "DemoSelfSignedCert1"
+resource "barracudawaf_signed_certificate" "demo_signed_cert" {
+    name                     = "DemoSignedCert"
+    assign_associated_key    = "Yes"
+    signed_certificate_file  = "certificates/fullchain.pem"
+    certificate_key_file     = "certificates/privkey.pem"
     allow_private_key_export = "Yes"
-    city                     = "xxxxxx"
-    common_name              = "xxxxxx"
-    country_code             = "xx"
-    key_size                 = "1024"
-    key_type                 = "rsa"
-    organization_name        = "xxxxxx"
-    organizational_unit      = "xxxxxx"
-    state                    = "xxxxxx"
-    
-    depends_on               = [ barracudawaf_xxxx.xxxx ]
+    early_renewal_hours      = 720
+  
+    depends_on  = [ barracudawaf_xxxx.xxxx ]
 }
\ No newline at end of file
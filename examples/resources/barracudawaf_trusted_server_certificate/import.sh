@@ -0,0 +1,2 @@
+# import using the name of the certificate
+terraform import barracudawaf_trusted_server_certificate.demo_trusted_server_cert DemoTrustedServerCert
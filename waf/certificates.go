@@ -0,0 +1,170 @@
+package waf
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// CertificateStore : store of the certificates of the WAF.
+type CertificateStore string
+
+const (
+	SignedCertificates        CertificateStore = "signed-certificate"         // uploaded and Let's Encrypt certificates
+	SelfSignedCertificates    CertificateStore = "self-signed-certificate"    // certificates generated by the WAF
+	CertificateRequests       CertificateStore = "certificate-request"        // keys generated by the WAF pending their signed certificate
+	TrustedCACertificates     CertificateStore = "trusted-ca-certificate"     // CAs trusted for client certificates
+	TrustedServerCertificates CertificateStore = "trusted-server-certificate" // certificates trusted for back-end servers
+)
+
+// Certificate : certificate stored on the WAF. The parameters used depend on the store and on
+// how the certificate is created, the private key and passwords are only sent when uploading.
+type Certificate struct {
+	Name                     string   `json:"name,omitempty"`
+	CommonName               string   `json:"common-name,omitempty"`
+	Expiry                   string   `json:"expiry,omitempty"`
+	Issuer                   string   `json:"issuer,omitempty"`
+	Subject                  string   `json:"subject,omitempty"`
+	SHA256Fingerprint        string   `json:"sha256-fingerprint,omitempty"`
+	Serial                   string   `json:"serial,omitempty"`
+	KeyType                  string   `json:"key-type,omitempty"`
+	KeySize                  string   `json:"key-size,omitempty"`
+	EllipticCurveName        string   `json:"elliptic-curve-name,omitempty"`
+	AllowPrivateKeyExport    string   `json:"allow-private-key-export,omitempty"`
+	AutoRenewCert            string   `json:"auto-renew-cert,omitempty"`
+	ScheduleRenewalDay       string   `json:"schedule-renewal-day,omitempty"`
+	DownloadType             string   `json:"download-type,omitempty"`
+	EncryptPassword          string   `json:"encrypt-password,omitempty"`
+	CertificateType          string   `json:"certificate-type,omitempty"`
+	AssignAssociatedKey      string   `json:"assign-associated-key,omitempty"`
+	SignedCertificate        string   `json:"signed-certificate,omitempty"`
+	CertificateKey           string   `json:"certificate-key,omitempty"`
+	CertificatePassword      string   `json:"certificate-password,omitempty"`
+	IntermediaryCertificates []string `json:"intermediary-certificates,omitempty"`
+	Certificate              string   `json:"certificate,omitempty"`
+	CertificateRequest       string   `json:"certificate-request,omitempty"` // PEM CSR of a pending key
+	City                     string   `json:"city,omitempty"`
+	CountryCode              string   `json:"country-code,omitempty"`
+	State                    string   `json:"state,omitempty"`
+	OrganizationName         string   `json:"organization-name,omitempty"`
+	OrganizationalUnit       string   `json:"organizational-unit,omitempty"`
+	SANCertificate           []string `json:"san-certificate,omitempty"`
+	SANCert                  []string `json:"san-cert,omitempty"`
+	MultiCertTrustedService  string   `json:"multi-cert-trusted-service,omitempty"`
+}
+
+// expiryLayouts : formats of the expiry dates reported by the WAF
+var expiryLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"Jan _2 15:04:05 2006 MST",
+	"Mon Jan _2 15:04:05 2006",
+	"2006-01-02",
+}
+
+// ExpiryTime : parses the expiry date of the certificate, dates without a time zone are UTC.
+func (c *Certificate) ExpiryTime() (time.Time, error) {
+	for _, layout := range expiryLayouts {
+		if expiry, err := time.Parse(layout, c.Expiry); err == nil {
+			return expiry, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unexpected expiry (%s) of certificate %s", c.Expiry, c.Name)
+}
+
+// certificatesPath : endpoint of a certificate store
+func certificatesPath(store CertificateStore) string {
+	return objectPath(string(store))
+}
+
+// ListCertificates : returns the certificates of the store sorted by name.
+func (c *Client) ListCertificates(ctx context.Context, store CertificateStore) ([]Certificate, error) {
+	var certificates []Certificate
+	err := c.listObjects(ctx, certificatesPath(store), &certificates)
+
+	return certificates, err
+}
+
+// GetCertificate : returns the named certificate of the store.
+func (c *Client) GetCertificate(ctx context.Context, store CertificateStore, name string) (*Certificate, error) {
+	var certificate Certificate
+	if err := c.getObject(ctx, certificatesPath(store), "certificate", name, &certificate); err != nil {
+		return nil, err
+	}
+
+	return &certificate, nil
+}
+
+// CreateCertificate : uploads a certificate to the store, or generates it for the self
+// signed certificates.
+func (c *Client) CreateCertificate(ctx context.Context, store CertificateStore, certificate *Certificate) error {
+	return c.Do(ctx, http.MethodPost, certificatesPath(store), certificate, nil)
+}
+
+// letsEncryptPath : endpoint of the certificates issued by Let's Encrypt
+var letsEncryptPath = objectPath("certificates", "letsencrypt")
+
+// GetLetsEncryptCertificate : returns the named certificate issued by Let's Encrypt.
+func (c *Client) GetLetsEncryptCertificate(ctx context.Context, name string) (*Certificate, error) {
+	var certificate Certificate
+	if err := c.getObject(ctx, letsEncryptPath, "certificate", name, &certificate); err != nil {
+		return nil, err
+	}
+
+	return &certificate, nil
+}
+
+// CreateLetsEncryptCertificate : requests a certificate from Let's Encrypt for the common name,
+// it is stored with the signed certificates.
+func (c *Client) CreateLetsEncryptCertificate(ctx context.Context, certificate *Certificate) error {
+	return c.Do(ctx, http.MethodPost, letsEncryptPath, certificate, nil)
+}
+
+// UpdateLetsEncryptCertificate : updates the renewal settings of the named certificate issued
+// by Let's Encrypt, the other parameters are not sent.
+func (c *Client) UpdateLetsEncryptCertificate(ctx context.Context, name string, certificate *Certificate) error {
+	update := &Certificate{
+		AutoRenewCert:      certificate.AutoRenewCert,
+		ScheduleRenewalDay: certificate.ScheduleRenewalDay,
+	}
+
+	return c.Do(ctx, http.MethodPut, letsEncryptPath+objectPath(name), update, nil)
+}
+
+// DeleteLetsEncryptCertificate : deletes the named certificate issued by Let's Encrypt.
+func (c *Client) DeleteLetsEncryptCertificate(ctx context.Context, name string) error {
+	return c.Do(ctx, http.MethodDelete, letsEncryptPath+objectPath(name), nil, nil)
+}
+
+// UpdateCertificate : updates the named certificate of the store. Only the download and
+// renewal settings can be changed, the other parameters are not sent. The intermediate
+// certificates are replaced by UpdateCertificateChain.
+func (c *Client) UpdateCertificate(ctx context.Context, store CertificateStore, name string, certificate *Certificate) error {
+	update := &Certificate{
+		DownloadType:       certificate.DownloadType,
+		EncryptPassword:    certificate.EncryptPassword,
+		AutoRenewCert:      certificate.AutoRenewCert,
+		ScheduleRenewalDay: certificate.ScheduleRenewalDay,
+	}
+
+	return c.Do(ctx, http.MethodPut, certificatesPath(store)+objectPath(name), update, nil)
+}
+
+// UpdateCertificateChain : replaces the intermediate certificates of the named certificate of
+// the store, an empty chain removes them.
+func (c *Client) UpdateCertificateChain(ctx context.Context, store CertificateStore, name string, intermediates []string) error {
+	update := struct {
+		IntermediaryCertificates []string `json:"intermediary-certificates"`
+	}{
+		IntermediaryCertificates: append([]string{}, intermediates...),
+	}
+
+	return c.Do(ctx, http.MethodPut, certificatesPath(store)+objectPath(name), update, nil)
+}
+
+// DeleteCertificate : deletes the named certificate of the store.
+func (c *Client) DeleteCertificate(ctx context.Context, store CertificateStore, name string) error {
+	return c.Do(ctx, http.MethodDelete, certificatesPath(store)+objectPath(name), nil, nil)
+}
@@ -0,0 +1,143 @@
+package waf
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClient_certificates(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+
+	certificate := &Certificate{
+		Name:                "DemoSignedCert",
+		SignedCertificate:   "MIIG2QIBAzCCBo8GCSqGSIb3DQEHAaCCBoAEggZ8",
+		CertificateType:     "PKCS12 Token",
+		CertificatePassword: "secret@123",
+	}
+
+	if err := client.CreateCertificate(ctx, SignedCertificates, certificate); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	certificate.ScheduleRenewalDay = "30"
+	if err := client.UpdateCertificate(ctx, SignedCertificates, "DemoSignedCert", certificate); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	body := server.LastRequest(http.MethodPut, "/signed-certificate/DemoSignedCert").Body
+	if !reflect.DeepEqual(body, map[string]interface{}{"schedule-renewal-day": "30"}) {
+		t.Errorf("update: expected only the renewal settings to be sent, got %v", body)
+	}
+
+	if got, err := client.GetCertificate(ctx, SignedCertificates, "DemoSignedCert"); err != nil || got.ScheduleRenewalDay != "30" {
+		t.Errorf("get: unexpected result %+v (%v)", got, err)
+	}
+
+	if _, err := client.GetCertificate(ctx, TrustedCACertificates, "DemoSignedCert"); !IsNotFound(err) {
+		t.Errorf("get: expected the certificate not to be found in another store, got %v", err)
+	}
+
+	if err := client.DeleteCertificate(ctx, SignedCertificates, "DemoSignedCert"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	if certificates, err := client.ListCertificates(ctx, SignedCertificates); err != nil || len(certificates) != 0 {
+		t.Errorf("list: expected no certificates, got %+v (%v)", certificates, err)
+	}
+}
+
+func TestClient_certificateChain(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+
+	server.SetObject("signed-certificate/DemoSignedCert", map[string]interface{}{"certificate-type": "PEM Certificate"})
+
+	if err := client.UpdateCertificateChain(ctx, SignedCertificates, "DemoSignedCert", []string{"LS0tLS1CRUdJTi"}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	if got, err := client.GetCertificate(ctx, SignedCertificates, "DemoSignedCert"); err != nil || len(got.IntermediaryCertificates) != 1 {
+		t.Errorf("get: unexpected result %+v (%v)", got, err)
+	}
+
+	if err := client.UpdateCertificateChain(ctx, SignedCertificates, "DemoSignedCert", nil); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	body := server.LastRequest(http.MethodPut, "/signed-certificate/DemoSignedCert").Body
+	if !reflect.DeepEqual(body, map[string]interface{}{"intermediary-certificates": []interface{}{}}) {
+		t.Errorf("update: expected only the emptied chain to be sent, got %v", body)
+	}
+}
+
+func TestClient_letsEncryptCertificates(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+
+	certificate := &Certificate{
+		Name:                    "DemoLetsEncryptCert",
+		CommonName:              "www.example.com",
+		MultiCertTrustedService: "DemoApp1",
+		SANCert:                 []string{"example.com"},
+	}
+
+	if err := client.CreateLetsEncryptCertificate(ctx, certificate); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	if body := server.LastRequest(http.MethodPost, "/certificates/letsencrypt").Body; body["san-cert"] == nil {
+		t.Errorf("create: unexpected payload %v", body)
+	}
+
+	certificate.AutoRenewCert = "Yes"
+	certificate.ScheduleRenewalDay = "30"
+	if err := client.UpdateLetsEncryptCertificate(ctx, "DemoLetsEncryptCert", certificate); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	body := server.LastRequest(http.MethodPut, "/certificates/letsencrypt/DemoLetsEncryptCert").Body
+	if !reflect.DeepEqual(body, map[string]interface{}{"auto-renew-cert": "Yes", "schedule-renewal-day": "30"}) {
+		t.Errorf("update: expected only the renewal settings to be sent, got %v", body)
+	}
+
+	got, err := client.GetLetsEncryptCertificate(ctx, "DemoLetsEncryptCert")
+	if err != nil || got.CommonName != "www.example.com" || got.ScheduleRenewalDay != "30" {
+		t.Errorf("get: unexpected result %+v (%v)", got, err)
+	}
+
+	server.LastRequest(http.MethodGet, "/certificates/letsencrypt")
+
+	if err := client.DeleteLetsEncryptCertificate(ctx, "DemoLetsEncryptCert"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	if _, err := client.GetLetsEncryptCertificate(ctx, "DemoLetsEncryptCert"); !IsNotFound(err) {
+		t.Errorf("get: expected the deleted certificate not to be found, got %v", err)
+	}
+}
+
+func TestCertificate_ExpiryTime(t *testing.T) {
+	expected := time.Date(2027, time.January, 17, 12, 30, 0, 0, time.UTC)
+
+	for _, expiry := range []string{
+		"2027-01-17T12:30:00Z",
+		"2027-01-17 12:30:00",
+		"Jan 17 12:30:00 2027 GMT",
+		"Sun Jan 17 12:30:00 2027",
+	} {
+		certificate := &Certificate{Name: "DemoCert", Expiry: expiry}
+
+		got, err := certificate.ExpiryTime()
+		if err != nil || !got.Equal(expected) {
+			t.Errorf("%s: expected %s, got %s (%v)", expiry, expected, got, err)
+		}
+	}
+
+	if _, err := (&Certificate{Name: "DemoCert", Expiry: "soon"}).ExpiryTime(); err == nil {
+		t.Errorf("expected an unknown format to be rejected")
+	}
+}
@@ -0,0 +1,358 @@
+// Package waf is a client for the REST API (v3.1) of the Barracuda Web Application Firewall.
+//
+// The Client logs in with the admin credentials, or uses a token obtained beforehand, renews
+// expired sessions and retries requests failing with a transient error. Objects of the WAF
+// are exposed as typed structs with context-aware methods, e.g.
+//
+//	client := waf.NewClient("waf.example.com", "8443", "admin", "secret")
+//	if err := client.Login(ctx); err != nil {
+//		return err
+//	}
+//
+//	err := client.CreateService(ctx, &waf.Service{Name: "DemoApp1", Type: "HTTP", Port: "80"})
+//
+// Empty parameters are left out of the payloads and keep the value of the WAF, except for
+// free-text parameters such as comments and hostnames, which are always sent so that they
+// can be cleared.
+package waf
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	b64 "encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	baseURI = "restapi/v3.1" // baseURI : base endpoint of the REST API
+)
+
+// Client : container for Barracuda WAF session state.
+type Client struct {
+	Host      string
+	User      string
+	Password  string
+	UserAgent string //specifies the caller of the request
+	Transport *http.Transport
+
+	MaxRetries   int           // retries of requests failing with a transient error
+	RetryMinWait time.Duration // wait before the first retry
+	RetryMaxWait time.Duration // upper bound of the wait between retries
+
+	MaxConcurrentRequests int     // requests sent at the same time, 0 for no limit
+	RequestsPerSecond     float64 // rate the requests are sent at, 0 for no limit
+
+	initOnce   sync.Once    // builds httpClient and limiter on the first request
+	httpClient *http.Client // shared by the requests to reuse the connections
+	limiter    *limiter     // bounds the concurrency and the rate of the requests
+
+	parentLocks keyedMutex // serializes the changes of the objects nested under the same parent
+	renames     renames    // renames of objects announced before they are made
+
+	authMutex sync.Mutex // guards token and serializes the re-authentication of expired sessions
+	token     string     // value of the Authorization header of the session
+}
+
+// request : single call to the REST API.
+type request struct {
+	method string
+	path   string
+	body   []byte
+}
+
+// NewClient : Barracuda WAF system connection, the host may include the scheme.
+func NewClient(host, port, user, password string) *Client {
+	var url string
+	if !strings.HasPrefix(host, "http") {
+		url = fmt.Sprintf("https://%s", host)
+	} else {
+		url = host
+	}
+	if port != "" {
+		url = url + ":" + port
+	}
+
+	return &Client{
+		Host:     url,
+		User:     user,
+		Password: password,
+		Transport: &http.Transport{
+			TLSClientConfig:     &tls.Config{},
+			MaxIdleConnsPerHost: DefaultMaxConcurrentRequests,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		MaxRetries:   DefaultMaxRetries,
+		RetryMinWait: DefaultRetryMinWait,
+		RetryMaxWait: DefaultRetryMaxWait,
+
+		MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+	}
+}
+
+// Login : authenticates with the stored credentials and starts a new session.
+func (c *Client) Login(ctx context.Context) error {
+	c.authMutex.Lock()
+	defer c.authMutex.Unlock()
+
+	token, err := c.login(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	c.token = token
+
+	return nil
+}
+
+// SetToken : uses a session or API token obtained outside of the client instead of
+// logging in with the stored credentials.
+func (c *Client) SetToken(token string) *Client {
+	c.authMutex.Lock()
+	defer c.authMutex.Unlock()
+
+	c.token = authorizationHeader(token)
+
+	return c
+}
+
+// login : authenticates with the stored credentials and returns the value of the
+// Authorization header for the new session.
+func (c *Client) login(ctx context.Context) (string, error) {
+	body, err := jsonMarshal(map[string]string{
+		"username": c.User,
+		"password": c.Password,
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	resp, _, err := c.call(ctx, &request{method: http.MethodPost, path: "/login", body: body}, "")
+
+	if err != nil {
+		return "", err
+	}
+
+	var resBody map[string]string
+	if err := json.Unmarshal(resp, &resBody); err != nil {
+		log.Printf("[ERROR] Unable to unmarshal auth token response")
+		return "", err
+	}
+
+	return authorizationHeader(resBody["token"]), nil
+}
+
+// authorizationHeader : returns the value of the Authorization header for a WAF token.
+func authorizationHeader(token string) string {
+	return "BASIC " + b64.StdEncoding.EncodeToString([]byte(token+":"))
+}
+
+// authToken : returns the value of the Authorization header for the current session.
+func (c *Client) authToken() string {
+	c.authMutex.Lock()
+	defer c.authMutex.Unlock()
+
+	return c.token
+}
+
+// reauthenticate : renews the session which was rejected with the expired token. Concurrent
+// callers are serialized and only the first one logs in again, the others reuse its session.
+func (c *Client) reauthenticate(ctx context.Context, expiredToken string) error {
+	c.authMutex.Lock()
+	defer c.authMutex.Unlock()
+
+	if c.token != expiredToken {
+		return nil
+	}
+
+	log.Printf("[INFO] Barracuda WAF session expired, re-authenticating as %s", c.User)
+
+	token, err := c.login(ctx)
+
+	if err != nil {
+		return fmt.Errorf("unable to renew the expired Barracuda WAF session: %w", err)
+	}
+
+	c.token = token
+
+	return nil
+}
+
+// Do : sends a request to the REST API, the path is relative to the API base, e.g.
+// "/services". The body is encoded as JSON and the response decoded into out, when set.
+// Requests failing with a transient error are retried with an exponential backoff. Changes
+// of the objects nested under the same parent, e.g. the servers of a service, are sent one
+// at a time as the WAF rejects concurrent changes of a service.
+func (c *Client) Do(ctx context.Context, method string, path string, body interface{}, out interface{}) error {
+	req := &request{method: strings.ToUpper(method), path: path}
+
+	if key := parentKey(req.method, req.path); key != "" && !holdsParent(ctx, key) {
+		unlock, err := c.parentLocks.lock(ctx, key)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
+	if body != nil {
+		data, err := jsonMarshal(body)
+		if err != nil {
+			return err
+		}
+		req.body = data
+	}
+
+	resp, err := c.retry(ctx, req, func() ([]byte, int, error) {
+		return c.authenticatedCall(ctx, req)
+	})
+
+	if err != nil || out == nil || len(resp) == 0 {
+		return err
+	}
+
+	if err := json.Unmarshal(resp, out); err != nil {
+		return fmt.Errorf("unable to decode the response of %s %s: %w", req.method, req.path, err)
+	}
+
+	return nil
+}
+
+// authenticatedCall : sends the request with the session token. Requests rejected because
+// the session has expired are sent once more after logging in again with the stored credentials.
+func (c *Client) authenticatedCall(ctx context.Context, req *request) ([]byte, int, error) {
+	token := c.authToken()
+
+	data, statusCode, err := c.call(ctx, req, token)
+
+	if c.isSessionExpired(req, err) {
+		if authErr := c.reauthenticate(ctx, token); authErr != nil {
+			return data, statusCode, authErr
+		}
+
+		data, statusCode, err = c.call(ctx, req, c.authToken())
+	}
+
+	return data, statusCode, err
+}
+
+// call : sends a single request to the REST API and returns the response body along
+// with the HTTP status code.
+func (c *Client) call(ctx context.Context, req *request, token string) ([]byte, int, error) {
+	c.init()
+
+	url := fmt.Sprintf("%s/%s%s", c.Host, baseURI, req.path)
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, url, bytes.NewReader(req.body))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if token != "" {
+		httpReq.Header.Set("Authorization", token)
+	}
+
+	if req.body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+
+	if c.UserAgent != "" {
+		httpReq.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	release, err := c.limiter.acquire(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer release()
+
+	logCtx := c.logContext(ctx)
+	logRequest(logCtx, httpReq, req.body)
+	start := time.Now()
+
+	res, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, 0, checkTLSError(err)
+	}
+
+	defer res.Body.Close()
+
+	data, _ := ioutil.ReadAll(res.Body)
+
+	logResponse(logCtx, httpReq, res.StatusCode, time.Since(start), data)
+
+	if res.StatusCode >= 400 {
+		return data, res.StatusCode, newAPIError(req, res.StatusCode, data)
+	}
+
+	return data, res.StatusCode, nil
+}
+
+// isSessionExpired : reports whether a failed request was rejected because the session
+// token is no longer valid and can be renewed with the stored credentials.
+func (c *Client) isSessionExpired(req *request, err error) bool {
+	var apiErr *APIError
+
+	if req.path == "/login" || c.User == "" || c.Password == "" || !errors.As(err, &apiErr) {
+		return false
+	}
+
+	if apiErr.StatusCode == http.StatusUnauthorized {
+		return true
+	}
+
+	if apiErr.StatusCode >= 500 {
+		return false
+	}
+
+	message := strings.ToLower(apiErr.Message)
+
+	return strings.Contains(message, "token") &&
+		(strings.Contains(message, "expired") || strings.Contains(message, "invalid"))
+}
+
+// checkTLSError : explains how to trust the admin API certificate when its verification failed.
+func checkTLSError(err error) error {
+	if isTLSVerificationError(err) {
+		return fmt.Errorf(
+			"unable to verify the certificate of the Barracuda WAF admin API: %w. "+
+				"Set ca_cert_pem or ca_cert_file to trust the issuer of the certificate, "+
+				"or insecure = true to skip the verification",
+			err,
+		)
+	}
+
+	return err
+}
+
+// isTLSVerificationError : reports whether the error is caused by an untrusted certificate.
+func isTLSVerificationError(err error) bool {
+	var unknownAuthorityError x509.UnknownAuthorityError
+	var hostnameError x509.HostnameError
+	var certificateInvalidError x509.CertificateInvalidError
+
+	return errors.As(err, &unknownAuthorityError) ||
+		errors.As(err, &hostnameError) ||
+		errors.As(err, &certificateInvalidError)
+}
+
+// jsonMarshal specifies an encoder with 'SetEscapeHTML' set to 'false' so that <, >, and & are not escaped.
+// https://golang.org/pkg/encoding/json/#Marshal
+// https://stackoverflow.com/questions/28595664/how-to-stop-json-marshal-from-escaping-and
+func jsonMarshal(t interface{}) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	err := encoder.Encode(t)
+	return bytes.TrimRight(buffer.Bytes(), "\n"), err
+}
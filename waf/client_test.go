@@ -0,0 +1,402 @@
+package waf
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/barracudanetworks/terraform-provider-barracudawaf/waf/waftest"
+)
+
+// newTestClient : starts a fake WAF and returns a client logged in to it with fast retries.
+func newTestClient(t *testing.T) (*Client, *waftest.Server) {
+	server := waftest.NewServer(t)
+
+	client := NewClient(server.URL, "", waftest.Username, waftest.Password)
+	client.RetryMinWait = time.Millisecond
+	client.RetryMaxWait = 5 * time.Millisecond
+
+	if err := client.Login(context.Background()); err != nil {
+		t.Fatalf("unable to log in to the fake WAF: %v", err)
+	}
+
+	return client, server
+}
+
+func TestNewClient(t *testing.T) {
+	cases := []struct {
+		host     string
+		port     string
+		expected string
+	}{
+		{host: "10.0.0.1", port: "8443", expected: "https://10.0.0.1:8443"},
+		{host: "http://10.0.0.1", port: "8000", expected: "http://10.0.0.1:8000"},
+		{host: "https://waf.example.com", port: "", expected: "https://waf.example.com"},
+	}
+
+	for _, c := range cases {
+		if client := NewClient(c.host, c.port, "", ""); client.Host != c.expected {
+			t.Errorf("NewClient(%q, %q): expected %q, got %q", c.host, c.port, c.expected, client.Host)
+		}
+	}
+}
+
+func TestClient_Login(t *testing.T) {
+	client, server := newTestClient(t)
+
+	if server.Logins() != 1 {
+		t.Errorf("expected a single login, got %d", server.Logins())
+	}
+
+	client.Password = "wrong"
+	if err := client.Login(context.Background()); err == nil || !strings.Contains(err.Error(), "Login failed") {
+		t.Errorf("expected the login to fail, got %v", err)
+	}
+}
+
+func TestClient_untrustedCertificate(t *testing.T) {
+	server := waftest.NewTLSServer(t)
+
+	client := NewClient(server.URL, "", waftest.Username, waftest.Password)
+
+	err := client.Login(context.Background())
+
+	var unknownAuthorityError x509.UnknownAuthorityError
+	if !errors.As(err, &unknownAuthorityError) {
+		t.Fatalf("expected the certificate of the admin API to be rejected, got %v", err)
+	}
+
+	if !strings.Contains(err.Error(), "Set ca_cert_pem or ca_cert_file") {
+		t.Errorf("expected the error to explain how to trust the certificate, got %v", err)
+	}
+
+	if server.Logins() != 0 {
+		t.Errorf("expected no login to reach the WAF, got %d", server.Logins())
+	}
+
+	if checkTLSError(errors.New("connection refused")).Error() != "connection refused" {
+		t.Errorf("expected other errors to be returned as they are")
+	}
+}
+
+func TestClient_sendsSessionToken(t *testing.T) {
+	client, server := newTestClient(t)
+
+	if _, err := client.ListServices(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := NewClient(server.URL, "", "", "").ListServices(context.Background()); err == nil {
+		t.Errorf("expected the request without a session token to fail")
+	}
+}
+
+func TestClient_SetToken(t *testing.T) {
+	server := waftest.NewServer(t)
+
+	client := NewClient(server.URL, "", "", "").SetToken(server.IssueToken())
+
+	if _, err := client.ListServices(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	server.ExpireSessions()
+
+	if _, err := client.ListServices(context.Background()); err == nil {
+		t.Fatalf("expected the request with the expired token to fail")
+	}
+
+	if server.Logins() != 0 {
+		t.Errorf("expected no login without credentials, got %d logins", server.Logins())
+	}
+}
+
+func TestClient_renewsExpiredSession(t *testing.T) {
+	client, server := newTestClient(t)
+
+	server.ExpireSessions()
+
+	if _, err := client.ListServices(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if server.Logins() != 2 {
+		t.Errorf("expected the client to log in again, got %d logins", server.Logins())
+	}
+}
+
+func TestClient_renewsExpiredSessionOnce(t *testing.T) {
+	client, server := newTestClient(t)
+
+	server.ExpireSessions()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.ListServices(context.Background())
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if server.Logins() != 2 {
+		t.Errorf("expected the concurrent requests to share a single renewal, got %d logins", server.Logins())
+	}
+}
+
+func TestClient_renewsSessionOnlyOnce(t *testing.T) {
+	client, server := newTestClient(t)
+
+	server.FailNext(http.MethodGet, http.StatusUnauthorized, "Invalid token", 2)
+
+	_, err := client.ListServices(context.Background())
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected the request rejected after the renewal to fail, got %v", err)
+	}
+
+	if server.Logins() != 2 {
+		t.Errorf("expected a single renewal, got %d logins", server.Logins())
+	}
+
+	if requests := server.Requests(http.MethodGet, "/services"); len(requests) != 2 {
+		t.Errorf("expected the request to be sent once more after the renewal, got %d attempts", len(requests))
+	}
+}
+
+func TestClient_isSessionExpired(t *testing.T) {
+	client := NewClient("127.0.0.1", "8443", waftest.Username, waftest.Password)
+	services := &request{method: http.MethodGet, path: "/services"}
+
+	cases := map[string]struct {
+		client   *Client
+		req      *request
+		err      error
+		expected bool
+	}{
+		"unauthorized":     {client: client, req: services, err: &APIError{StatusCode: http.StatusUnauthorized}, expected: true},
+		"expired token":    {client: client, req: services, err: &APIError{StatusCode: http.StatusBadRequest, Message: "Token has expired"}, expected: true},
+		"invalid token":    {client: client, req: services, err: &APIError{StatusCode: http.StatusForbidden, Message: "Invalid token"}, expected: true},
+		"server error":     {client: client, req: services, err: &APIError{StatusCode: http.StatusInternalServerError, Message: "Invalid token"}},
+		"other error":      {client: client, req: services, err: &APIError{StatusCode: http.StatusBadRequest, Message: "Invalid port"}},
+		"connection error": {client: client, req: services, err: errors.New("connection reset by peer")},
+		"login":            {client: client, req: &request{method: http.MethodPost, path: "/login"}, err: &APIError{StatusCode: http.StatusUnauthorized}},
+		"no credentials": {
+			client: NewClient("127.0.0.1", "8443", "", "").SetToken("token"),
+			req:    services,
+			err:    &APIError{StatusCode: http.StatusUnauthorized},
+		},
+	}
+
+	for name, c := range cases {
+		if expired := c.client.isSessionExpired(c.req, c.err); expired != c.expected {
+			t.Errorf("%s: expected %v, got %v", name, c.expected, expired)
+		}
+	}
+}
+
+func TestClient_retriesTransientErrors(t *testing.T) {
+	client, server := newTestClient(t)
+
+	server.FailNext(http.MethodGet, http.StatusServiceUnavailable, "Service Unavailable", 2)
+
+	if _, err := client.ListServices(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if requests := server.Requests(http.MethodGet, "/services"); len(requests) != 3 {
+		t.Errorf("expected 3 attempts, got %d", len(requests))
+	}
+}
+
+func TestClient_retriesExhausted(t *testing.T) {
+	client, server := newTestClient(t)
+	client.MaxRetries = 2
+
+	server.FailNext(http.MethodGet, http.StatusServiceUnavailable, "Service Unavailable", 5)
+
+	_, err := client.ListServices(context.Background())
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable || apiErr.Message != "Service Unavailable" {
+		t.Fatalf("expected the last error to be returned, got %v", err)
+	}
+
+	if requests := server.Requests(http.MethodGet, "/services"); len(requests) != 3 {
+		t.Errorf("expected 3 attempts, got %d", len(requests))
+	}
+}
+
+func TestClient_retryCanceled(t *testing.T) {
+	client, server := newTestClient(t)
+	client.RetryMinWait = time.Hour
+	client.RetryMaxWait = time.Hour
+
+	server.FailNext(http.MethodGet, http.StatusServiceUnavailable, "Service Unavailable", 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.ListServices(ctx); err == nil {
+		t.Fatalf("expected the request to fail")
+	}
+
+	if requests := server.Requests(http.MethodGet, "/services"); len(requests) != 1 {
+		t.Errorf("expected the retry to be abandoned, got %d attempts", len(requests))
+	}
+}
+
+func TestClient_retriesBusyWAF(t *testing.T) {
+	client, server := newTestClient(t)
+
+	server.FailNext(http.MethodPost, http.StatusBadRequest, "Configuration is being applied, try again later", 1)
+
+	if err := client.CreateSecurityPolicy(context.Background(), &SecurityPolicy{Name: "DemoPolicy"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if requests := server.Requests(http.MethodPost, "/security-policies"); len(requests) != 2 {
+		t.Errorf("expected 2 attempts, got %d", len(requests))
+	}
+}
+
+func TestClient_doesNotRetryNonIdempotentRequests(t *testing.T) {
+	client, server := newTestClient(t)
+
+	server.FailNext(http.MethodPost, http.StatusInternalServerError, "Internal Server Error", 1)
+
+	if err := client.CreateSecurityPolicy(context.Background(), &SecurityPolicy{Name: "DemoPolicy"}); err == nil {
+		t.Fatalf("expected the request to fail")
+	}
+
+	if requests := server.Requests(http.MethodPost, "/security-policies"); len(requests) != 1 {
+		t.Errorf("expected a single attempt, got %d", len(requests))
+	}
+
+	if server.Object("security-policies/DemoPolicy") != nil {
+		t.Errorf("expected the failed request not to be applied")
+	}
+}
+
+func TestClient_doesNotRetryPermanentErrors(t *testing.T) {
+	client, server := newTestClient(t)
+
+	err := client.Do(context.Background(), http.MethodGet, "/services/DemoApp1", nil, nil)
+
+	if !IsNotFound(err) {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+
+	if requests := server.Requests(http.MethodGet, "/services/DemoApp1"); len(requests) != 1 {
+		t.Errorf("expected a single attempt, got %d", len(requests))
+	}
+}
+
+func TestNewAPIError(t *testing.T) {
+	cases := []struct {
+		statusCode int
+		resp       string
+		expected   string
+	}{
+		{statusCode: http.StatusBadGateway, resp: "", expected: "Bad Gateway"},
+		{statusCode: http.StatusInternalServerError, resp: `{}`, expected: "Internal Server Error"},
+		{statusCode: http.StatusNotFound, resp: `{"msg":"Service DemoApp1 does not exist"}`, expected: "Service DemoApp1 does not exist"},
+		{statusCode: http.StatusBadGateway, resp: "<html>Bad Gateway</html>\n", expected: "<html>Bad Gateway</html>"},
+	}
+
+	req := &request{method: http.MethodGet, path: "/services"}
+
+	for _, c := range cases {
+		err := newAPIError(req, c.statusCode, []byte(c.resp))
+
+		if err.Error() != c.expected || err.StatusCode != c.statusCode || err.Method != "GET" || err.URL != "/services" {
+			t.Errorf("newAPIError(%d, %q): unexpected error %#v", c.statusCode, c.resp, err)
+		}
+	}
+}
+
+func TestNewAPIError_fields(t *testing.T) {
+	cases := []struct {
+		resp     string
+		message  string
+		expected []FieldError
+	}{
+		{
+			resp:     `{"msg":"Validation failed","errors":{"port":"must be a number","name":["is required","is too long"]}}`,
+			message:  "Validation failed",
+			expected: []FieldError{{"name", "is required"}, {"name", "is too long"}, {"port", "must be a number"}},
+		},
+		{
+			resp:     `{"error":{"msg":"Invalid parameters","status":400},"errors":[{"param":"hsts-max-age","msg":"must be a number"},{"field":"mode","msg":"invalid"}]}`,
+			message:  "Invalid parameters",
+			expected: []FieldError{{"hsts-max-age", "must be a number"}, {"mode", "invalid"}},
+		},
+		{
+			resp:    `{"msg":"Service DemoApp1 does not exist"}`,
+			message: "Service DemoApp1 does not exist",
+		},
+	}
+
+	req := &request{method: http.MethodPut, path: "/services/DemoApp1"}
+
+	for _, c := range cases {
+		err := newAPIError(req, http.StatusBadRequest, []byte(c.resp))
+
+		if err.Message != c.message || !reflect.DeepEqual(err.Fields, c.expected) {
+			t.Errorf("newAPIError(%q): unexpected error %#v", c.resp, err)
+		}
+	}
+
+	err := &APIError{Message: "Validation failed", Fields: []FieldError{{"port", "must be a number"}}}
+	if expected := "Validation failed (port: must be a number)"; err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+}
+
+func TestClient_validationError(t *testing.T) {
+	client, server := newTestClient(t)
+
+	server.RejectNext(http.MethodPost, map[string]string{"port": "must be a number"})
+
+	err := client.CreateService(context.Background(), &Service{Name: "DemoApp1", Port: "eighty"})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || len(apiErr.Fields) != 1 || apiErr.Fields[0].Param != "port" {
+		t.Fatalf("expected a validation error of the port, got %#v", err)
+	}
+
+	if apiErr.StatusCode != http.StatusBadRequest || apiErr.Method != http.MethodPost || apiErr.URL != "/services" {
+		t.Errorf("expected the request to be reported, got %#v", apiErr)
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	if !IsNotFound(&NotFoundError{Kind: "service", Name: "DemoApp1"}) {
+		t.Errorf("expected NotFoundError to be reported as not found")
+	}
+
+	if !IsNotFound(&APIError{StatusCode: http.StatusNotFound}) {
+		t.Errorf("expected a 404 APIError to be reported as not found")
+	}
+
+	if IsNotFound(&APIError{StatusCode: http.StatusBadRequest}) || IsNotFound(errors.New("not found")) {
+		t.Errorf("expected other errors not to be reported as not found")
+	}
+}
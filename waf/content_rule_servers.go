@@ -0,0 +1,136 @@
+package waf
+
+import (
+	"context"
+	"net/http"
+)
+
+// ContentRuleServer : back-end server of a content rule.
+type ContentRuleServer struct {
+	Name           string `json:"name,omitempty"`
+	AddressVersion string `json:"address-version,omitempty"`
+	Comments       string `json:"comments"`
+	Hostname       string `json:"hostname"`
+	Identifier     string `json:"identifier,omitempty"`
+	IPAddress      string `json:"ip-address,omitempty"`
+	Port           string `json:"port,omitempty"`
+	Status         string `json:"status,omitempty"`
+}
+
+// contentRuleServersPath : endpoint of the servers of a content rule
+func contentRuleServersPath(service string, contentRule string) string {
+	return objectPath("services", service, "content-rules", contentRule, "content-rule-servers")
+}
+
+// ListContentRuleServers : returns the servers of the content rule sorted by name.
+func (c *Client) ListContentRuleServers(ctx context.Context, service string, contentRule string) ([]ContentRuleServer, error) {
+	var servers []ContentRuleServer
+	err := c.listObjects(ctx, contentRuleServersPath(service, contentRule), &servers)
+
+	return servers, err
+}
+
+// GetContentRuleServer : returns the named server of the content rule.
+func (c *Client) GetContentRuleServer(
+	ctx context.Context,
+	service string,
+	contentRule string,
+	name string,
+) (*ContentRuleServer, error) {
+	var server ContentRuleServer
+	if err := c.getObject(ctx, contentRuleServersPath(service, contentRule), "content rule server", name, &server); err != nil {
+		return nil, err
+	}
+
+	return &server, nil
+}
+
+// CreateContentRuleServer : creates a server of the content rule.
+func (c *Client) CreateContentRuleServer(
+	ctx context.Context,
+	service string,
+	contentRule string,
+	server *ContentRuleServer,
+) error {
+	return c.Do(ctx, http.MethodPost, contentRuleServersPath(service, contentRule), server, nil)
+}
+
+// UpdateContentRuleServer : updates the named server of the content rule. The address version
+// can only be set when the server is created and is not sent.
+func (c *Client) UpdateContentRuleServer(
+	ctx context.Context,
+	service string,
+	contentRule string,
+	name string,
+	server *ContentRuleServer,
+) error {
+	update := *server
+	update.AddressVersion = ""
+
+	return c.Do(ctx, http.MethodPut, contentRuleServersPath(service, contentRule)+objectPath(name), &update, nil)
+}
+
+// DeleteContentRuleServer : deletes the named server of the content rule.
+func (c *Client) DeleteContentRuleServer(ctx context.Context, service string, contentRule string, name string) error {
+	return c.Do(ctx, http.MethodDelete, contentRuleServersPath(service, contentRule)+objectPath(name), nil, nil)
+}
+
+// GetContentRuleServerSSLPolicy : returns the SSL settings of the named server of the content rule.
+func (c *Client) GetContentRuleServerSSLPolicy(
+	ctx context.Context,
+	service string,
+	contentRule string,
+	name string,
+) (*SSLPolicy, error) {
+	var sslPolicy SSLPolicy
+	if err := c.getSubResource(ctx, contentRuleServersPath(service, contentRule), name, "ssl-policy", &sslPolicy); err != nil {
+		return nil, err
+	}
+
+	return &sslPolicy, nil
+}
+
+// UpdateContentRuleServerSSLPolicy : updates the SSL settings of the named server of the content rule.
+func (c *Client) UpdateContentRuleServerSSLPolicy(
+	ctx context.Context,
+	service string,
+	contentRule string,
+	name string,
+	sslPolicy *SSLPolicy,
+) error {
+	path := contentRuleServersPath(service, contentRule) + objectPath(name, "ssl-policy")
+
+	return c.Do(ctx, http.MethodPut, path, sslPolicy, nil)
+}
+
+// GetContentRuleServerConnectionPooling : returns the connection pooling settings of the named
+// server of the content rule.
+func (c *Client) GetContentRuleServerConnectionPooling(
+	ctx context.Context,
+	service string,
+	contentRule string,
+	name string,
+) (*ConnectionPooling, error) {
+	var connectionPooling ConnectionPooling
+	path := contentRuleServersPath(service, contentRule)
+
+	if err := c.getSubResource(ctx, path, name, "connection-pooling", &connectionPooling); err != nil {
+		return nil, err
+	}
+
+	return &connectionPooling, nil
+}
+
+// UpdateContentRuleServerConnectionPooling : updates the connection pooling settings of the named
+// server of the content rule.
+func (c *Client) UpdateContentRuleServerConnectionPooling(
+	ctx context.Context,
+	service string,
+	contentRule string,
+	name string,
+	connectionPooling *ConnectionPooling,
+) error {
+	path := contentRuleServersPath(service, contentRule) + objectPath(name, "connection-pooling")
+
+	return c.Do(ctx, http.MethodPut, path, connectionPooling, nil)
+}
@@ -0,0 +1,54 @@
+package waf
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestClient_contentRuleServers(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+
+	server.SetObject("services/DemoApp1", nil)
+	server.SetObject("services/DemoApp1/content-rules/DemoRule1", nil)
+
+	path := "/services/DemoApp1/content-rules/DemoRule1/content-rule-servers/DemoRgServer1"
+	backend := &ContentRuleServer{Name: "DemoRgServer1", IPAddress: "10.11.16.21", Port: "80", AddressVersion: "IPv4"}
+
+	if err := client.CreateContentRuleServer(ctx, "DemoApp1", "DemoRule1", backend); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	backend.Port = "8080"
+	if err := client.UpdateContentRuleServer(ctx, "DemoApp1", "DemoRule1", "DemoRgServer1", backend); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	if body := server.LastRequest(http.MethodPut, path).Body; body["address-version"] != nil {
+		t.Errorf("update: expected the address version not to be sent, got %v", body)
+	}
+
+	sslPolicy := &SSLPolicy{EnableHTTPS: "Yes", EnableTLS12: "Yes"}
+	if err := client.UpdateContentRuleServerSSLPolicy(ctx, "DemoApp1", "DemoRule1", "DemoRgServer1", sslPolicy); err != nil {
+		t.Fatalf("ssl policy: %v", err)
+	}
+
+	got, err := client.GetContentRuleServer(ctx, "DemoApp1", "DemoRule1", "DemoRgServer1")
+	if err != nil || !reflect.DeepEqual(got, backend) {
+		t.Errorf("get: expected %+v, got %+v (%v)", backend, got, err)
+	}
+
+	if got, err := client.GetContentRuleServerSSLPolicy(ctx, "DemoApp1", "DemoRule1", "DemoRgServer1"); err != nil || !reflect.DeepEqual(got, sslPolicy) {
+		t.Errorf("ssl policy: expected %+v, got %+v (%v)", sslPolicy, got, err)
+	}
+
+	if err := client.DeleteContentRuleServer(ctx, "DemoApp1", "DemoRule1", "DemoRgServer1"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	if servers, err := client.ListContentRuleServers(ctx, "DemoApp1", "DemoRule1"); err != nil || len(servers) != 0 {
+		t.Errorf("list: expected no servers, got %+v (%v)", servers, err)
+	}
+}
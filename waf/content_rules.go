@@ -0,0 +1,59 @@
+package waf
+
+import (
+	"context"
+	"net/http"
+)
+
+// ContentRule : content rule of a service, routing the matching requests to its own servers.
+type ContentRule struct {
+	Name                  string `json:"name,omitempty"`
+	AccessLog             string `json:"access-log,omitempty"`
+	AppID                 string `json:"app-id"`
+	Comments              string `json:"comments"`
+	HostMatch             string `json:"host-match,omitempty"`
+	Status                string `json:"status,omitempty"`
+	ExtendedMatch         string `json:"extended-match,omitempty"`
+	ExtendedMatchSequence string `json:"extended-match-sequence,omitempty"`
+	Mode                  string `json:"mode,omitempty"`
+	URLMatch              string `json:"url-match,omitempty"`
+	WebFirewallPolicy     string `json:"web-firewall-policy,omitempty"`
+}
+
+// contentRulesPath : endpoint of the content rules of a service
+func contentRulesPath(service string) string {
+	return objectPath("services", service, "content-rules")
+}
+
+// ListContentRules : returns the content rules of the service sorted by name.
+func (c *Client) ListContentRules(ctx context.Context, service string) ([]ContentRule, error) {
+	var contentRules []ContentRule
+	err := c.listObjects(ctx, contentRulesPath(service), &contentRules)
+
+	return contentRules, err
+}
+
+// GetContentRule : returns the named content rule of the service.
+func (c *Client) GetContentRule(ctx context.Context, service string, name string) (*ContentRule, error) {
+	var contentRule ContentRule
+	if err := c.getObject(ctx, contentRulesPath(service), "content rule", name, &contentRule); err != nil {
+		return nil, err
+	}
+
+	return &contentRule, nil
+}
+
+// CreateContentRule : creates a content rule of the service.
+func (c *Client) CreateContentRule(ctx context.Context, service string, contentRule *ContentRule) error {
+	return c.Do(ctx, http.MethodPost, contentRulesPath(service), contentRule, nil)
+}
+
+// UpdateContentRule : updates the named content rule of the service.
+func (c *Client) UpdateContentRule(ctx context.Context, service string, name string, contentRule *ContentRule) error {
+	return c.Do(ctx, http.MethodPut, contentRulesPath(service)+objectPath(name), contentRule, nil)
+}
+
+// DeleteContentRule : deletes the named content rule of the service along with its servers.
+func (c *Client) DeleteContentRule(ctx context.Context, service string, name string) error {
+	return c.Do(ctx, http.MethodDelete, contentRulesPath(service)+objectPath(name), nil, nil)
+}
@@ -0,0 +1,41 @@
+package waf
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestClient_contentRules(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+
+	server.SetObject("services/DemoApp1", nil)
+
+	contentRule := &ContentRule{Name: "DemoRule1", URLMatch: "/index.html", HostMatch: "www.example.com"}
+	if err := client.CreateContentRule(ctx, "DemoApp1", contentRule); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	contentRule.Mode = "Active"
+	if err := client.UpdateContentRule(ctx, "DemoApp1", "DemoRule1", contentRule); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	got, err := client.GetContentRule(ctx, "DemoApp1", "DemoRule1")
+	if err != nil || !reflect.DeepEqual(got, contentRule) {
+		t.Errorf("get: expected %+v, got %+v (%v)", contentRule, got, err)
+	}
+
+	if contentRules, err := client.ListContentRules(ctx, "DemoApp1"); err != nil || len(contentRules) != 1 {
+		t.Errorf("list: expected a single content rule, got %+v (%v)", contentRules, err)
+	}
+
+	if err := client.DeleteContentRule(ctx, "DemoApp1", "DemoRule1"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	if _, err := client.GetContentRule(ctx, "DemoApp1", "DemoRule1"); !IsNotFound(err) {
+		t.Errorf("get: expected a not found error, got %v", err)
+	}
+}
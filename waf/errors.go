@@ -0,0 +1,166 @@
+package waf
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+var (
+	// transientErrorMessages : messages of the WAF errors reported while the appliance is busy,
+	// the rejected request can be sent again once the pending operation completes.
+	transientErrorMessages = []string{
+		"configuration is being applied",
+		"is locked",
+		"locked by",
+		"is busy",
+		"try again",
+		"temporarily unavailable",
+	}
+)
+
+// APIError : error returned by the REST API.
+type APIError struct {
+	StatusCode int          // HTTP status of the response
+	Method     string       // method of the failed request
+	URL        string       // path of the failed request, relative to the API base
+	Message    string       // message reported by the WAF
+	Fields     []FieldError // validation errors of the request parameters, sorted by parameter
+}
+
+// FieldError : validation error of a parameter of the request.
+type FieldError struct {
+	Param   string // REST name of the parameter, e.g. "hsts-max-age"
+	Message string // reason the value was rejected
+}
+
+// Error : returns the message reported by the WAF, followed by the validation errors.
+func (e *APIError) Error() string {
+	if len(e.Fields) == 0 {
+		return e.Message
+	}
+
+	fields := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		fields = append(fields, field.Param+": "+field.Message)
+	}
+
+	return e.Message + " (" + strings.Join(fields, ", ") + ")"
+}
+
+// Transient : reports whether the error is caused by a temporary condition on the WAF.
+func (e *APIError) Transient() bool {
+	message := strings.ToLower(e.Message)
+
+	for _, transientMessage := range transientErrorMessages {
+		if strings.Contains(message, transientMessage) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newAPIError : builds the error of a failed request from the response body, which holds
+// the message in its "msg" field, or in the "msg" field of its "error" object, and the
+// validation errors in its "errors" field. Bodies which are not JSON are used as the message.
+func newAPIError(req *request, statusCode int, resp []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		Method:     req.method,
+		URL:        req.path,
+	}
+
+	var body struct {
+		Message string `json:"msg"`
+		Error   struct {
+			Message string `json:"msg"`
+		} `json:"error"`
+		Errors json.RawMessage `json:"errors"`
+	}
+
+	valid := json.Unmarshal(resp, &body) == nil
+
+	switch {
+	case valid && body.Message != "":
+		apiErr.Message = body.Message
+	case valid && body.Error.Message != "":
+		apiErr.Message = body.Error.Message
+	case len(resp) > 0 && !json.Valid(resp):
+		apiErr.Message = strings.TrimSpace(string(resp))
+	default:
+		apiErr.Message = http.StatusText(statusCode)
+	}
+
+	if valid {
+		apiErr.Fields = parseFieldErrors(body.Errors)
+	}
+
+	return apiErr
+}
+
+// parseFieldErrors : decodes the validation errors of a response. They are either an object
+// mapping the parameters to a message or a list of messages, or a list of objects holding
+// the parameter and the message.
+func parseFieldErrors(data json.RawMessage) []FieldError {
+	var fields []FieldError
+
+	var byParam map[string]interface{}
+	var list []struct {
+		Param   string `json:"param"`
+		Field   string `json:"field"`
+		Message string `json:"msg"`
+	}
+
+	switch {
+	case len(data) == 0:
+		return nil
+	case json.Unmarshal(data, &byParam) == nil:
+		for param, value := range byParam {
+			switch v := value.(type) {
+			case string:
+				fields = append(fields, FieldError{Param: param, Message: v})
+			case []interface{}:
+				for _, message := range v {
+					fields = append(fields, FieldError{Param: param, Message: fmt.Sprint(message)})
+				}
+			}
+		}
+	case json.Unmarshal(data, &list) == nil:
+		for _, item := range list {
+			param := item.Param
+			if param == "" {
+				param = item.Field
+			}
+			fields = append(fields, FieldError{Param: param, Message: item.Message})
+		}
+	}
+
+	sort.SliceStable(fields, func(i, j int) bool {
+		return fields[i].Param < fields[j].Param
+	})
+
+	return fields
+}
+
+// NotFoundError : returned when the requested object does not exist on the WAF.
+type NotFoundError struct {
+	Kind string // kind of the object, e.g. "service"
+	Name string // name of the object
+}
+
+// Error : returns the description of the missing object.
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s (%s) not found on the Barracuda WAF", e.Kind, e.Name)
+}
+
+// IsNotFound : reports whether the error is caused by a missing object.
+func IsNotFound(err error) bool {
+	var notFoundErr *NotFoundError
+	var apiErr *APIError
+
+	return errors.As(err, &notFoundErr) || (errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound)
+}
@@ -0,0 +1,103 @@
+package waf
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultMaxConcurrentRequests = 4 // DefaultMaxConcurrentRequests : requests sent to the WAF at the same time
+)
+
+// limiter : bounds the number of requests in flight and the rate they are sent at.
+type limiter struct {
+	slots    chan struct{} // one element per request in flight, nil when unlimited
+	interval time.Duration // minimum time between the start of two requests, 0 when unlimited
+
+	mutex sync.Mutex
+	next  time.Time // earliest start of the next request
+}
+
+// newLimiter : returns a limiter for the given number of concurrent requests and requests
+// per second, zero disables the limit.
+func newLimiter(maxConcurrentRequests int, requestsPerSecond float64) *limiter {
+	l := &limiter{}
+
+	if maxConcurrentRequests > 0 {
+		l.slots = make(chan struct{}, maxConcurrentRequests)
+	}
+
+	if requestsPerSecond > 0 {
+		l.interval = time.Duration(float64(time.Second) / requestsPerSecond)
+	}
+
+	return l
+}
+
+// acquire : waits until a request can be sent, the returned function releases its slot once
+// the request completes.
+func (l *limiter) acquire(ctx context.Context) (func(), error) {
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	release := func() {
+		if l.slots != nil {
+			<-l.slots
+		}
+	}
+
+	if err := l.wait(ctx); err != nil {
+		release()
+		return nil, err
+	}
+
+	return release, nil
+}
+
+// wait : waits until the start of the request respects the rate limit.
+func (l *limiter) wait(ctx context.Context) error {
+	if l.interval == 0 {
+		return nil
+	}
+
+	l.mutex.Lock()
+	now := time.Now()
+	start := l.next
+	if start.Before(now) {
+		start = now
+	}
+	l.next = start.Add(l.interval)
+	l.mutex.Unlock()
+
+	delay := time.Until(start)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// init : builds the HTTP client and the limiter shared by the requests of the client from its
+// settings, on the first request.
+func (c *Client) init() {
+	c.initOnce.Do(func() {
+		// the deadline of the requests is set by their context
+		c.httpClient = &http.Client{Transport: c.Transport}
+		c.limiter = newLimiter(c.MaxConcurrentRequests, c.RequestsPerSecond)
+	})
+}
@@ -0,0 +1,95 @@
+package waf
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// keyedMutex : serializes the holders of the same key while letting different keys proceed
+// concurrently. The zero value is ready to use.
+type keyedMutex struct {
+	mutex sync.Mutex
+	locks map[string]*keyedLock
+}
+
+// keyedLock : lock of a single key, removed once it has no holder nor waiter.
+type keyedLock struct {
+	held chan struct{} // holds one element while the lock is held
+	refs int           // holder and waiters of the lock
+}
+
+// lock : waits until the key is free or the context is done, the returned function unlocks it.
+func (m *keyedMutex) lock(ctx context.Context, key string) (func(), error) {
+	m.mutex.Lock()
+	if m.locks == nil {
+		m.locks = make(map[string]*keyedLock)
+	}
+	l, ok := m.locks[key]
+	if !ok {
+		l = &keyedLock{held: make(chan struct{}, 1)}
+		m.locks[key] = l
+	}
+	l.refs++
+	m.mutex.Unlock()
+
+	select {
+	case l.held <- struct{}{}:
+	case <-ctx.Done():
+		m.done(key, l)
+		return nil, ctx.Err()
+	}
+
+	return func() {
+		<-l.held
+		m.done(key, l)
+	}, nil
+}
+
+// done : drops a reference to the lock of the key.
+func (m *keyedMutex) done(key string, l *keyedLock) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	l.refs--
+	if l.refs == 0 {
+		delete(m.locks, key)
+	}
+}
+
+// parentKey : returns the top level object a request modifies, e.g. "/services/DemoApp1"
+// for the requests on the servers and the content rules of the service. Reads and requests
+// on collections, e.g. the creation of a service, return an empty key and are not serialized.
+func parentKey(method string, path string) string {
+	if method == http.MethodGet {
+		return ""
+	}
+
+	segments := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 3)
+	if len(segments) < 2 || segments[1] == "" {
+		return ""
+	}
+
+	return "/" + segments[0] + "/" + segments[1]
+}
+
+// heldParentKey : context key of the parent locked by lockParent.
+type heldParentKey struct{}
+
+// lockParent : locks the parent of a sequence of requests, e.g. the read and the update of a
+// setting of a service. The requests sent with the returned context do not lock it again.
+func (c *Client) lockParent(ctx context.Context, key string) (context.Context, func(), error) {
+	unlock, err := c.parentLocks.lock(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return context.WithValue(ctx, heldParentKey{}, key), unlock, nil
+}
+
+// holdsParent : reports whether the context was returned by lockParent for the key.
+func holdsParent(ctx context.Context, key string) bool {
+	held, _ := ctx.Value(heldParentKey{}).(string)
+	return held == key
+}
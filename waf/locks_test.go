@@ -0,0 +1,112 @@
+package waf
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParentKey(t *testing.T) {
+	cases := []struct {
+		method   string
+		path     string
+		expected string
+	}{
+		{method: http.MethodPost, path: "/services", expected: ""},
+		{method: http.MethodPut, path: "/services/DemoApp1", expected: "/services/DemoApp1"},
+		{method: http.MethodPost, path: "/services/DemoApp1/servers", expected: "/services/DemoApp1"},
+		{method: http.MethodPut, path: "/services/DemoApp1/servers/web1/ssl-policy", expected: "/services/DemoApp1"},
+		{method: http.MethodDelete, path: "/security-policies/policy1", expected: "/security-policies/policy1"},
+		{method: http.MethodGet, path: "/services/DemoApp1/servers", expected: ""},
+		{method: http.MethodPost, path: "/login", expected: ""},
+	}
+
+	for _, c := range cases {
+		if key := parentKey(c.method, c.path); key != c.expected {
+			t.Errorf("%s %s: expected key %q, got %q", c.method, c.path, c.expected, key)
+		}
+	}
+}
+
+func TestKeyedMutex(t *testing.T) {
+	var m keyedMutex
+
+	unlock, err := m.lock(context.Background(), "/services/DemoApp1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	other, err := m.lock(context.Background(), "/services/DemoApp2")
+	if err != nil {
+		t.Fatalf("expected another key to be locked at the same time, got %v", err)
+	}
+	other()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := m.lock(ctx, "/services/DemoApp1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the held key to wait for the context, got %v", err)
+	}
+
+	unlock()
+
+	if len(m.locks) != 0 {
+		t.Errorf("expected the unused locks to be removed, got %d", len(m.locks))
+	}
+}
+
+func TestClient_serializesChangesOfAParent(t *testing.T) {
+	var mutex sync.Mutex
+	inFlight := map[string]int{}
+	maxInFlight := map[string]int{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		service := strings.Split(strings.TrimPrefix(r.URL.Path, "/"+baseURI+"/"), "/")[1]
+
+		mutex.Lock()
+		inFlight[service]++
+		if inFlight[service] > maxInFlight[service] {
+			maxInFlight[service] = inFlight[service]
+		}
+		mutex.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+
+		mutex.Lock()
+		inFlight[service]--
+		mutex.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"msg":"Configuration updated"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "", "", "").SetToken("token")
+	client.MaxConcurrentRequests = 0
+
+	var wg sync.WaitGroup
+	for _, service := range []string{"DemoApp1", "DemoApp2"} {
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func(service string) {
+				defer wg.Done()
+				if err := client.CreateServer(context.Background(), service, &Server{Name: "web"}); err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			}(service)
+		}
+	}
+	wg.Wait()
+
+	for _, service := range []string{"DemoApp1", "DemoApp2"} {
+		if maxInFlight[service] != 1 {
+			t.Errorf("expected the changes of %s to be sent one at a time, got %d at once", service, maxInFlight[service])
+		}
+	}
+}
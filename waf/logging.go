@@ -0,0 +1,131 @@
+package waf
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// LogSubsystem : tflog subsystem logging the requests and responses of the REST API
+	LogSubsystem = "waf"
+
+	redacted = "***" // redacted : logged in place of secret values, as tflog masks them
+)
+
+var (
+	// secretParams : parts of the names of the parameters holding secrets
+	secretParams = []string{"password", "token", "secret", "passphrase"}
+
+	// secretHeaders : request headers holding secrets
+	secretHeaders = []string{"Authorization", "Cookie"}
+
+	// privateKeyPattern : PEM private keys, masked wherever they appear in a logged value
+	privateKeyPattern = regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`)
+)
+
+// isSecretParam : reports whether the parameter holds a secret, e.g. "certificate-password"
+// or "certificate-key".
+func isSecretParam(param string) bool {
+	param = strings.ToLower(param)
+
+	if param == "key" || strings.HasSuffix(param, "-key") || strings.HasSuffix(param, "_key") {
+		return true
+	}
+
+	for _, secretParam := range secretParams {
+		if strings.Contains(param, secretParam) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redactValue : replaces the values of the secret parameters in a decoded JSON value.
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for param, paramValue := range v {
+			if isSecretParam(param) {
+				v[param] = redacted
+			} else {
+				v[param] = redactValue(paramValue)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+
+	return value
+}
+
+// redactBody : returns the body to log, with the values of the secret parameters replaced.
+// Bodies which are not JSON are logged as they are.
+func redactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return strings.TrimSpace(string(body))
+	}
+
+	data, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return redacted
+	}
+
+	return string(data)
+}
+
+// logContext : returns the context of the waf logging subsystem. The secret headers, the
+// password of the client and PEM private keys are masked in every entry.
+func (c *Client) logContext(ctx context.Context) context.Context {
+	ctx = tflog.NewSubsystem(ctx, LogSubsystem)
+	ctx = tflog.SubsystemMaskFieldValuesWithFieldKeys(ctx, LogSubsystem, secretHeaders...)
+	ctx = tflog.SubsystemMaskAllFieldValuesRegexes(ctx, LogSubsystem, privateKeyPattern)
+
+	if c.Password != "" {
+		ctx = tflog.SubsystemMaskAllFieldValuesStrings(ctx, LogSubsystem, c.Password)
+	}
+
+	return ctx
+}
+
+// headerFields : returns the headers of the request as log fields named after them.
+func headerFields(headers http.Header, fields map[string]interface{}) map[string]interface{} {
+	for header := range headers {
+		fields[header] = headers.Get(header)
+	}
+
+	return fields
+}
+
+// logRequest : logs a request sent to the REST API at debug level.
+func logRequest(ctx context.Context, httpReq *http.Request, body []byte) {
+	tflog.SubsystemDebug(ctx, LogSubsystem, "Sending request to the Barracuda WAF", headerFields(httpReq.Header, map[string]interface{}{
+		"http_method":       httpReq.Method,
+		"http_url":          httpReq.URL.String(),
+		"http_request_body": redactBody(body),
+	}))
+}
+
+// logResponse : logs the response of the REST API at debug level.
+func logResponse(ctx context.Context, httpReq *http.Request, statusCode int, latency time.Duration, body []byte) {
+	tflog.SubsystemDebug(ctx, LogSubsystem, "Received response from the Barracuda WAF", map[string]interface{}{
+		"http_method":        httpReq.Method,
+		"http_url":           httpReq.URL.String(),
+		"http_status_code":   statusCode,
+		"http_latency_ms":    latency.Milliseconds(),
+		"http_response_body": redactBody(body),
+	})
+}
@@ -0,0 +1,178 @@
+package waf
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// objectsResponse : response of the REST API listing the objects of a collection, or
+// the parameters of a sub resource, keyed by the name of the object.
+type objectsResponse struct {
+	Object string                            `json:"object,omitempty"`
+	Data   map[string]map[string]interface{} `json:"data,omitempty"`
+}
+
+// objectPath : returns the path of a collection nested under parent objects, e.g.
+// objectPath("services", "DemoApp1", "servers") returns "/services/DemoApp1/servers".
+func objectPath(segments ...string) string {
+	escaped := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		escaped = append(escaped, url.PathEscape(segment))
+	}
+
+	return "/" + strings.Join(escaped, "/")
+}
+
+// listObjects : fetches the objects of a collection into out, a pointer to a slice of
+// typed objects, sorted by name.
+func (c *Client) listObjects(ctx context.Context, path string, out interface{}) error {
+	var resp objectsResponse
+	if err := c.Do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(resp.Data))
+	for name := range resp.Data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	elemType := reflect.TypeOf(out).Elem().Elem()
+	objects := make([]map[string]interface{}, 0, len(names))
+	for _, name := range names {
+		objects = append(objects, normalizeParams(resp.Data[name], elemType))
+	}
+
+	return remarshal(objects, out)
+}
+
+// getObject : fetches the named object of a collection into out, a pointer to a typed object.
+func (c *Client) getObject(ctx context.Context, path string, kind string, name string, out interface{}) error {
+	var resp objectsResponse
+	if err := c.Do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return err
+	}
+
+	for key, params := range resp.Data {
+		if params["name"] == name || (params["name"] == nil && key == name) {
+			return remarshal(normalizeParams(params, reflect.TypeOf(out).Elem()), out)
+		}
+	}
+
+	return &NotFoundError{Kind: kind, Name: name}
+}
+
+// getSubResource : fetches the parameters of a sub resource of the named object into out.
+func (c *Client) getSubResource(ctx context.Context, path string, name string, subResource string, out interface{}) error {
+	var resp objectsResponse
+	if err := c.Do(ctx, http.MethodGet, path+objectPath(name, subResource), nil, &resp); err != nil {
+		return err
+	}
+
+	params, ok := resp.Data[name]
+	if !ok {
+		for _, params = range resp.Data {
+			break
+		}
+	}
+
+	return remarshal(normalizeParams(params, reflect.TypeOf(out).Elem()), out)
+}
+
+// normalizeParams : converts the parameters returned by the REST API to the types of the
+// fields of the typed object. Scalars are converted to strings, single values to lists for
+// list fields and the parameters of nested groups are merged into the top level.
+func normalizeParams(data map[string]interface{}, objectType reflect.Type) map[string]interface{} {
+	params := mergeParamGroups(data)
+
+	normalized := make(map[string]interface{})
+	for i := 0; i < objectType.NumField(); i++ {
+		field := objectType.Field(i)
+		param := strings.Split(field.Tag.Get("json"), ",")[0]
+
+		value, ok := params[param]
+		if !ok || param == "" {
+			continue
+		}
+
+		if field.Type.Kind() == reflect.Slice {
+			normalized[param] = normalizeList(value)
+		} else {
+			normalized[param] = normalizeValue(value)
+		}
+	}
+
+	return normalized
+}
+
+// mergeParamGroups : merges the parameters of the nested groups returned by the REST API,
+// e.g. "SSL Security", into the top level. Parameters of the object itself take precedence
+// over the ones of its groups.
+func mergeParamGroups(data map[string]interface{}) map[string]interface{} {
+	params := make(map[string]interface{})
+	for key, val := range data {
+		if _, ok := val.(map[string]interface{}); !ok {
+			params[key] = val
+		}
+	}
+
+	for _, val := range data {
+		if group, ok := val.(map[string]interface{}); ok {
+			for groupKey, groupVal := range group {
+				if _, exists := params[groupKey]; !exists {
+					params[groupKey] = groupVal
+				}
+			}
+		}
+	}
+
+	return params
+}
+
+// normalizeValue : converts a scalar returned by the REST API to a string.
+func normalizeValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// normalizeList : converts a list, or a single value, returned by the REST API to a list of strings.
+func normalizeList(value interface{}) []string {
+	switch v := value.(type) {
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, normalizeValue(item))
+		}
+		return items
+	default:
+		if item := normalizeValue(v); item != "" {
+			return []string{item}
+		}
+		return []string{}
+	}
+}
+
+// remarshal : decodes the generic representation of an object into its typed representation.
+func remarshal(in interface{}, out interface{}) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, out)
+}
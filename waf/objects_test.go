@@ -0,0 +1,26 @@
+package waf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeParamGroups(t *testing.T) {
+	params := mergeParamGroups(map[string]interface{}{
+		"name":          "DemoApp1",
+		"port":          float64(80),
+		"Service Group": map[string]interface{}{"group": "default", "port": "ignored"},
+		"SSL Security":  map[string]interface{}{"status": "Off"},
+	})
+
+	expected := map[string]interface{}{
+		"name":   "DemoApp1",
+		"port":   float64(80),
+		"group":  "default",
+		"status": "Off",
+	}
+
+	if !reflect.DeepEqual(params, expected) {
+		t.Errorf("expected %v, got %v", expected, params)
+	}
+}
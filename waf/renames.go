@@ -0,0 +1,53 @@
+package waf
+
+import (
+	"strings"
+	"sync"
+)
+
+// renames : renames of objects announced before they are made, to tell the objects moved
+// along with a renamed parent from the objects moved to another parent. The zero value is
+// ready to use.
+type renames struct {
+	mutex sync.Mutex
+	paths map[string]string // new path of the renamed objects by their old path
+}
+
+// ServicePath : returns the path of the named service, e.g. "/services/DemoApp1".
+func ServicePath(name string) string {
+	return servicesPath + objectPath(name)
+}
+
+// ContentRulePath : returns the path of the named content rule of the service, e.g.
+// "/services/DemoApp1/content-rules/rule1".
+func ContentRulePath(service string, name string) string {
+	return contentRulesPath(service) + objectPath(name)
+}
+
+// ExpectRename : announces that the object at oldPath is about to be renamed, moving it
+// along with the objects nested under it to newPath.
+func (c *Client) ExpectRename(oldPath string, newPath string) {
+	c.renames.mutex.Lock()
+	defer c.renames.mutex.Unlock()
+
+	if c.renames.paths == nil {
+		c.renames.paths = make(map[string]string)
+	}
+	c.renames.paths[oldPath] = newPath
+}
+
+// IsRenameExpected : reports whether an announced rename of the object at oldPath, or of one
+// of its parents, moves it to newPath. E.g. "/services/DemoApp1/content-rules/rule1" is moved
+// to "/services/DemoApp2/content-rules/rule1" by the rename of the service DemoApp1.
+func (c *Client) IsRenameExpected(oldPath string, newPath string) bool {
+	c.renames.mutex.Lock()
+	defer c.renames.mutex.Unlock()
+
+	for path := oldPath; path != ""; path = path[:strings.LastIndex(path, "/")] {
+		if renamed, ok := c.renames.paths[path]; ok && renamed+strings.TrimPrefix(oldPath, path) == newPath {
+			return true
+		}
+	}
+
+	return false
+}
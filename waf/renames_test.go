@@ -0,0 +1,29 @@
+package waf
+
+import "testing"
+
+func TestClient_expectRename(t *testing.T) {
+	client := NewClient("127.0.0.1", "8443", "admin", "admin")
+	client.ExpectRename(ServicePath("DemoApp1"), ServicePath("DemoApp2"))
+	client.ExpectRename(ContentRulePath("DemoApp3", "rule1"), ContentRulePath("DemoApp3", "rule2"))
+
+	cases := []struct {
+		oldPath  string
+		newPath  string
+		expected bool
+	}{
+		{oldPath: ServicePath("DemoApp1"), newPath: ServicePath("DemoApp2"), expected: true},
+		{oldPath: ContentRulePath("DemoApp1", "rule1"), newPath: ContentRulePath("DemoApp2", "rule1"), expected: true},
+		{oldPath: ContentRulePath("DemoApp3", "rule1"), newPath: ContentRulePath("DemoApp3", "rule2"), expected: true},
+		{oldPath: ServicePath("DemoApp1"), newPath: ServicePath("DemoApp3"), expected: false},
+		{oldPath: ContentRulePath("DemoApp1", "rule1"), newPath: ContentRulePath("DemoApp2", "rule2"), expected: false},
+		{oldPath: ServicePath("DemoApp3"), newPath: ServicePath("DemoApp4"), expected: false},
+		{oldPath: ServicePath("DemoApp11"), newPath: ServicePath("DemoApp21"), expected: false},
+	}
+
+	for _, c := range cases {
+		if renamed := client.IsRenameExpected(c.oldPath, c.newPath); renamed != c.expected {
+			t.Errorf("%s to %s: expected %t, got %t", c.oldPath, c.newPath, c.expected, renamed)
+		}
+	}
+}
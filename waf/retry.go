@@ -0,0 +1,103 @@
+package waf
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+const (
+	DefaultMaxRetries   = 3                // DefaultMaxRetries : retries of a failed request
+	DefaultRetryMinWait = time.Second      // DefaultRetryMinWait : wait before the first retry
+	DefaultRetryMaxWait = 30 * time.Second // DefaultRetryMaxWait : upper bound of the wait between retries
+)
+
+var (
+	// idempotentMethods : methods safe to send again when the outcome of a request is unknown
+	idempotentMethods = map[string]bool{
+		http.MethodGet:    true,
+		http.MethodHead:   true,
+		http.MethodPut:    true,
+		http.MethodDelete: true,
+	}
+)
+
+// shouldRetry : reports whether a failed request can be sent again. Requests rejected by the
+// WAF as busy are always retried, other server and connection errors only for idempotent
+// methods since the request may have been applied already.
+func (c *Client) shouldRetry(req *request, statusCode int, err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if statusCode == 0 {
+		return !isTLSVerificationError(err) &&
+			!errors.Is(err, context.Canceled) &&
+			!errors.Is(err, context.DeadlineExceeded) &&
+			idempotentMethods[req.method]
+	}
+
+	if statusCode == http.StatusTooManyRequests || statusCode == http.StatusServiceUnavailable {
+		return true
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Transient() {
+		return true
+	}
+
+	return statusCode >= 500 && idempotentMethods[req.method]
+}
+
+// retryWait : returns the jittered exponential backoff before the given retry attempt.
+func (c *Client) retryWait(attempt int) time.Duration {
+	wait := c.RetryMinWait
+	for i := 0; i < attempt && wait < c.RetryMaxWait; i++ {
+		wait *= 2
+	}
+
+	if wait > c.RetryMaxWait {
+		wait = c.RetryMaxWait
+	}
+
+	if wait <= 0 {
+		return 0
+	}
+
+	// equal jitter : half of the backoff is fixed, the other half is random
+	return wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+}
+
+// retry : sends the request until it succeeds, fails with a permanent error, the configured
+// number of retries is exhausted or the context is done.
+func (c *Client) retry(ctx context.Context, req *request, call func() ([]byte, int, error)) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		data, statusCode, err := call()
+
+		if attempt >= c.MaxRetries || !c.shouldRetry(req, statusCode, err) {
+			return data, err
+		}
+
+		wait := c.retryWait(attempt)
+		log.Printf(
+			"[WARN] Barracuda WAF request (%s %s) failed (%v), retrying in %s (%d/%d)",
+			req.method,
+			req.path,
+			err,
+			wait,
+			attempt+1,
+			c.MaxRetries,
+		)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return data, err
+		case <-timer.C:
+		}
+	}
+}
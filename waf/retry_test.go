@@ -0,0 +1,92 @@
+package waf
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestClient_shouldRetry(t *testing.T) {
+	client := NewClient("127.0.0.1", "8443", "admin", "secret")
+
+	get := &request{method: http.MethodGet, path: "/services"}
+	put := &request{method: http.MethodPut, path: "/services/DemoApp1"}
+	post := &request{method: http.MethodPost, path: "/services"}
+
+	busy := &APIError{StatusCode: http.StatusBadRequest, Message: "Configuration is being applied, try again later"}
+	connectionReset := errors.New("read: connection reset by peer")
+
+	cases := map[string]struct {
+		req        *request
+		statusCode int
+		err        error
+		expected   bool
+	}{
+		"success":                  {req: get, statusCode: http.StatusOK},
+		"connection error GET":     {req: get, err: connectionReset, expected: true},
+		"connection error PUT":     {req: put, err: connectionReset, expected: true},
+		"connection error POST":    {req: post, err: connectionReset},
+		"canceled":                 {req: get, err: fmt.Errorf("request: %w", context.Canceled)},
+		"deadline exceeded":        {req: get, err: fmt.Errorf("request: %w", context.DeadlineExceeded)},
+		"untrusted certificate":    {req: get, err: x509.UnknownAuthorityError{}},
+		"server error GET":         {req: get, statusCode: http.StatusInternalServerError, err: &APIError{StatusCode: 500}, expected: true},
+		"server error POST":        {req: post, statusCode: http.StatusInternalServerError, err: &APIError{StatusCode: 500}},
+		"unavailable POST":         {req: post, statusCode: http.StatusServiceUnavailable, err: &APIError{StatusCode: 503}, expected: true},
+		"too many requests POST":   {req: post, statusCode: http.StatusTooManyRequests, err: &APIError{StatusCode: 429}, expected: true},
+		"busy POST":                {req: post, statusCode: http.StatusBadRequest, err: busy, expected: true},
+		"validation error PUT":     {req: put, statusCode: http.StatusBadRequest, err: &APIError{StatusCode: 400, Message: "Invalid port"}},
+		"not found GET":            {req: get, statusCode: http.StatusNotFound, err: &APIError{StatusCode: 404}},
+		"locked by another admin":  {req: put, statusCode: http.StatusConflict, err: &APIError{StatusCode: 409, Message: "Service is locked by admin"}, expected: true},
+		"temporarily unavailable":  {req: post, statusCode: http.StatusBadRequest, err: &APIError{StatusCode: 400, Message: "Temporarily unavailable"}, expected: true},
+		"unauthorized after login": {req: get, statusCode: http.StatusUnauthorized, err: &APIError{StatusCode: 401}},
+	}
+
+	for name, c := range cases {
+		if retry := client.shouldRetry(c.req, c.statusCode, c.err); retry != c.expected {
+			t.Errorf("%s: expected %v, got %v", name, c.expected, retry)
+		}
+	}
+}
+
+func TestClient_retryWait(t *testing.T) {
+	client := NewClient("127.0.0.1", "8443", "admin", "secret")
+	client.RetryMinWait = time.Second
+	client.RetryMaxWait = 10 * time.Second
+
+	// the backoff doubles from the minimum wait up to the maximum wait, half of it is jitter
+	backoffs := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second}
+
+	for attempt, backoff := range backoffs {
+		for i := 0; i < 100; i++ {
+			if wait := client.retryWait(attempt); wait < backoff/2 || wait > backoff {
+				t.Fatalf("attempt %d: expected a wait between %s and %s, got %s", attempt, backoff/2, backoff, wait)
+			}
+		}
+	}
+
+	client.RetryMinWait = 0
+	if wait := client.retryWait(3); wait != 0 {
+		t.Errorf("expected no wait without a minimum wait, got %s", wait)
+	}
+}
+
+func TestClient_retriesConnectionErrors(t *testing.T) {
+	client := NewClient("127.0.0.1", "1", "", "").SetToken("token")
+	client.MaxRetries = 2
+	client.RetryMinWait = time.Millisecond
+	client.RetryMaxWait = time.Millisecond
+
+	attempts := 0
+	_, err := client.retry(context.Background(), &request{method: http.MethodGet, path: "/services"}, func() ([]byte, int, error) {
+		attempts++
+		return nil, 0, errors.New("connection refused")
+	})
+
+	if err == nil || attempts != 3 {
+		t.Errorf("expected the request to be sent 3 times and fail, got %d attempts (%v)", attempts, err)
+	}
+}
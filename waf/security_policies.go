@@ -0,0 +1,52 @@
+package waf
+
+import (
+	"context"
+	"net/http"
+)
+
+// SecurityPolicy : security policy applied by the web firewall of the services.
+type SecurityPolicy struct {
+	Name    string `json:"name,omitempty"`
+	BasedOn string `json:"based-on,omitempty"`
+}
+
+// securityPoliciesPath : endpoint of the security policies
+var securityPoliciesPath = objectPath("security-policies")
+
+// ListSecurityPolicies : returns the security policies sorted by name.
+func (c *Client) ListSecurityPolicies(ctx context.Context) ([]SecurityPolicy, error) {
+	var securityPolicies []SecurityPolicy
+	err := c.listObjects(ctx, securityPoliciesPath, &securityPolicies)
+
+	return securityPolicies, err
+}
+
+// GetSecurityPolicy : returns the named security policy.
+func (c *Client) GetSecurityPolicy(ctx context.Context, name string) (*SecurityPolicy, error) {
+	var securityPolicy SecurityPolicy
+	if err := c.getObject(ctx, securityPoliciesPath, "security policy", name, &securityPolicy); err != nil {
+		return nil, err
+	}
+
+	return &securityPolicy, nil
+}
+
+// CreateSecurityPolicy : creates a security policy.
+func (c *Client) CreateSecurityPolicy(ctx context.Context, securityPolicy *SecurityPolicy) error {
+	return c.Do(ctx, http.MethodPost, securityPoliciesPath, securityPolicy, nil)
+}
+
+// UpdateSecurityPolicy : updates the named security policy. The policy it is based on can only
+// be set when the policy is created and is not sent.
+func (c *Client) UpdateSecurityPolicy(ctx context.Context, name string, securityPolicy *SecurityPolicy) error {
+	update := *securityPolicy
+	update.BasedOn = ""
+
+	return c.Do(ctx, http.MethodPut, securityPoliciesPath+objectPath(name), &update, nil)
+}
+
+// DeleteSecurityPolicy : deletes the named security policy.
+func (c *Client) DeleteSecurityPolicy(ctx context.Context, name string) error {
+	return c.Do(ctx, http.MethodDelete, securityPoliciesPath+objectPath(name), nil, nil)
+}
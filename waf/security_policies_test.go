@@ -0,0 +1,41 @@
+package waf
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestClient_securityPolicies(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+
+	securityPolicy := &SecurityPolicy{Name: "DemoPolicy1", BasedOn: "default"}
+	if err := client.CreateSecurityPolicy(ctx, securityPolicy); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	if err := client.UpdateSecurityPolicy(ctx, "DemoPolicy1", securityPolicy); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	if body := server.LastRequest(http.MethodPut, "/security-policies/DemoPolicy1").Body; body["based-on"] != nil {
+		t.Errorf("update: expected the policy it is based on not to be sent, got %v", body)
+	}
+
+	if got, err := client.GetSecurityPolicy(ctx, "DemoPolicy1"); err != nil || *got != *securityPolicy {
+		t.Errorf("get: expected %+v, got %+v (%v)", securityPolicy, got, err)
+	}
+
+	if securityPolicies, err := client.ListSecurityPolicies(ctx); err != nil || len(securityPolicies) != 1 {
+		t.Errorf("list: expected a single security policy, got %+v (%v)", securityPolicies, err)
+	}
+
+	if err := client.DeleteSecurityPolicy(ctx, "DemoPolicy1"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	if _, err := client.GetSecurityPolicy(ctx, "DemoPolicy1"); !IsNotFound(err) {
+		t.Errorf("get: expected a not found error, got %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package waf
+
+import (
+	"context"
+	"net/http"
+)
+
+// Server : back-end server of a service.
+type Server struct {
+	Name           string `json:"name,omitempty"`
+	AddressVersion string `json:"address-version,omitempty"`
+	Comments       string `json:"comments"`
+	Hostname       string `json:"hostname"`
+	Identifier     string `json:"identifier,omitempty"`
+	IPAddress      string `json:"ip-address,omitempty"`
+	Port           string `json:"port,omitempty"`
+	Status         string `json:"status,omitempty"`
+}
+
+// SSLPolicy : SSL settings used to connect to a back-end server.
+type SSLPolicy struct {
+	ClientCertificate          string `json:"client-certificate"`
+	EnableSSLCompatibilityMode string `json:"enable-ssl-compatibility-mode,omitempty"`
+	ValidateCertificate        string `json:"validate-certificate,omitempty"`
+	EnableHTTPS                string `json:"enable-https,omitempty"`
+	EnableSNI                  string `json:"enable-sni,omitempty"`
+	EnableSSL3                 string `json:"enable-ssl-3,omitempty"`
+	EnableTLS1                 string `json:"enable-tls-1,omitempty"`
+	EnableTLS11                string `json:"enable-tls-1-1,omitempty"`
+	EnableTLS12                string `json:"enable-tls-1-2,omitempty"`
+	EnableTLS13                string `json:"enable-tls-1-3,omitempty"`
+}
+
+// ConnectionPooling : connection pooling settings of a back-end server.
+type ConnectionPooling struct {
+	KeepaliveTimeout        string `json:"keepalive-timeout,omitempty"`
+	EnableConnectionPooling string `json:"enable-connection-pooling,omitempty"`
+}
+
+// serversPath : endpoint of the servers of a service
+func serversPath(service string) string {
+	return objectPath("services", service, "servers")
+}
+
+// ListServers : returns the servers of the service sorted by name.
+func (c *Client) ListServers(ctx context.Context, service string) ([]Server, error) {
+	var servers []Server
+	err := c.listObjects(ctx, serversPath(service), &servers)
+
+	return servers, err
+}
+
+// GetServer : returns the named server of the service.
+func (c *Client) GetServer(ctx context.Context, service string, name string) (*Server, error) {
+	var server Server
+	if err := c.getObject(ctx, serversPath(service), "server", name, &server); err != nil {
+		return nil, err
+	}
+
+	return &server, nil
+}
+
+// CreateServer : creates a server of the service.
+func (c *Client) CreateServer(ctx context.Context, service string, server *Server) error {
+	return c.Do(ctx, http.MethodPost, serversPath(service), server, nil)
+}
+
+// UpdateServer : updates the named server of the service. The address version can only be
+// set when the server is created and is not sent.
+func (c *Client) UpdateServer(ctx context.Context, service string, name string, server *Server) error {
+	update := *server
+	update.AddressVersion = ""
+
+	return c.Do(ctx, http.MethodPut, serversPath(service)+objectPath(name), &update, nil)
+}
+
+// DeleteServer : deletes the named server of the service.
+func (c *Client) DeleteServer(ctx context.Context, service string, name string) error {
+	return c.Do(ctx, http.MethodDelete, serversPath(service)+objectPath(name), nil, nil)
+}
+
+// GetServerSSLPolicy : returns the SSL settings of the named server of the service.
+func (c *Client) GetServerSSLPolicy(ctx context.Context, service string, name string) (*SSLPolicy, error) {
+	var sslPolicy SSLPolicy
+	if err := c.getSubResource(ctx, serversPath(service), name, "ssl-policy", &sslPolicy); err != nil {
+		return nil, err
+	}
+
+	return &sslPolicy, nil
+}
+
+// UpdateServerSSLPolicy : updates the SSL settings of the named server of the service.
+func (c *Client) UpdateServerSSLPolicy(ctx context.Context, service string, name string, sslPolicy *SSLPolicy) error {
+	return c.Do(ctx, http.MethodPut, serversPath(service)+objectPath(name, "ssl-policy"), sslPolicy, nil)
+}
+
+// GetServerConnectionPooling : returns the connection pooling settings of the named server of the service.
+func (c *Client) GetServerConnectionPooling(ctx context.Context, service string, name string) (*ConnectionPooling, error) {
+	var connectionPooling ConnectionPooling
+	if err := c.getSubResource(ctx, serversPath(service), name, "connection-pooling", &connectionPooling); err != nil {
+		return nil, err
+	}
+
+	return &connectionPooling, nil
+}
+
+// UpdateServerConnectionPooling : updates the connection pooling settings of the named server of the service.
+func (c *Client) UpdateServerConnectionPooling(
+	ctx context.Context,
+	service string,
+	name string,
+	connectionPooling *ConnectionPooling,
+) error {
+	return c.Do(ctx, http.MethodPut, serversPath(service)+objectPath(name, "connection-pooling"), connectionPooling, nil)
+}
@@ -0,0 +1,59 @@
+package waf
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestClient_servers(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+
+	if err := client.CreateServer(ctx, "DemoApp1", &Server{Name: "DemoServer1"}); !IsNotFound(err) {
+		t.Fatalf("create: expected the missing service to be reported, got %v", err)
+	}
+
+	server.SetObject("services/DemoApp1", nil)
+
+	backend := &Server{Name: "DemoServer1", IPAddress: "10.0.0.10", Port: "80", AddressVersion: "IPv4"}
+	if err := client.CreateServer(ctx, "DemoApp1", backend); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	backend.Status = "Out of Service Maintenance"
+	if err := client.UpdateServer(ctx, "DemoApp1", "DemoServer1", backend); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	if body := server.LastRequest(http.MethodPut, "/services/DemoApp1/servers/DemoServer1").Body; body["address-version"] != nil {
+		t.Errorf("update: expected the address version not to be sent, got %v", body)
+	}
+
+	if err := client.UpdateServerConnectionPooling(ctx, "DemoApp1", "DemoServer1", &ConnectionPooling{KeepaliveTimeout: "900000"}); err != nil {
+		t.Fatalf("connection pooling: %v", err)
+	}
+
+	got, err := client.GetServer(ctx, "DemoApp1", "DemoServer1")
+	if err != nil || !reflect.DeepEqual(got, backend) {
+		t.Errorf("get: expected %+v, got %+v (%v)", backend, got, err)
+	}
+
+	connectionPooling, err := client.GetServerConnectionPooling(ctx, "DemoApp1", "DemoServer1")
+	if err != nil || connectionPooling.KeepaliveTimeout != "900000" {
+		t.Errorf("connection pooling: unexpected result %+v (%v)", connectionPooling, err)
+	}
+
+	if servers, err := client.ListServers(ctx, "DemoApp1"); err != nil || len(servers) != 1 {
+		t.Errorf("list: expected a single server, got %+v (%v)", servers, err)
+	}
+
+	if err := client.DeleteServer(ctx, "DemoApp1", "DemoServer1"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	if _, err := client.GetServer(ctx, "DemoApp1", "DemoServer1"); !IsNotFound(err) {
+		t.Errorf("get: expected a not found error, got %v", err)
+	}
+}
@@ -0,0 +1,227 @@
+package waf
+
+import (
+	"context"
+	"net/http"
+)
+
+// Service : virtual service (web application) of the WAF.
+type Service struct {
+	Name             string   `json:"name,omitempty"`
+	AddressVersion   string   `json:"address-version,omitempty"`
+	Mask             string   `json:"mask,omitempty"`
+	SessionTimeout   string   `json:"session-timeout,omitempty"`
+	EnableAccessLogs string   `json:"enable-access-logs,omitempty"`
+	AppID            string   `json:"app-id"`
+	Comments         string   `json:"comments"`
+	Group            string   `json:"group,omitempty"`
+	IPAddress        string   `json:"ip-address,omitempty"`
+	CloudIPSelect    string   `json:"cloud-ip-select,omitempty"`
+	Port             string   `json:"port,omitempty"`
+	Status           string   `json:"status,omitempty"`
+	Type             string   `json:"type,omitempty"`
+	Certificate      string   `json:"certificate,omitempty"`
+	Vsite            string   `json:"vsite,omitempty"`
+	SecureSiteDomain []string `json:"secure-site-domain,omitempty"`
+}
+
+// ServiceBasicSecurity : basic security settings of a service.
+type ServiceBasicSecurity struct {
+	WebFirewallLogLevel string `json:"web-firewall-log-level,omitempty"`
+	Mode                string `json:"mode,omitempty"`
+	TrustedHostsAction  string `json:"trusted-hosts-action,omitempty"`
+	TrustedHostsGroup   string `json:"trusted-hosts-group"`
+	IgnoreCase          string `json:"ignore-case,omitempty"`
+	ClientIPAddrHeader  string `json:"client-ip-addr-header"`
+	RateControlPool     string `json:"rate-control-pool,omitempty"`
+	RateControlStatus   string `json:"rate-control-status,omitempty"`
+	WebFirewallPolicy   string `json:"web-firewall-policy,omitempty"`
+}
+
+// ServiceSSLSecurity : SSL settings of a service.
+type ServiceSSLSecurity struct {
+	Certificate           string   `json:"certificate,omitempty"`
+	Ciphers               string   `json:"ciphers,omitempty"`
+	EcdsaCertificate      string   `json:"ecdsa-certificate"`
+	IncludeHSTSSubDomains string   `json:"include-hsts-sub-domains,omitempty"`
+	HSTSMaxAge            string   `json:"hsts-max-age,omitempty"`
+	SelectedCiphers       []string `json:"selected-ciphers,omitempty"`
+	OverrideCiphersSSL3   []string `json:"override-ciphers-ssl3,omitempty"`
+	OverrideCiphersTLS11  []string `json:"override-ciphers-tls-1-1,omitempty"`
+	OverrideCiphersTLS12  []string `json:"override-ciphers-tls-1-2,omitempty"`
+	OverrideCiphersTLS13  []string `json:"override-ciphers-tls-1-3,omitempty"`
+	OverrideCiphersTLS1   []string `json:"override-ciphers-tls-1,omitempty"`
+	EnablePFS             string   `json:"enable-pfs,omitempty"`
+	EnableSSL3            string   `json:"enable-ssl-3,omitempty"`
+	EnableTLS1            string   `json:"enable-tls-1,omitempty"`
+	EnableTLS11           string   `json:"enable-tls-1-1,omitempty"`
+	EnableTLS12           string   `json:"enable-tls-1-2,omitempty"`
+	EnableTLS13           string   `json:"enable-tls-1-3,omitempty"`
+	EnableHSTS            string   `json:"enable-hsts,omitempty"`
+	EnableOCSPStapling    string   `json:"enable-ocsp-stapling,omitempty"`
+	SNICertificate        []string `json:"sni-certificate,omitempty"`
+	Domain                []string `json:"domain,omitempty"`
+	SNIEcdsaCertificate   []string `json:"sni-ecdsa-certificate,omitempty"`
+	EnableSNI             string   `json:"enable-sni,omitempty"`
+	EnableStrictSNICheck  string   `json:"enable-strict-sni-check,omitempty"`
+	Status                string   `json:"status,omitempty"`
+	SSLTLSPresets         string   `json:"ssl-tls-presets,omitempty"`
+}
+
+// ServiceSSLClientAuthentication : client certificate authentication settings of a service. The
+// trusted certificates are always sent, an empty list removes them.
+type ServiceSSLClientAuthentication struct {
+	EnableClientAuthentication string   `json:"enable-client-authentication,omitempty"`
+	EnforceClientCertificate   string   `json:"enforce-client-certificate,omitempty"`
+	TrustedCertificates        []string `json:"trusted-certificates"`
+}
+
+// ServiceInstantSSL : Instant SSL settings of a service.
+type ServiceInstantSSL struct {
+	Status                   string   `json:"status,omitempty"`
+	SharepointRewriteSupport string   `json:"sharepoint-rewrite-support,omitempty"`
+	SecureSiteDomain         []string `json:"secure-site-domain,omitempty"`
+}
+
+// servicesPath : endpoint of the services
+var servicesPath = objectPath("services")
+
+// ListServices : returns the services sorted by name.
+func (c *Client) ListServices(ctx context.Context) ([]Service, error) {
+	var services []Service
+	err := c.listObjects(ctx, servicesPath, &services)
+
+	return services, err
+}
+
+// GetService : returns the named service.
+func (c *Client) GetService(ctx context.Context, name string) (*Service, error) {
+	var service Service
+	if err := c.getObject(ctx, servicesPath, "service", name, &service); err != nil {
+		return nil, err
+	}
+
+	return &service, nil
+}
+
+// CreateService : creates a service.
+func (c *Client) CreateService(ctx context.Context, service *Service) error {
+	return c.Do(ctx, http.MethodPost, servicesPath, service, nil)
+}
+
+// UpdateService : updates the named service. The address version, type, group, vsite,
+// certificate and secure site domains can only be set when the service is created and
+// are not sent.
+func (c *Client) UpdateService(ctx context.Context, name string, service *Service) error {
+	update := *service
+	update.AddressVersion = ""
+	update.Type = ""
+	update.Group = ""
+	update.Vsite = ""
+	update.Certificate = ""
+	update.SecureSiteDomain = nil
+
+	return c.Do(ctx, http.MethodPut, servicesPath+objectPath(name), &update, nil)
+}
+
+// DeleteService : deletes the named service along with its servers and rules.
+func (c *Client) DeleteService(ctx context.Context, name string) error {
+	return c.Do(ctx, http.MethodDelete, servicesPath+objectPath(name), nil, nil)
+}
+
+// GetServiceBasicSecurity : returns the basic security settings of the named service.
+func (c *Client) GetServiceBasicSecurity(ctx context.Context, name string) (*ServiceBasicSecurity, error) {
+	var basicSecurity ServiceBasicSecurity
+	if err := c.getSubResource(ctx, servicesPath, name, "basic-security", &basicSecurity); err != nil {
+		return nil, err
+	}
+
+	return &basicSecurity, nil
+}
+
+// UpdateServiceBasicSecurity : updates the basic security settings of the named service.
+func (c *Client) UpdateServiceBasicSecurity(ctx context.Context, name string, basicSecurity *ServiceBasicSecurity) error {
+	return c.Do(ctx, http.MethodPut, servicesPath+objectPath(name, "basic-security"), basicSecurity, nil)
+}
+
+// GetServiceSSLSecurity : returns the SSL settings of the named service.
+func (c *Client) GetServiceSSLSecurity(ctx context.Context, name string) (*ServiceSSLSecurity, error) {
+	var sslSecurity ServiceSSLSecurity
+	if err := c.getSubResource(ctx, servicesPath, name, "ssl-security", &sslSecurity); err != nil {
+		return nil, err
+	}
+
+	return &sslSecurity, nil
+}
+
+// UpdateServiceSSLSecurity : updates the SSL settings of the named service.
+func (c *Client) UpdateServiceSSLSecurity(ctx context.Context, name string, sslSecurity *ServiceSSLSecurity) error {
+	return c.Do(ctx, http.MethodPut, servicesPath+objectPath(name, "ssl-security"), sslSecurity, nil)
+}
+
+// GetServiceSSLClientAuthentication : returns the client certificate authentication settings of
+// the named service.
+func (c *Client) GetServiceSSLClientAuthentication(ctx context.Context, name string) (*ServiceSSLClientAuthentication, error) {
+	var clientAuthentication ServiceSSLClientAuthentication
+	if err := c.getSubResource(ctx, servicesPath, name, "ssl-client-authentication", &clientAuthentication); err != nil {
+		return nil, err
+	}
+
+	return &clientAuthentication, nil
+}
+
+// UpdateServiceSSLClientAuthentication : updates the client certificate authentication settings
+// of the named service.
+func (c *Client) UpdateServiceSSLClientAuthentication(
+	ctx context.Context,
+	name string,
+	clientAuthentication *ServiceSSLClientAuthentication,
+) error {
+	update := *clientAuthentication
+	update.TrustedCertificates = append([]string{}, clientAuthentication.TrustedCertificates...)
+
+	return c.Do(ctx, http.MethodPut, servicesPath+objectPath(name, "ssl-client-authentication"), &update, nil)
+}
+
+// ModifyServiceSSLClientAuthentication : reads the client certificate authentication settings
+// of the named service, changes them with modify and updates them. The other changes of the
+// service wait for the update, so concurrent modifications are not lost.
+func (c *Client) ModifyServiceSSLClientAuthentication(
+	ctx context.Context,
+	name string,
+	modify func(*ServiceSSLClientAuthentication) error,
+) error {
+	path := servicesPath + objectPath(name, "ssl-client-authentication")
+
+	ctx, unlock, err := c.lockParent(ctx, parentKey(http.MethodPut, path))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	clientAuthentication, err := c.GetServiceSSLClientAuthentication(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if err := modify(clientAuthentication); err != nil {
+		return err
+	}
+
+	return c.UpdateServiceSSLClientAuthentication(ctx, name, clientAuthentication)
+}
+
+// GetServiceInstantSSL : returns the Instant SSL settings of the named service.
+func (c *Client) GetServiceInstantSSL(ctx context.Context, name string) (*ServiceInstantSSL, error) {
+	var instantSSL ServiceInstantSSL
+	if err := c.getSubResource(ctx, servicesPath, name, "instant-ssl", &instantSSL); err != nil {
+		return nil, err
+	}
+
+	return &instantSSL, nil
+}
+
+// UpdateServiceInstantSSL : updates the Instant SSL settings of the named service.
+func (c *Client) UpdateServiceInstantSSL(ctx context.Context, name string, instantSSL *ServiceInstantSSL) error {
+	return c.Do(ctx, http.MethodPut, servicesPath+objectPath(name, "instant-ssl"), instantSSL, nil)
+}
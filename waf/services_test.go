@@ -0,0 +1,185 @@
+package waf
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClient_services(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+
+	service := &Service{
+		Name:             "DemoApp1",
+		IPAddress:        "172.30.1.4",
+		Port:             "80",
+		Type:             "HTTP",
+		AddressVersion:   "IPv4",
+		SecureSiteDomain: []string{"example.com"},
+	}
+
+	if err := client.CreateService(ctx, service); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	if body := server.LastRequest(http.MethodPost, "/services").Body; body["type"] != "HTTP" || body["mask"] != nil || body["comments"] != "" {
+		t.Errorf("create: unexpected payload %v", body)
+	}
+
+	service.Port = "8080"
+	if err := client.UpdateService(ctx, "DemoApp1", service); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	body := server.LastRequest(http.MethodPut, "/services/DemoApp1").Body
+	if body["port"] != "8080" || body["type"] != nil || body["address-version"] != nil || body["secure-site-domain"] != nil {
+		t.Errorf("update: expected the create only parameters not to be sent, got %v", body)
+	}
+
+	got, err := client.GetService(ctx, "DemoApp1")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	expected := &Service{
+		Name:             "DemoApp1",
+		IPAddress:        "172.30.1.4",
+		Port:             "8080",
+		Type:             "HTTP",
+		AddressVersion:   "IPv4",
+		SecureSiteDomain: []string{"example.com"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("get: expected %+v, got %+v", expected, got)
+	}
+
+	if err := client.DeleteService(ctx, "DemoApp1"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	if _, err := client.GetService(ctx, "DemoApp1"); !IsNotFound(err) {
+		t.Errorf("get: expected a not found error, got %v", err)
+	}
+}
+
+func TestClient_ListServices(t *testing.T) {
+	client, server := newTestClient(t)
+
+	server.SetObject("services/DemoApp2", map[string]interface{}{"port": float64(443)})
+	server.SetObject("services/DemoApp1", map[string]interface{}{
+		"port":               float64(80),
+		"secure-site-domain": "example.com",
+		"Service Group":      map[string]interface{}{"group": "default", "port": "ignored"},
+	})
+
+	services, err := client.ListServices(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []Service{
+		{Name: "DemoApp1", Port: "80", Group: "default", SecureSiteDomain: []string{"example.com"}},
+		{Name: "DemoApp2", Port: "443"},
+	}
+	if !reflect.DeepEqual(services, expected) {
+		t.Errorf("expected %+v, got %+v", expected, services)
+	}
+}
+
+func TestClient_serviceSubResources(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+
+	server.SetObject("services/DemoApp1", nil)
+
+	if err := client.UpdateServiceBasicSecurity(ctx, "DemoApp1", &ServiceBasicSecurity{Mode: "Active"}); err != nil {
+		t.Fatalf("basic security: %v", err)
+	}
+
+	sslSecurity := &ServiceSSLSecurity{Status: "On", SelectedCiphers: []string{"ECDHE-RSA-AES128-GCM-SHA256"}}
+	if err := client.UpdateServiceSSLSecurity(ctx, "DemoApp1", sslSecurity); err != nil {
+		t.Fatalf("ssl security: %v", err)
+	}
+
+	if basicSecurity, err := client.GetServiceBasicSecurity(ctx, "DemoApp1"); err != nil || basicSecurity.Mode != "Active" {
+		t.Errorf("basic security: unexpected result %+v (%v)", basicSecurity, err)
+	}
+
+	if got, err := client.GetServiceSSLSecurity(ctx, "DemoApp1"); err != nil || !reflect.DeepEqual(got, sslSecurity) {
+		t.Errorf("ssl security: expected %+v, got %+v (%v)", sslSecurity, got, err)
+	}
+
+	if instantSSL, err := client.GetServiceInstantSSL(ctx, "DemoApp1"); err != nil || !reflect.DeepEqual(instantSSL, &ServiceInstantSSL{}) {
+		t.Errorf("instant ssl: expected the settings to be empty, got %+v (%v)", instantSSL, err)
+	}
+}
+
+func TestClient_serviceSSLClientAuthentication(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+
+	server.SetObject("services/DemoApp1", nil)
+
+	clientAuthentication := &ServiceSSLClientAuthentication{
+		EnableClientAuthentication: "Yes",
+		TrustedCertificates:        []string{"DemoClientCA-1a2b3c4d"},
+	}
+	if err := client.UpdateServiceSSLClientAuthentication(ctx, "DemoApp1", clientAuthentication); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	if got, err := client.GetServiceSSLClientAuthentication(ctx, "DemoApp1"); err != nil || !reflect.DeepEqual(got, clientAuthentication) {
+		t.Errorf("get: expected %+v, got %+v (%v)", clientAuthentication, got, err)
+	}
+
+	if err := client.UpdateServiceSSLClientAuthentication(ctx, "DemoApp1", &ServiceSSLClientAuthentication{}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	body := server.LastRequest(http.MethodPut, "/services/DemoApp1/ssl-client-authentication").Body
+	if !reflect.DeepEqual(body, map[string]interface{}{"trusted-certificates": []interface{}{}}) {
+		t.Errorf("update: expected the trusted certificates to be removed, got %v", body)
+	}
+}
+
+func TestClient_ModifyServiceSSLClientAuthentication(t *testing.T) {
+	client, server := newTestClient(t)
+	ctx := context.Background()
+
+	server.SetObject("services/DemoApp1", nil)
+
+	names := []string{"DemoClientCA-1", "DemoClientCA-2", "DemoClientCA-3", "DemoClientCA-4"}
+
+	var wg sync.WaitGroup
+	for _, name := range names {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			err := client.ModifyServiceSSLClientAuthentication(ctx, "DemoApp1", func(clientAuthentication *ServiceSSLClientAuthentication) error {
+				// leaves time to the other modifications to read the same settings
+				time.Sleep(5 * time.Millisecond)
+				clientAuthentication.TrustedCertificates = append(clientAuthentication.TrustedCertificates, name)
+				return nil
+			})
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", name, err)
+			}
+		}(name)
+	}
+	wg.Wait()
+
+	got, err := client.GetServiceSSLClientAuthentication(ctx, "DemoApp1")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	sort.Strings(got.TrustedCertificates)
+	if !reflect.DeepEqual(got.TrustedCertificates, names) {
+		t.Errorf("expected every modification to be kept, got %v", got.TrustedCertificates)
+	}
+}
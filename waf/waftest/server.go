@@ -0,0 +1,556 @@
+// Package waftest provides an in-process fake of the Barracuda WAF REST API (v3.1) for tests.
+//
+// The fake serves the login, services, servers, content rules, content rule servers, security
+// policies and certificates endpoints from an in-memory store. Objects are addressed by their
+// path relative to the API base without the leading slash, e.g. "services/DemoApp1/servers/DemoServer1".
+package waftest
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	b64 "encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const (
+	Username = "admin"  // Username : user accepted by the login endpoint
+	Password = "secret" // Password : password accepted by the login endpoint
+
+	baseURI = "/restapi/v3.1" // baseURI : base endpoint of the REST API
+)
+
+var (
+	// collections : collections served by the fake WAF
+	collections = map[string]bool{
+		"services":                   true,
+		"servers":                    true,
+		"content-rules":              true,
+		"content-rule-servers":       true,
+		"security-policies":          true,
+		"signed-certificate":         true,
+		"self-signed-certificate":    true,
+		"certificate-request":        true,
+		"trusted-ca-certificate":     true,
+		"trusted-server-certificate": true,
+	}
+
+	// collectionAliases : collections creating objects in another collection
+	collectionAliases = map[string]string{
+		"certificates/letsencrypt": "signed-certificate",
+	}
+)
+
+// Request : request received by the fake WAF.
+type Request struct {
+	Method string
+	Path   string // path relative to the API base, e.g. "/services/DemoApp1"
+	Body   map[string]interface{}
+}
+
+// failure : response returned instead of handling the next matching request.
+type failure struct {
+	method string
+	status int
+	body   string
+}
+
+// Server : in-process fake of the Barracuda WAF REST API backed by an in-memory store.
+type Server struct {
+	URL string // base URL of the fake WAF, e.g. "http://127.0.0.1:36109"
+
+	t      testing.TB
+	server *httptest.Server
+
+	mutex        sync.Mutex
+	tokens       map[string]bool
+	logins       int
+	objects      map[string]map[string]interface{}
+	subResources map[string]map[string]interface{}
+	requests     []Request
+	failures     []failure
+}
+
+// NewServer : starts a fake WAF, it is stopped when the test completes.
+func NewServer(t testing.TB) *Server {
+	return newServer(t, httptest.NewServer)
+}
+
+// NewTLSServer : starts a fake WAF serving HTTPS with the self-signed certificate returned by
+// Certificate, it is stopped when the test completes.
+func NewTLSServer(t testing.TB) *Server {
+	return newServer(t, httptest.NewTLSServer)
+}
+
+func newServer(t testing.TB, start func(http.Handler) *httptest.Server) *Server {
+	s := &Server{
+		t:            t,
+		tokens:       make(map[string]bool),
+		objects:      make(map[string]map[string]interface{}),
+		subResources: make(map[string]map[string]interface{}),
+	}
+
+	s.server = start(http.HandlerFunc(s.handle))
+	s.URL = s.server.URL
+	t.Cleanup(s.server.Close)
+
+	return s
+}
+
+// Certificate : returns the certificate of a fake WAF started with NewTLSServer.
+func (s *Server) Certificate() *x509.Certificate {
+	return s.server.Certificate()
+}
+
+// IssueToken : returns a new token accepted by the fake WAF, as generated for API access.
+func (s *Server) IssueToken() string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.issueToken()
+}
+
+func (s *Server) issueToken() string {
+	token := fmt.Sprintf("token-%d", len(s.tokens)+1)
+	s.tokens[token] = true
+
+	return token
+}
+
+// ExpireSessions : invalidates every token issued so far.
+func (s *Server) ExpireSessions() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for token := range s.tokens {
+		s.tokens[token] = false
+	}
+}
+
+// Logins : returns the number of successful logins.
+func (s *Server) Logins() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.logins
+}
+
+// SetObject : stores an object, its name is the last segment of the path.
+func (s *Server) SetObject(path string, params map[string]interface{}) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	object := map[string]interface{}{"name": path[strings.LastIndex(path, "/")+1:]}
+	for key, val := range params {
+		object[key] = val
+	}
+
+	s.objects[path] = object
+}
+
+// SetSubResource : stores a sub resource of an object, e.g. SetSubResource("services/DemoApp1",
+// "basic-security", map[string]interface{}{"mode": "Active"}).
+func (s *Server) SetSubResource(path string, subResource string, params map[string]interface{}) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.subResources[path+"/"+subResource] = params
+}
+
+// Object : returns the stored object, or nil.
+func (s *Server) Object(path string) map[string]interface{} {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.objects[path]
+}
+
+// SubResource : returns the stored sub resource of an object, or nil.
+func (s *Server) SubResource(path string, subResource string) map[string]interface{} {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.subResources[path+"/"+subResource]
+}
+
+// FailNext : fails the next count requests with the given method, or any method when empty.
+func (s *Server) FailNext(method string, status int, message string, count int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	body, _ := json.Marshal(map[string]string{"msg": message})
+	for i := 0; i < count; i++ {
+		s.failures = append(s.failures, failure{method: method, status: status, body: string(body)})
+	}
+}
+
+// RejectNext : fails the next request with the given method with a validation error of
+// the parameters, e.g. RejectNext("PUT", map[string]string{"port": "must be a number"}).
+func (s *Server) RejectNext(method string, fields map[string]string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	body, _ := json.Marshal(map[string]interface{}{"msg": "Validation failed", "errors": fields})
+	s.failures = append(s.failures, failure{method: method, status: http.StatusBadRequest, body: string(body)})
+}
+
+// Requests : returns the requests received with the given method and path prefix.
+func (s *Server) Requests(method string, pathPrefix string) []Request {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var requests []Request
+	for _, request := range s.requests {
+		if request.Method == method && strings.HasPrefix(request.Path, pathPrefix) {
+			requests = append(requests, request)
+		}
+	}
+
+	return requests
+}
+
+// LastRequest : returns the last request received with the given method and path, the test
+// fails when there is none.
+func (s *Server) LastRequest(method string, path string) Request {
+	s.t.Helper()
+
+	requests := s.Requests(method, path)
+	for i := len(requests) - 1; i >= 0; i-- {
+		if requests[i].Path == path {
+			return requests[i]
+		}
+	}
+
+	s.t.Fatalf("expected a %s request to %s", method, path)
+	return Request{}
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, baseURI)
+
+	var body map[string]interface{}
+	if data, _ := ioutil.ReadAll(r.Body); len(data) > 0 {
+		if err := json.Unmarshal(data, &body); err != nil {
+			s.respond(w, http.StatusBadRequest, map[string]interface{}{"msg": "Invalid JSON: " + err.Error()})
+			return
+		}
+	}
+
+	s.requests = append(s.requests, Request{Method: r.Method, Path: path, Body: body})
+
+	for i, failure := range s.failures {
+		if failure.method == "" || failure.method == r.Method {
+			s.failures = append(s.failures[:i], s.failures[i+1:]...)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(failure.status)
+			fmt.Fprint(w, failure.body)
+			return
+		}
+	}
+
+	if path == "/login" {
+		s.login(w, body)
+		return
+	}
+
+	if !s.authorized(r.Header.Get("Authorization")) {
+		s.respond(w, http.StatusUnauthorized, map[string]interface{}{"msg": "Token expired or invalid"})
+		return
+	}
+
+	kind, objectPath, subResource := parse(path)
+
+	switch {
+	case kind == "collection" && r.Method == http.MethodGet:
+		s.list(w, objectPath)
+	case kind == "collection" && r.Method == http.MethodPost:
+		s.create(w, objectPath, body)
+	case kind == "object" && r.Method == http.MethodGet:
+		s.read(w, objectPath)
+	case kind == "object" && r.Method == http.MethodPut:
+		s.update(w, objectPath, body)
+	case kind == "object" && r.Method == http.MethodDelete:
+		s.delete(w, objectPath)
+	case kind == "sub-resource" && r.Method == http.MethodGet:
+		s.readSubResource(w, objectPath, subResource)
+	case kind == "sub-resource" && r.Method == http.MethodPut:
+		s.updateSubResource(w, objectPath, subResource, body)
+	default:
+		s.respond(w, http.StatusMethodNotAllowed, map[string]interface{}{"msg": "Unsupported request " + r.Method + " " + path})
+	}
+}
+
+// authorized : reports whether the Authorization header holds a valid token, encoded as
+// the user name of the basic authentication.
+func (s *Server) authorized(header string) bool {
+	for token, valid := range s.tokens {
+		if valid && header == "BASIC "+b64.StdEncoding.EncodeToString([]byte(token+":")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parse : splits a request path into the kind of the addressed entity, the path of the
+// collection or object and the name of the sub resource.
+func parse(path string) (string, string, string) {
+	path = strings.Trim(path, "/")
+	for alias, collection := range collectionAliases {
+		if path == alias || strings.HasPrefix(path, alias+"/") {
+			path = collection + strings.TrimPrefix(path, alias)
+		}
+	}
+
+	segments := strings.Split(path, "/")
+	last := segments[len(segments)-1]
+
+	switch {
+	case len(segments)%2 == 0:
+		return "object", path, ""
+	case collections[last]:
+		return "collection", path, ""
+	default:
+		return "sub-resource", strings.Join(segments[:len(segments)-1], "/"), last
+	}
+}
+
+func (s *Server) login(w http.ResponseWriter, body map[string]interface{}) {
+	if body["username"] != Username || body["password"] != Password {
+		s.respond(w, http.StatusUnauthorized, map[string]interface{}{"msg": "Login failed"})
+		return
+	}
+
+	s.logins++
+	s.respond(w, http.StatusOK, map[string]interface{}{"token": s.issueToken()})
+}
+
+func (s *Server) parentExists(path string) bool {
+	index := strings.LastIndex(path, "/")
+	if index < 0 {
+		return true
+	}
+
+	_, ok := s.objects[path[:index]]
+	return ok
+}
+
+func (s *Server) list(w http.ResponseWriter, collection string) {
+	if !s.parentExists(collection) {
+		s.respond(w, http.StatusNotFound, map[string]interface{}{"msg": "Parent of " + collection + " does not exist"})
+		return
+	}
+
+	data := make(map[string]interface{})
+	for path, object := range s.objects {
+		if strings.HasPrefix(path, collection+"/") && !strings.Contains(strings.TrimPrefix(path, collection+"/"), "/") {
+			data[object["name"].(string)] = object
+		}
+	}
+
+	s.respond(w, http.StatusOK, map[string]interface{}{"object": collection, "data": data})
+}
+
+func (s *Server) create(w http.ResponseWriter, collection string, body map[string]interface{}) {
+	name, _ := body["name"].(string)
+	path := collection + "/" + name
+
+	switch {
+	case name == "":
+		s.respond(w, http.StatusBadRequest, map[string]interface{}{"msg": "name is required"})
+	case !s.parentExists(collection):
+		s.respond(w, http.StatusNotFound, map[string]interface{}{"msg": "Parent of " + collection + " does not exist"})
+	case s.objects[path] != nil:
+		s.respond(w, http.StatusConflict, map[string]interface{}{"msg": name + " already exists"})
+	default:
+		if collection == "certificate-request" {
+			if err := generateCertificateRequest(body); err != nil {
+				s.respond(w, http.StatusInternalServerError, map[string]interface{}{"msg": err.Error()})
+				return
+			}
+		}
+
+		if collection == "signed-certificate" && body["assign-associated-key"] == "Yes" {
+			s.consumeCertificateRequest(body)
+		}
+
+		s.objects[path] = body
+		s.respond(w, http.StatusCreated, map[string]interface{}{"msg": "Configuration updated", "id": name})
+	}
+}
+
+// generateCertificateRequest : generates the pending key and the PEM certificate request of the
+// common name, as the WAF does when a certificate request is created.
+func generateCertificateRequest(body map[string]interface{}) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	commonName, _ := body["common-name"].(string)
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: commonName},
+	}, key)
+	if err != nil {
+		return err
+	}
+
+	body["certificate-request"] = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}))
+	return nil
+}
+
+// consumeCertificateRequest : clears the certificate request of the pending key the uploaded
+// certificate is signed for, as the WAF does once the key is assigned to a certificate.
+func (s *Server) consumeCertificateRequest(body map[string]interface{}) {
+	content, _ := body["signed-certificate"].(string)
+	block := decodePEM(content)
+	if block == nil {
+		return
+	}
+
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return
+	}
+
+	for path, object := range s.objects {
+		if !strings.HasPrefix(path, "certificate-request/") {
+			continue
+		}
+
+		request, _ := object["certificate-request"].(string)
+		if block = decodePEM(request); block == nil {
+			continue
+		}
+
+		parsed, err := x509.ParseCertificateRequest(block.Bytes)
+		if err != nil {
+			continue
+		}
+
+		if key, ok := parsed.PublicKey.(interface{ Equal(crypto.PublicKey) bool }); ok && key.Equal(certificate.PublicKey) {
+			object["certificate-request"] = ""
+		}
+	}
+}
+
+// decodePEM : returns the first block of PEM content, which may be base64 encoded.
+func decodePEM(content string) *pem.Block {
+	if decoded, err := b64.StdEncoding.DecodeString(content); err == nil {
+		content = string(decoded)
+	}
+
+	block, _ := pem.Decode([]byte(content))
+	return block
+}
+
+func (s *Server) read(w http.ResponseWriter, path string) {
+	object, ok := s.objects[path]
+	if !ok {
+		s.respond(w, http.StatusNotFound, map[string]interface{}{"msg": path + " does not exist"})
+		return
+	}
+
+	s.respond(w, http.StatusOK, map[string]interface{}{"data": map[string]interface{}{object["name"].(string): object}})
+}
+
+func (s *Server) update(w http.ResponseWriter, path string, body map[string]interface{}) {
+	object, ok := s.objects[path]
+	if !ok {
+		s.respond(w, http.StatusNotFound, map[string]interface{}{"msg": path + " does not exist"})
+		return
+	}
+
+	for key, val := range body {
+		object[key] = val
+	}
+
+	// renaming moves the object along with its children and sub resources
+	if name, _ := body["name"].(string); name != "" && !strings.HasSuffix(path, "/"+name) {
+		renamed := path[:strings.LastIndex(path, "/")+1] + name
+		for _, store := range []map[string]map[string]interface{}{s.objects, s.subResources} {
+			for key, val := range store {
+				if key == path || strings.HasPrefix(key, path+"/") {
+					delete(store, key)
+					store[renamed+strings.TrimPrefix(key, path)] = val
+				}
+			}
+		}
+	}
+
+	s.respond(w, http.StatusOK, map[string]interface{}{"msg": "Configuration updated", "id": object["name"]})
+}
+
+func (s *Server) delete(w http.ResponseWriter, path string) {
+	if _, ok := s.objects[path]; !ok {
+		s.respond(w, http.StatusNotFound, map[string]interface{}{"msg": path + " does not exist"})
+		return
+	}
+
+	for _, store := range []map[string]map[string]interface{}{s.objects, s.subResources} {
+		for key := range store {
+			if key == path || strings.HasPrefix(key, path+"/") {
+				delete(store, key)
+			}
+		}
+	}
+
+	s.respond(w, http.StatusOK, map[string]interface{}{"msg": "Configuration updated"})
+}
+
+func (s *Server) readSubResource(w http.ResponseWriter, path string, subResource string) {
+	object, ok := s.objects[path]
+	if !ok {
+		s.respond(w, http.StatusNotFound, map[string]interface{}{"msg": path + " does not exist"})
+		return
+	}
+
+	params := s.subResources[path+"/"+subResource]
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+
+	s.respond(w, http.StatusOK, map[string]interface{}{"data": map[string]interface{}{object["name"].(string): params}})
+}
+
+func (s *Server) updateSubResource(w http.ResponseWriter, path string, subResource string, body map[string]interface{}) {
+	if _, ok := s.objects[path]; !ok {
+		s.respond(w, http.StatusNotFound, map[string]interface{}{"msg": path + " does not exist"})
+		return
+	}
+
+	params := s.subResources[path+"/"+subResource]
+	if params == nil {
+		params = make(map[string]interface{})
+		s.subResources[path+"/"+subResource] = params
+	}
+
+	for key, val := range body {
+		params[key] = val
+	}
+
+	s.respond(w, http.StatusOK, map[string]interface{}{"msg": "Configuration updated"})
+}
+
+func (s *Server) respond(w http.ResponseWriter, status int, body map[string]interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		s.t.Errorf("unable to encode the fake WAF response: %v", err)
+	}
+}